@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"asset_backend/db"
+	"asset_backend/models"
+	"asset_backend/requests"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	Database *db.MongoDB
+}
+
+func NewAdminController(mongoDB *db.MongoDB) AdminController {
+	return AdminController{
+		Database: mongoDB,
+	}
+}
+
+var (
+	errBackfillDateRange       = "End date can't be before start date and the range can be up to 366 days."
+	errCorporateActionSymbol   = "New symbol must be different from the symbol."
+	errCorporateActionNotFound = "Corporate action not found."
+)
+
+const backfillMaxDays = 366
+
+// Backfill Daily Asset Stats
+// @Summary Backfill Daily Asset Stats
+// @Description Reconstructs missing daily asset stats from asset logs, existing days are kept
+// @Tags admin
+// @Accept application/json
+// @Produce application/json
+// @Param dailyassetstatsbackfill body requests.DailyAssetStatsBackfill true "Daily Asset Stats Backfill"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} responses.DailyAssetStatsBackfill
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Router /admin/daily-asset-stats/backfill [post]
+func (a *AdminController) BackfillDailyAssetStats(c *gin.Context) {
+	var data requests.DailyAssetStatsBackfill
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	endDate := data.StartDate.AddDate(0, 0, backfillMaxDays-1)
+	if data.EndDate != nil {
+		endDate = *data.EndDate
+	}
+
+	if endDate.Before(data.StartDate) || endDate.Sub(data.StartDate).Hours()/24 >= backfillMaxDays {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errBackfillDateRange,
+		})
+
+		return
+	}
+
+	dasModel := models.NewDailyAssetStatsModel(a.Database)
+
+	backfill, err := dasModel.BackfillDailyAssetStats(data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully backfilled.", "data": backfill})
+}
+
+// Create Corporate Action
+// @Summary Create Corporate Action
+// @Description Registers a stock split or symbol change, asset logs created before the effective date are adjusted while aggregating
+// @Tags admin
+// @Accept application/json
+// @Produce application/json
+// @Param corporateactioncreate body requests.CorporateActionCreate true "Corporate Action Create"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 201 {object} models.CorporateAction
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Router /admin/corporate-action [post]
+func (a *AdminController) CreateCorporateAction(c *gin.Context) {
+	var data requests.CorporateActionCreate
+	if shouldReturn := bindCorporateActionData(&data, c); shouldReturn {
+		return
+	}
+
+	caModel := models.NewCorporateActionModel(a.Database)
+
+	corporateAction, err := caModel.CreateCorporateAction(data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Corporate action successfully created.", "data": corporateAction})
+}
+
+// Preview Corporate Action
+// @Summary Preview Corporate Action
+// @Description Returns the users and asset logs that would be adjusted by the corporate action without creating it
+// @Tags admin
+// @Accept application/json
+// @Produce application/json
+// @Param corporateactioncreate body requests.CorporateActionCreate true "Corporate Action Create"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {object} responses.CorporateActionPreview
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Router /admin/corporate-action/preview [post]
+func (a *AdminController) PreviewCorporateAction(c *gin.Context) {
+	var data requests.CorporateActionCreate
+	if shouldReturn := bindCorporateActionData(&data, c); shouldReturn {
+		return
+	}
+
+	caModel := models.NewCorporateActionModel(a.Database)
+
+	preview, err := caModel.GetCorporateActionPreview(data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": preview})
+}
+
+// Corporate Actions
+// @Summary Get Corporate Actions
+// @Description Returns all corporate actions
+// @Tags admin
+// @Accept application/json
+// @Produce application/json
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {array} models.CorporateAction
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Router /admin/corporate-action [get]
+func (a *AdminController) GetCorporateActions(c *gin.Context) {
+	caModel := models.NewCorporateActionModel(a.Database)
+
+	corporateActions, err := caModel.GetCorporateActions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": corporateActions})
+}
+
+// Delete Corporate Action
+// @Summary Delete Corporate Action
+// @Description Deletes corporate action, chained symbol changes aren't reverted
+// @Tags admin
+// @Accept application/json
+// @Produce application/json
+// @Param ID body requests.ID true "Corporate Action ID"
+// @Param X-Admin-Key header string true "Admin key"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Failure 401 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Router /admin/corporate-action [delete]
+func (a *AdminController) DeleteCorporateActionByID(c *gin.Context) {
+	var data requests.ID
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	caModel := models.NewCorporateActionModel(a.Database)
+
+	isDeleted, err := caModel.DeleteCorporateActionByID(data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if !isDeleted {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": errCorporateActionNotFound,
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Corporate action deleted successfully."})
+}
+
+func bindCorporateActionData(data *requests.CorporateActionCreate, c *gin.Context) bool {
+	if shouldReturn := bindJSONData(data, c); shouldReturn {
+		return true
+	}
+
+	if data.ActionType == models.CorporateActionSymbolChange && data.NewSymbol == data.Symbol {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errCorporateActionSymbol,
+		})
+
+		return true
+	}
+
+	return false
+}
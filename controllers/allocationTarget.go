@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"asset_backend/models"
+	"asset_backend/requests"
+	"math"
+	"net/http"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errAllocationTotal    = "Allocation targets must add up to 100."
+	errAllocationKey      = "Allocation targets must be unique and asset type targets must be one of crypto, stock, exchange, commodity or custom."
+	errAllocationNotFound = "You don't have allocation targets yet."
+)
+
+var allocationAssetTypes = map[string]bool{"crypto": true, "stock": true, "exchange": true, "commodity": true, "custom": true}
+
+// Allocation Targets
+// @Summary Get Allocation Targets
+// @Description Returns target allocation of user
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} models.AllocationTarget
+// @Failure 404 {string} string
+// @Router /asset/allocation [get]
+func (a *AssetController) GetAllocationTarget(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	allocationModel := models.NewAllocationTargetModel(a.Database)
+
+	allocationTarget, err := allocationModel.GetAllocationTargetByUserID(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": errAllocationNotFound,
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": allocationTarget})
+}
+
+// Update Allocation Targets
+// @Summary Set Allocation Targets
+// @Description Replaces target allocation of user. Targets are asset types or to_asset symbols and must add up to 100.
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Param allocationtarget body requests.AllocationTargetUpdate true "Allocation Target Update"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} models.AllocationTarget
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /asset/allocation [put]
+func (a *AssetController) UpdateAllocationTarget(c *gin.Context) {
+	var data requests.AllocationTargetUpdate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	const totalEpsilon = 0.01
+
+	var (
+		total float64
+		keys  = make(map[string]bool)
+	)
+
+	for _, target := range data.Targets {
+		if keys[target.Key] || (data.TargetType == models.AllocationTargetAssetType && !allocationAssetTypes[target.Key]) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": errAllocationKey,
+			})
+
+			return
+		}
+
+		keys[target.Key] = true
+		total += target.Percentage
+	}
+
+	if math.Abs(total-100) > totalEpsilon {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errAllocationTotal,
+		})
+
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	allocationModel := models.NewAllocationTargetModel(a.Database)
+
+	allocationTarget, err := allocationModel.UpdateAllocationTarget(uid, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Allocation targets updated.", "data": allocationTarget})
+}
+
+// Asset Rebalance
+// @Summary Get Rebalancing Suggestions
+// @Description Compares current values with allocation targets and returns buy/sell values in user's currency. Drifts within tolerance and trades smaller than min trade size are held.
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.AssetRebalance
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Router /asset/rebalance [get]
+func (a *AssetController) GetAssetRebalance(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	allocationModel := models.NewAllocationTargetModel(a.Database)
+
+	allocationTarget, err := allocationModel.GetAllocationTargetByUserID(uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": errAllocationNotFound,
+		})
+
+		return
+	}
+
+	userModel := models.NewUserModel(a.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	assetModel := models.NewAssetModel(a.Database)
+
+	assets, err := assetModel.GetAssetsByUserID(uid, user.CostBasisMethod, requests.AssetSortFilter{
+		Sort:     "name",
+		SortType: 1,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	rebalance, err := allocationModel.GetAssetRebalance(allocationTarget, assets, user.Currency)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": rebalance})
+}
+
+// Delete Allocation Targets
+// @Summary Delete Allocation Targets
+// @Description Deletes target allocation of user
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {string} string
+// @Failure 500 {string} string
+// @Router /asset/allocation [delete]
+func (a *AssetController) DeleteAllocationTarget(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	allocationModel := models.NewAllocationTargetModel(a.Database)
+
+	if err := allocationModel.DeleteAllocationTargetByUserID(uid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Allocation targets deleted successfully."})
+}
@@ -2,14 +2,19 @@ package controllers
 
 import (
 	"asset_backend/db"
+	"asset_backend/entitlements"
+	"asset_backend/helpers"
 	"asset_backend/models"
 	"asset_backend/requests"
 	"asset_backend/responses"
 	"context"
+	"fmt"
 	"net/http"
+	"sort"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -24,8 +29,11 @@ func NewAssetController(mongoDB *db.MongoDB) AssetController {
 }
 
 var (
-	errAssetNotFound = "Asset not found."
-	errAssetPremium  = "Free members can add up to 10, you can get premium membership for unlimited access."
+	errAssetNotFound         = "Asset not found."
+	errAssetImportFile       = "Couldn't read the uploaded CSV file."
+	errAssetImportFileSize   = fmt.Sprintf("CSV files can be up to %d MB.", helpers.AssetImportMaxFileSize>>20)
+	errAssetImportSymbol     = "Couldn't find %s in %s investings of %s market."
+	errAssetPerformanceRange = "End date can't be before start date."
 )
 
 // Create Asset
@@ -49,18 +57,7 @@ func (a *AssetController) CreateAsset(c *gin.Context) {
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
 
-	userModel := models.NewUserModel(a.Database)
-	isPremium := userModel.IsUserPremium(uid)
-
 	assetModel := models.NewAssetModel(a.Database)
-	if !isPremium && assetModel.GetUserAssetCount(uid) >= 10 {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errAssetPremium,
-		})
-
-		return
-	}
-
 	if err := assetModel.CreateAsset(uid, data); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -104,6 +101,189 @@ func (a *AssetController) CreateAssetLog(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"message": "Successfully created."})
 }
 
+// Import Assets
+// @Summary Import Asset Trade History
+// @Description Imports asset logs from a CSV export of up to 5 MB and 5000 rows. Rows are validated one by one and only valid rows are inserted.
+// @Tags asset
+// @Accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "CSV File"
+// @Param assetimport formData requests.AssetImport true "Asset Import"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.AssetImport "Dry run result"
+// @Success 201 {object} responses.AssetImport
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /asset/import [post]
+func (a *AssetController) ImportAssets(c *gin.Context) {
+	// Multipart body has the form fields besides the file.
+	maxBodySize := int64(helpers.AssetImportMaxFileSize + 1<<20)
+	if c.Request.ContentLength > maxBodySize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errAssetImportFileSize,
+		})
+
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize)
+
+	var data requests.AssetImport
+	if err := c.ShouldBind(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validatorErrorHandler(err),
+		})
+
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errAssetImportFile,
+		})
+
+		return
+	}
+
+	if fileHeader.Size > helpers.AssetImportMaxFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errAssetImportFileSize,
+		})
+
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errAssetImportFile,
+		})
+
+		return
+	}
+	defer file.Close()
+
+	rows, rowErrors, err := helpers.ParseAssetImportCSV(file, data.Profile, data.AssetMarket)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	totalRows := len(rows) + len(rowErrors)
+
+	var validatedRows []requests.AssetImportRow
+	for _, row := range rows {
+		if err := binding.Validator.ValidateStruct(&row.AssetCreate); err != nil {
+			rowErrors = append(rowErrors, responses.AssetImportError{
+				Row:   row.Row,
+				Error: validatorErrorHandler(err),
+			})
+
+			continue
+		}
+
+		validatedRows = append(validatedRows, row)
+	}
+
+	investingIDSet := make(map[models.InvestingID]bool)
+	for _, row := range validatedRows {
+		investingIDSet[models.InvestingID{Symbol: row.ToAsset, Type: row.AssetType, Market: row.AssetMarket}] = true
+	}
+
+	investingIDs := make([]models.InvestingID, 0, len(investingIDSet))
+	for investingID := range investingIDSet {
+		investingIDs = append(investingIDs, investingID)
+	}
+
+	investingModel := models.NewInvestingModel(a.Database)
+
+	existingInvestingIDs, err := investingModel.GetExistingInvestingIDs(investingIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	assetModel := models.NewAssetModel(a.Database)
+	quotaService := entitlements.NewQuotaService(a.Database)
+
+	plan := quotaService.GetUserPlan(uid)
+
+	assetPairs, err := assetModel.GetUserAssetPairs(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	var validRows []requests.AssetImportRow
+	for _, row := range validatedRows {
+		if row.AssetType != models.AssetTypeCustom &&
+			!existingInvestingIDs[models.InvestingID{Symbol: row.ToAsset, Type: row.AssetType, Market: row.AssetMarket}] {
+			rowErrors = append(rowErrors, responses.AssetImportError{
+				Row:   row.Row,
+				Error: fmt.Sprintf(errAssetImportSymbol, row.ToAsset, row.AssetType, row.AssetMarket),
+			})
+
+			continue
+		}
+
+		pairKey := models.GetAssetPairKey(row.ToAsset, row.FromAsset)
+		if !assetPairs[pairKey] {
+			if plan.IsLimitReached(entitlements.ResourceAsset, int64(len(assetPairs))) {
+				rowErrors = append(rowErrors, responses.AssetImportError{
+					Row:   row.Row,
+					Error: entitlements.GetQuotaError(plan, entitlements.ResourceAsset),
+				})
+
+				continue
+			}
+
+			assetPairs[pairKey] = true
+		}
+
+		validRows = append(validRows, row)
+	}
+
+	sort.Slice(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Row < rowErrors[j].Row
+	})
+
+	assetImport := responses.AssetImport{
+		DryRun:    data.DryRun,
+		TotalRows: totalRows,
+		ValidRows: len(validRows),
+		Errors:    rowErrors,
+	}
+
+	if data.DryRun || len(validRows) == 0 {
+		c.JSON(http.StatusOK, gin.H{"message": "Successfully validated.", "data": assetImport})
+		return
+	}
+
+	assetImport.InsertedRows, err = assetModel.CreateAssets(uid, validRows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+			"data":  assetImport,
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Successfully imported.", "data": assetImport})
+}
+
 // Assets & Stats by User ID
 // @Summary Get Assets & Stats by User ID
 // @Description Returns assets and stats by user id
@@ -129,17 +309,26 @@ func (a *AssetController) GetAssetsAndStatsByUserID(c *gin.Context) {
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
 	assetModel := models.NewAssetModel(a.Database)
+	userModel := models.NewUserModel(a.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
 
 	var (
 		assetAndStats responses.AssetAndStats
 		assets        []responses.Asset
 		assetStat     responses.AssetStats
-		err           error
 		errs, _       = errgroup.WithContext(context.TODO())
 	)
 
 	errs.Go(func() error {
-		assets, err = assetModel.GetAssetsByUserID(uid, data)
+		assets, err = assetModel.GetAssetsByUserID(uid, user.CostBasisMethod, data)
 		return err
 	})
 
@@ -190,8 +379,20 @@ func (a *AssetController) GetAssetStatsByAssetAndUserID(c *gin.Context) {
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
 	assetModel := models.NewAssetModel(a.Database)
+	userModel := models.NewUserModel(a.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
 
-	assetDetails, err := assetModel.GetAssetStatsByAssetAndUserID(uid, data.ToAsset, data.FromAsset, data.AssetMarket)
+		return
+	}
+
+	assetDetails, err := assetModel.GetAssetStatsByAssetAndUserID(
+		uid, user.CostBasisMethod, data.ToAsset, data.FromAsset, data.AssetMarket,
+	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -231,6 +432,112 @@ func (a *AssetController) GetAllAssetStatsByUserID(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": assetStat})
 }
 
+// Asset Tax Report
+// @Summary Get Capital Gains Tax Report by User ID
+// @Description Returns per disposal capital gains and yearly totals in user's currency, cost basis is converted at the acquisition date rate and proceeds at the disposal date rate
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Param assettaxreport query requests.AssetTaxReport true "Asset Tax Report"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.AssetTaxReport
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /asset/tax-report [get]
+func (a *AssetController) GetTaxReportByUserID(c *gin.Context) {
+	var data requests.AssetTaxReport
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validatorErrorHandler(err),
+		})
+
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(a.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	assetModel := models.NewAssetModel(a.Database)
+
+	taxReport, err := assetModel.GetTaxReportByUserID(uid, user.CostBasisMethod, user.Currency, data.Year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": taxReport})
+}
+
+// Asset Performance
+// @Summary Get Asset Performance by User ID
+// @Description Returns time-weighted return and XIRR per holding, per asset type and for the portfolio in user's currency. Range defaults to first asset log until today.
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Param assetperformance query requests.AssetPerformance false "Asset Performance"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.AssetPerformance
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /asset/performance [get]
+func (a *AssetController) GetAssetPerformanceByUserID(c *gin.Context) {
+	var data requests.AssetPerformance
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validatorErrorHandler(err),
+		})
+
+		return
+	}
+
+	if data.StartDate != nil && data.EndDate != nil && data.EndDate.Before(*data.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errAssetPerformanceRange,
+		})
+
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(a.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	assetModel := models.NewAssetModel(a.Database)
+
+	performance, err := assetModel.GetAssetPerformanceByUserID(uid, user.Currency, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": performance})
+}
+
 // Asset Logs
 // @Summary Get Asset Logs by User ID
 // @Description Returns asset logs by user id
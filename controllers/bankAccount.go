@@ -23,8 +23,7 @@ func NewBankAccountController(mongoDB *db.MongoDB) BankAccountController {
 }
 
 var (
-	errBankAccountPremium = "Free members can add up to 2 bank accounts, you can get premium membership for unlimited access."
-	errNoBankAccount      = "Couldn't find bank account."
+	errNoBankAccount = "Couldn't find bank account."
 )
 
 // Create BankAccount
@@ -47,17 +46,7 @@ func (ba *BankAccountController) CreateBankAccount(c *gin.Context) {
 	}
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
-	userModel := models.NewUserModel(ba.Database)
-	isPremium := userModel.IsUserPremium(uid)
-
 	bankAccModel := models.NewBankAccountModel(ba.Database)
-	if !isPremium && bankAccModel.GetUserBankAccountCount(uid) >= 2 {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errBankAccountPremium,
-		})
-
-		return
-	}
 
 	var (
 		createdBankAccount models.BankAccount
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"asset_backend/db"
+	"asset_backend/models"
+	"asset_backend/requests"
+	"net/http"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetController struct {
+	Database *db.MongoDB
+}
+
+func NewBudgetController(mongoDB *db.MongoDB) BudgetController {
+	return BudgetController{
+		Database: mongoDB,
+	}
+}
+
+var (
+	errBudgetCategory = "Budgets can only be set for expense categories."
+	errBudgetExists   = "There is already a budget for this category."
+)
+
+// Create Budget
+// @Summary Create Budget
+// @Description Creates monthly budget for a category in user's currency
+// @Tags budget
+// @Accept application/json
+// @Produce application/json
+// @Param budget body requests.BudgetCreate true "Budget Create"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 201 {object} models.Budget
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /budget [post]
+func (b *BudgetController) CreateBudget(c *gin.Context) {
+	var data requests.BudgetCreate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	if *data.Category < models.Food || *data.Category > models.Others || *data.Category == models.Income {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errBudgetCategory,
+		})
+
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	budgetModel := models.NewBudgetModel(b.Database)
+	if budgetModel.HasBudgetForCategory(uid, *data.Category) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errBudgetExists,
+		})
+
+		return
+	}
+
+	createdBudget, err := budgetModel.CreateBudget(uid, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Successfully created.", "data": createdBudget})
+}
+
+// Budget Status
+// @Summary Get Budget Status
+// @Description Returns spent, remaining and projected values of current month for every budget
+// @Tags budget
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.BudgetStatus
+// @Failure 500 {string} string
+// @Router /budget [get]
+func (b *BudgetController) GetBudgetStatus(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(b.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	budgetModel := models.NewBudgetModel(b.Database)
+
+	budgetStatus, err := budgetModel.GetBudgetStatus(uid, user.Currency, time.Now().UTC())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": budgetStatus})
+}
+
+// Update Budget
+// @Summary Update Budget
+// @Description Updates budget limit
+// @Tags budget
+// @Accept application/json
+// @Produce application/json
+// @Param budget body requests.BudgetUpdate true "Budget Update"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} models.Budget
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /budget [put]
+func (b *BudgetController) UpdateBudget(c *gin.Context) {
+	var data requests.BudgetUpdate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	budgetModel := models.NewBudgetModel(b.Database)
+
+	budget, err := budgetModel.GetBudgetByID(data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	if uid != budget.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrUnauthorized})
+		return
+	}
+
+	updatedBudget, err := budgetModel.UpdateBudget(data, budget)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Budget updated.", "data": updatedBudget})
+}
+
+// Delete Budget By ID
+// @Summary Delete budget by budget id
+// @Description Deletes budget by id
+// @Tags budget
+// @Accept application/json
+// @Produce application/json
+// @Param ID body requests.ID true "ID"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {string} string
+// @Failure 500 {string} string
+// @Router /budget [delete]
+func (b *BudgetController) DeleteBudgetByBudgetID(c *gin.Context) {
+	var data requests.ID
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	budgetModel := models.NewBudgetModel(b.Database)
+
+	isDeleted, err := budgetModel.DeleteBudgetByBudgetID(uid, data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if isDeleted {
+		c.JSON(http.StatusOK, gin.H{"message": "Budget deleted successfully."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"error": "Unauthorized delete."})
+}
@@ -24,7 +24,6 @@ func NewCardController(mongoDB *db.MongoDB) CardController {
 }
 
 var (
-	errCardPremium  = "Free members can add up to 3 credit cards, you can get premium membership for unlimited access."
 	errNoCreditCard = "Couldn't find credit card."
 )
 
@@ -48,17 +47,7 @@ func (cc *CardController) CreateCard(c *gin.Context) {
 	}
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
-	userModel := models.NewUserModel(cc.Database)
-	isPremium := userModel.IsUserPremium(uid)
-
 	cardModel := models.NewCardModel(cc.Database)
-	if !isPremium && cardModel.GetUserCardCount(uid) >= 3 {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errCardPremium,
-		})
-
-		return
-	}
 
 	var (
 		createdCard models.Card
@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"asset_backend/db"
+	"asset_backend/models"
+	"asset_backend/requests"
+	"net/http"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryController struct {
+	Database *db.MongoDB
+}
+
+func NewCategoryController(mongoDB *db.MongoDB) CategoryController {
+	return CategoryController{
+		Database: mongoDB,
+	}
+}
+
+var (
+	errCategoryParent       = "Parent category must be one of your top level categories."
+	errCategoryBuiltInChild = "Default categories can't be moved under another category."
+	errCategoryHasChildren  = "Categories with sub categories can't be moved under another category."
+)
+
+// Create Category
+// @Summary Create Category
+// @Description Creates transaction category, optionally under a parent category
+// @Tags category
+// @Accept application/json
+// @Produce application/json
+// @Param category body requests.CategoryCreate true "Category Create"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 201 {object} models.Category
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /category [post]
+func (cc *CategoryController) CreateCategory(c *gin.Context) {
+	var data requests.CategoryCreate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	categoryModel := models.NewCategoryModel(cc.Database)
+
+	if data.ParentID != nil && !cc.isValidParent(uid, *data.ParentID, "") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errCategoryParent,
+		})
+
+		return
+	}
+
+	createdCategory, err := categoryModel.CreateCategory(uid, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Successfully created.", "data": createdCategory})
+}
+
+// Categories By User ID
+// @Summary Get Categories by User ID
+// @Description Returns user's categories, default categories are created on registration
+// @Tags category
+// @Accept application/json
+// @Produce application/json
+// @Param categorylist query requests.CategoryList false "Category List"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {array} models.Category
+// @Failure 500 {string} string
+// @Router /category [get]
+func (cc *CategoryController) GetCategoriesByUserID(c *gin.Context) {
+	var data requests.CategoryList
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validatorErrorHandler(err),
+		})
+
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	categoryModel := models.NewCategoryModel(cc.Database)
+
+	categories, err := categoryModel.GetCategoriesByUserID(uid, data.IncludeArchived)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": categories})
+}
+
+// Update Category
+// @Summary Update Category
+// @Description Renames, recolors or moves category under another parent
+// @Tags category
+// @Accept application/json
+// @Produce application/json
+// @Param category body requests.CategoryUpdate true "Category Update"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} models.Category
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /category [put]
+func (cc *CategoryController) UpdateCategory(c *gin.Context) {
+	var data requests.CategoryUpdate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	categoryModel := models.NewCategoryModel(cc.Database)
+
+	category, err := categoryModel.GetCategoryByID(data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	if uid != category.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrUnauthorized})
+		return
+	}
+
+	if data.ParentID != nil {
+		if category.BuiltIn != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errCategoryBuiltInChild})
+			return
+		}
+
+		if categoryModel.HasSubCategories(data.ID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errCategoryHasChildren})
+			return
+		}
+
+		if !cc.isValidParent(uid, *data.ParentID, data.ID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errCategoryParent})
+			return
+		}
+	}
+
+	updatedCategory, err := categoryModel.UpdateCategory(data, category)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category updated.", "data": updatedCategory})
+}
+
+// Archive Category
+// @Summary Archive/Unarchive Category
+// @Description Archived categories are hidden and can't be used for new transactions, existing transactions are kept
+// @Tags category
+// @Accept application/json
+// @Produce application/json
+// @Param categoryarchive body requests.CategoryArchive true "Category Archive"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} models.Category
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /category/archive [put]
+func (cc *CategoryController) ArchiveCategory(c *gin.Context) {
+	var data requests.CategoryArchive
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	categoryModel := models.NewCategoryModel(cc.Database)
+
+	category, err := categoryModel.GetCategoryByID(data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	if uid != category.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrUnauthorized})
+		return
+	}
+
+	archivedCategory, err := categoryModel.ArchiveCategory(category, *data.IsArchived)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category updated.", "data": archivedCategory})
+}
+
+// isValidParent only allows top level categories of the user as parent, so
+// categories are nested at most one level deep.
+func (cc *CategoryController) isValidParent(uid, parentID, categoryID string) bool {
+	if parentID == categoryID {
+		return false
+	}
+
+	categoryModel := models.NewCategoryModel(cc.Database)
+
+	parent, err := categoryModel.GetCategoryByID(parentID)
+	if err != nil {
+		return false
+	}
+
+	return parent.UserID == uid && parent.ParentID == nil && !parent.IsArchived
+}
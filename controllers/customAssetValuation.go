@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"asset_backend/models"
+	"asset_backend/requests"
+	"net/http"
+	"strings"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errCustomAssetNotFound = "Custom asset not found, you need to add it as custom asset first."
+)
+
+// Custom Asset Valuations
+// @Summary Get Custom Asset Valuations
+// @Description Returns valuations of custom assets that aren't in investings
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {array} models.CustomAssetValuation
+// @Failure 500 {string} string
+// @Router /asset/custom/valuation [get]
+func (a *AssetController) GetCustomAssetValuations(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	customModel := models.NewCustomAssetValuationModel(a.Database)
+
+	valuations, err := customModel.GetCustomAssetValuationsByUserID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": valuations})
+}
+
+// Update Custom Asset Valuation
+// @Summary Update Custom Asset Valuation
+// @Description Updates the unit price of a custom asset, it's used to value the asset from now on
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Param customassetvaluation body requests.CustomAssetValuationUpdate true "Custom Asset Valuation Update"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} models.CustomAssetValuation
+// @Failure 400 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Router /asset/custom/valuation [put]
+func (a *AssetController) UpdateCustomAssetValuation(c *gin.Context) {
+	var data requests.CustomAssetValuationUpdate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	assetModel := models.NewAssetModel(a.Database)
+
+	if !assetModel.HasCustomAsset(uid, data.ToAsset, strings.ToUpper(data.FromAsset)) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": errCustomAssetNotFound,
+		})
+
+		return
+	}
+
+	customModel := models.NewCustomAssetValuationModel(a.Database)
+
+	valuation, err := customModel.UpdateCustomAssetValuation(uid, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully updated.", "data": valuation})
+}
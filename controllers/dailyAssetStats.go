@@ -2,11 +2,13 @@ package controllers
 
 import (
 	"asset_backend/db"
+	"asset_backend/entitlements"
 	"asset_backend/models"
 	"asset_backend/requests"
 	"asset_backend/responses"
 	"context"
 	"net/http"
+	"time"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
@@ -25,7 +27,7 @@ func NewDailyAssetStatsController(mongoDB *db.MongoDB) DailyAssetStatsController
 
 // Daily Asset Stats
 // @Summary Get Daily Asset Stats by User ID
-// @Description Returns daily asset stats by user id
+// @Description Returns daily asset stats by user id, custom and all ranges can be downsampled to buckets
 // @Tags asset
 // @Accept application/json
 // @Produce application/json
@@ -47,27 +49,26 @@ func (d *DailyAssetStatsController) GetAssetStatsByUserID(c *gin.Context) {
 	}
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
-	userModel := models.NewUserModel(d.Database)
-
-	isPremium := userModel.IsUserPremium(uid)
-	if !isPremium && data.Interval != "weekly" {
+	if errMessage := d.checkDailyStatsInterval(uid, data.Interval, data.StartDate, data.EndDate); errMessage != "" {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": errPremiumFeature,
+			"error": errMessage,
 		})
 
 		return
 	}
 
-	var (
-		cacheKey        = "daily-asset/" + uid + "/" + data.Interval
-		dailyAssetStats responses.DailyAssetStats
-	)
+	cacheKey := "daily-asset/" + uid + "/" + data.Interval + "/" + data.Bucket
+	if data.Interval == "custom" {
+		cacheKey += "/" + data.StartDate.Format("2006-01-02") + "/" + data.EndDate.Format("2006-01-02")
+	}
+
+	var dailyAssetStats responses.DailyAssetStats
 
 	result, err := db.RedisDB.Get(context.TODO(), cacheKey).Result()
 	if err != nil || result == "" {
 		dasModel := models.NewDailyAssetStatsModel(d.Database)
 
-		dailyAssetStats, err = dasModel.GetAssetStatsByUserID(uid, data.Interval)
+		dailyAssetStats, err = dasModel.GetAssetStatsByUserID(uid, data)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": err.Error(),
@@ -90,3 +91,110 @@ func (d *DailyAssetStatsController) GetAssetStatsByUserID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"data": dailyAssetStats})
 }
+
+// Daily Asset Holding Stats
+// @Summary Get Daily Holding Stats by User ID
+// @Description Returns daily amount, price, value and p/l of a holding in user's currency, yearly, long custom and all ranges are downsampled to weekly or monthly buckets
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Param dailyassetholdingstats query requests.DailyAssetHoldingStats true "Daily Asset Holding Stats"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.DailyAssetHoldingStats
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /asset/daily-stats/holding [get]
+func (d *DailyAssetStatsController) GetHoldingStatsByUserID(c *gin.Context) {
+	var data requests.DailyAssetHoldingStats
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validatorErrorHandler(err),
+		})
+
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	if errMessage := d.checkDailyStatsInterval(uid, data.Interval, data.StartDate, data.EndDate); errMessage != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errMessage,
+		})
+
+		return
+	}
+
+	dasModel := models.NewDailyAssetStatsModel(d.Database)
+
+	dailyHoldingStats, err := dasModel.GetHoldingStatsByUserID(uid, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dailyHoldingStats})
+}
+
+// Daily Asset Type Stats
+// @Summary Get Daily Asset Type Stats by User ID
+// @Description Returns daily value and p/l of an asset type in user's currency, yearly, long custom and all ranges are downsampled to weekly or monthly buckets
+// @Tags asset
+// @Accept application/json
+// @Produce application/json
+// @Param dailyassettypestats query requests.DailyAssetTypeStats true "Daily Asset Type Stats"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.DailyAssetTypeStats
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /asset/daily-stats/type [get]
+func (d *DailyAssetStatsController) GetAssetTypeStatsByUserID(c *gin.Context) {
+	var data requests.DailyAssetTypeStats
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validatorErrorHandler(err),
+		})
+
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	if errMessage := d.checkDailyStatsInterval(uid, data.Interval, data.StartDate, data.EndDate); errMessage != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errMessage,
+		})
+
+		return
+	}
+
+	dasModel := models.NewDailyAssetStatsModel(d.Database)
+
+	dailyAssetTypeStats, err := dasModel.GetAssetTypeStatsByUserID(uid, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": dailyAssetTypeStats})
+}
+
+// checkDailyStatsInterval returns the error message for the interval, plans
+// without the daily stats interval feature can only see weekly stats.
+func (d *DailyAssetStatsController) checkDailyStatsInterval(uid, interval string, startDate, endDate *time.Time) string {
+	quotaService := entitlements.NewQuotaService(d.Database)
+	if interval != "weekly" && !quotaService.HasFeature(uid, entitlements.FeatureDailyStatsInterval) {
+		return errPremiumFeature
+	}
+
+	if interval == "custom" && endDate.Before(*startDate) {
+		return errAssetPerformanceRange
+	}
+
+	return ""
+}
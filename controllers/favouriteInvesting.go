@@ -23,11 +23,6 @@ func NewFavouriteInvestingController(mongoDB *db.MongoDB) FavouriteInvestingCont
 	}
 }
 
-var (
-	errFavInvestingPremium = "Free members can add up to 5, you can get premium membership to increase the limit."
-	errFavInvestingLimit   = "You've reached the limit."
-)
-
 // Create Favourite Investing
 // @Summary Create Favourite Investing
 // @Description Creates favourite investing
@@ -49,27 +44,9 @@ func (fi *FavouriteInvestingController) CreateFavouriteInvesting(c *gin.Context)
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
 
-	userModel := models.NewUserModel(fi.Database)
-	isPremium := userModel.IsUserPremium(uid)
-
 	favouriteInvestingModel := models.NewFavouriteInvestingModel(fi.Database)
 
-	count := favouriteInvestingModel.GetFavouriteInvestingsCount(uid)
-	if !isPremium && count >= 5 {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errFavInvestingPremium,
-		})
-
-		return
-	} else if isPremium && count >= 10 {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errFavInvestingLimit,
-		})
-
-		return
-	}
-
-	data.Priority = int(count)
+	data.Priority = int(favouriteInvestingModel.GetFavouriteInvestingsCount(uid))
 	if err := favouriteInvestingModel.CreateFavouriteInvesting(uid, data); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
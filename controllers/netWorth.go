@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"asset_backend/models"
+	"net/http"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// Net Worth
+// @Summary Get Net Worth
+// @Description Returns total assets, bank account balances and card outstanding balances in user's currency
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.NetWorth
+// @Failure 500 {string} string
+// @Router /user/net-worth [get]
+func (u *UserController) GetNetWorth(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(u.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	assetModel := models.NewAssetModel(u.Database)
+
+	assetStats, err := assetModel.GetAllAssetStats(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	netWorthModel := models.NewNetWorthModel(u.Database)
+
+	netWorth, err := netWorthModel.GetNetWorthByUserID(uid, user.Currency, assetStats.TotalAssets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": netWorth})
+}
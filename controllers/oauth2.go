@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"asset_backend/db"
+	"asset_backend/helpers"
 	"asset_backend/models"
 	"asset_backend/requests"
 	"asset_backend/responses"
@@ -40,8 +41,6 @@ var (
 	errWrongLoginMethod = "Failed to login. This email is already registered with different login method."
 )
 
-const tokenExpiration = 259200
-
 // OAuth2 Apple Login
 // @Summary OAuth2 Apple Login
 // @Description Gets user info from apple and creates/finds user and returns token
@@ -109,14 +108,14 @@ func (o *OAuth2Controller) OAuth2AppleLogin(jwt *jwt.GinJWTMiddleware) gin.Handl
 				return
 			}
 
-			token, _, err := jwt.TokenGenerator(user)
+			token, refreshToken, err := helpers.GenerateSessionTokens(c, jwt, o.Database, user, data.DeviceID, data.DeviceName)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
 			}
 
-			c.SetCookie("jwt", token, tokenExpiration, "/", os.Getenv("BASE_URI"), true, true)
-			c.JSON(http.StatusOK, gin.H{"access_token": token})
+			c.SetCookie("jwt", token, int(helpers.AccessTokenTimeout.Seconds()), "/", os.Getenv("BASE_URI"), true, true)
+			c.JSON(http.StatusOK, gin.H{"access_token": token, "refresh_token": refreshToken})
 
 			return
 		} else {
@@ -158,6 +157,9 @@ func (o *OAuth2Controller) OAuth2AppleLogin(jwt *jwt.GinJWTMiddleware) gin.Handl
 					return
 				}
 
+				categoryModel := models.NewCategoryModel(o.Database)
+				categoryModel.CreateDefaultCategories(oAuthUser.ID.Hex())
+
 				user = *oAuthUser
 			}
 
@@ -167,6 +169,7 @@ func (o *OAuth2Controller) OAuth2AppleLogin(jwt *jwt.GinJWTMiddleware) gin.Handl
 			}
 
 			user.RefreshToken = &resp.RefreshToken
+			user.IsVerified = true
 			if err := userModel.UpdateUser(user); err != nil {
 				if err != nil {
 					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -174,14 +177,14 @@ func (o *OAuth2Controller) OAuth2AppleLogin(jwt *jwt.GinJWTMiddleware) gin.Handl
 				}
 			}
 
-			token, _, err := jwt.TokenGenerator(user)
+			token, refreshToken, err := helpers.GenerateSessionTokens(c, jwt, o.Database, user, data.DeviceID, data.DeviceName)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 				return
 			}
 
-			c.SetCookie("jwt", token, tokenExpiration, "/", os.Getenv("BASE_URI"), true, true)
-			c.JSON(http.StatusOK, gin.H{"access_token": token, "refresh_token": resp.RefreshToken})
+			c.SetCookie("jwt", token, int(helpers.AccessTokenTimeout.Seconds()), "/", os.Getenv("BASE_URI"), true, true)
+			c.JSON(http.StatusOK, gin.H{"access_token": token, "refresh_token": refreshToken, "apple_refresh_token": resp.RefreshToken})
 		}
 	}
 }
@@ -238,6 +241,9 @@ func (o *OAuth2Controller) OAuth2GoogleLogin(jwt *jwt.GinJWTMiddleware) gin.Hand
 				return
 			}
 
+			categoryModel := models.NewCategoryModel(o.Database)
+			categoryModel.CreateDefaultCategories(oAuthUser.ID.Hex())
+
 			user = *oAuthUser
 		}
 
@@ -246,14 +252,23 @@ func (o *OAuth2Controller) OAuth2GoogleLogin(jwt *jwt.GinJWTMiddleware) gin.Hand
 			return
 		}
 
-		token, _, err := jwt.TokenGenerator(user)
+		if !user.IsVerified {
+			if err := userModel.SetUserVerified(user.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			user.IsVerified = true
+		}
+
+		token, refreshToken, err := helpers.GenerateSessionTokens(c, jwt, o.Database, user, data.DeviceID, data.DeviceName)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		c.SetCookie("jwt", token, tokenExpiration, "/", os.Getenv("BASE_URI"), true, true)
-		c.JSON(http.StatusOK, gin.H{"access_token": token})
+		c.SetCookie("jwt", token, int(helpers.AccessTokenTimeout.Seconds()), "/", os.Getenv("BASE_URI"), true, true)
+		c.JSON(http.StatusOK, gin.H{"access_token": token, "refresh_token": refreshToken})
 	}
 }
 
@@ -314,17 +329,29 @@ func (o *OAuth2Controller) GoogleCallback(jwt *jwt.GinJWTMiddleware) gin.Handler
 				return
 			}
 
+			categoryModel := models.NewCategoryModel(o.Database)
+			categoryModel.CreateDefaultCategories(oAuthUser.ID.Hex())
+
 			user = *oAuthUser
 		}
 
-		token, _, err := jwt.TokenGenerator(user)
+		if !user.IsVerified {
+			if err := userModel.SetUserVerified(user.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			user.IsVerified = true
+		}
+
+		token, refreshToken, err := helpers.GenerateSessionTokens(c, jwt, o.Database, user, "", "")
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		c.SetCookie("access_token", token, tokenExpiration, "/", os.Getenv("BASE_URI"), true, true)
-		c.JSON(http.StatusOK, gin.H{"access_token": token})
+		c.SetCookie("access_token", token, int(helpers.AccessTokenTimeout.Seconds()), "/", os.Getenv("BASE_URI"), true, true)
+		c.JSON(http.StatusOK, gin.H{"access_token": token, "refresh_token": refreshToken})
 	}
 }
 
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"asset_backend/db"
+	"asset_backend/models"
+	"asset_backend/requests"
+	"net/http"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+type PriceAlertController struct {
+	Database *db.MongoDB
+}
+
+func NewPriceAlertController(mongoDB *db.MongoDB) PriceAlertController {
+	return PriceAlertController{
+		Database: mongoDB,
+	}
+}
+
+var (
+	errPriceAlertInvesting = "Investing couldn't be found."
+)
+
+// Create Price Alert
+// @Summary Create Price Alert
+// @Description Creates price alert for investing. Alerts are checked hourly.
+// @Tags pricealert
+// @Accept application/json
+// @Produce application/json
+// @Param pricealert body requests.PriceAlertCreate true "Price Alert Create"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 201 {object} models.PriceAlert
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /price-alert [post]
+func (pa *PriceAlertController) CreatePriceAlert(c *gin.Context) {
+	var data requests.PriceAlertCreate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+
+	priceAlertModel := models.NewPriceAlertModel(pa.Database)
+
+	investingID := models.InvestingID{
+		Symbol: data.Symbol,
+		Type:   data.Type,
+		Market: data.Market,
+	}
+
+	investingModel := models.NewInvestingModel(pa.Database)
+
+	existingIDs, err := investingModel.GetExistingInvestingIDs([]models.InvestingID{investingID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if !existingIDs[investingID] {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errPriceAlertInvesting,
+		})
+
+		return
+	}
+
+	createdPriceAlert, err := priceAlertModel.CreatePriceAlert(uid, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Successfully created.", "data": createdPriceAlert})
+}
+
+// Price Alerts
+// @Summary Get Price Alerts
+// @Description Returns price alerts of user with current prices
+// @Tags pricealert
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {array} responses.PriceAlert
+// @Failure 500 {string} string
+// @Router /price-alert [get]
+func (pa *PriceAlertController) GetPriceAlerts(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	priceAlertModel := models.NewPriceAlertModel(pa.Database)
+
+	priceAlerts, err := priceAlertModel.GetPriceAlertsByUserID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": priceAlerts})
+}
+
+// Update Price Alert
+// @Summary Update Price Alert
+// @Description Updates price alert, one-shot alerts can be activated again with is_active
+// @Tags pricealert
+// @Accept application/json
+// @Produce application/json
+// @Param pricealert body requests.PriceAlertUpdate true "Price Alert Update"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} models.PriceAlert
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /price-alert [put]
+func (pa *PriceAlertController) UpdatePriceAlert(c *gin.Context) {
+	var data requests.PriceAlertUpdate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	priceAlertModel := models.NewPriceAlertModel(pa.Database)
+
+	priceAlert, err := priceAlertModel.GetPriceAlertByID(data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	if uid != priceAlert.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrUnauthorized})
+		return
+	}
+
+	updatedPriceAlert, err := priceAlertModel.UpdatePriceAlert(data, priceAlert)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Price alert updated.", "data": updatedPriceAlert})
+}
+
+// Delete Price Alert By ID
+// @Summary Delete price alert by id
+// @Description Deletes price alert by id
+// @Tags pricealert
+// @Accept application/json
+// @Produce application/json
+// @Param ID body requests.ID true "ID"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {string} string
+// @Failure 500 {string} string
+// @Router /price-alert [delete]
+func (pa *PriceAlertController) DeletePriceAlertByID(c *gin.Context) {
+	var data requests.ID
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	priceAlertModel := models.NewPriceAlertModel(pa.Database)
+
+	isDeleted, err := priceAlertModel.DeletePriceAlertByID(uid, data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if isDeleted {
+		c.JSON(http.StatusOK, gin.H{"message": "Price alert deleted successfully."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"error": "Unauthorized delete."})
+}
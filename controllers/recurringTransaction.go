@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"asset_backend/models"
+	"asset_backend/requests"
+	"net/http"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// Create Recurring Transaction
+// @Summary Create Recurring Transaction
+// @Description Creates recurring transaction template. Transactions are created by daily job when they're due.
+// @Tags transaction
+// @Accept application/json
+// @Produce application/json
+// @Param recurringtransaction body requests.RecurringTransactionCreate true "Recurring Transaction Create"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 201 {object} models.RecurringTransaction
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /transaction/recurring [post]
+func (t *TransactionController) CreateRecurringTransaction(c *gin.Context) {
+	var data requests.RecurringTransactionCreate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+
+	if data.CategoryID != nil {
+		category, shouldReturn := t.resolveCategory(uid, *data.CategoryID, c)
+		if shouldReturn {
+			return
+		}
+
+		data.Category = &category
+	}
+
+	if data.TransactionMethod != nil {
+		if shouldReturn := t.checkTransactionMethod(uid, *data.TransactionMethod, c); shouldReturn {
+			return
+		}
+	}
+
+	transactionModel := models.NewTransactionModel(t.Database)
+
+	createdRecurringTransaction, err := transactionModel.CreateRecurringTransaction(uid, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Successfully created.", "data": createdRecurringTransaction})
+}
+
+// Recurring Transactions
+// @Summary Get Recurring Transactions
+// @Description Returns recurring transaction templates of user
+// @Tags transaction
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {array} models.RecurringTransaction
+// @Failure 500 {string} string
+// @Router /transaction/recurring [get]
+func (t *TransactionController) GetRecurringTransactionsByUserID(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	transactionModel := models.NewTransactionModel(t.Database)
+
+	recurringTransactions, err := transactionModel.GetRecurringTransactionsByUserID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": recurringTransactions})
+}
+
+// Recurring Transaction Preview
+// @Summary Preview Recurring Transaction
+// @Description Returns upcoming occurrence dates of recurring transaction
+// @Tags transaction
+// @Accept application/json
+// @Produce application/json
+// @Param recurringpreview query requests.RecurringTransactionPreview true "Recurring Transaction Preview"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.RecurringTransactionPreview
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /transaction/recurring/preview [get]
+func (t *TransactionController) GetRecurringTransactionPreview(c *gin.Context) {
+	var data requests.RecurringTransactionPreview
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validatorErrorHandler(err),
+		})
+
+		return
+	}
+
+	transactionModel := models.NewTransactionModel(t.Database)
+
+	recurringTransaction, err := transactionModel.GetRecurringTransactionByID(data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	if uid != recurringTransaction.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrUnauthorized})
+		return
+	}
+
+	preview := transactionModel.GetRecurringTransactionPreview(recurringTransaction, data.Count)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": preview})
+}
+
+// Update Recurring Transaction
+// @Summary Update Recurring Transaction
+// @Description Updates recurring transaction template, already created transactions are not changed
+// @Tags transaction
+// @Accept application/json
+// @Produce application/json
+// @Param recurringtransaction body requests.RecurringTransactionUpdate true "Recurring Transaction Update"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} models.RecurringTransaction
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /transaction/recurring [put]
+func (t *TransactionController) UpdateRecurringTransaction(c *gin.Context) {
+	var data requests.RecurringTransactionUpdate
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	transactionModel := models.NewTransactionModel(t.Database)
+
+	recurringTransaction, err := transactionModel.GetRecurringTransactionByID(data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	if uid != recurringTransaction.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrUnauthorized})
+		return
+	}
+
+	if data.CategoryID != nil {
+		category, shouldReturn := t.resolveCategory(uid, *data.CategoryID, c)
+		if shouldReturn {
+			return
+		}
+
+		data.Category = &category
+	}
+
+	if data.TransactionMethod != nil {
+		if shouldReturn := t.checkTransactionMethod(uid, *data.TransactionMethod, c); shouldReturn {
+			return
+		}
+	}
+
+	updatedRecurringTransaction, err := transactionModel.UpdateRecurringTransaction(data, recurringTransaction)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recurring transaction updated.", "data": updatedRecurringTransaction})
+}
+
+// Pause Recurring Transaction
+// @Summary Pause/Resume Recurring Transaction
+// @Description Pauses or resumes recurring transaction. Occurrences while paused are skipped.
+// @Tags transaction
+// @Accept application/json
+// @Produce application/json
+// @Param recurringpause body requests.RecurringTransactionPause true "Recurring Transaction Pause"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} models.RecurringTransaction
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 500 {string} string
+// @Router /transaction/recurring/pause [put]
+func (t *TransactionController) PauseRecurringTransaction(c *gin.Context) {
+	var data requests.RecurringTransactionPause
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	transactionModel := models.NewTransactionModel(t.Database)
+
+	recurringTransaction, err := transactionModel.GetRecurringTransactionByID(data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	if uid != recurringTransaction.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrUnauthorized})
+		return
+	}
+
+	updatedRecurringTransaction, err := transactionModel.PauseRecurringTransaction(recurringTransaction, *data.IsPaused)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Recurring transaction updated.", "data": updatedRecurringTransaction})
+}
+
+// Delete Recurring Transaction
+// @Summary Delete recurring transaction by id
+// @Description Deletes recurring transaction template, already created transactions are kept
+// @Tags transaction
+// @Accept application/json
+// @Produce application/json
+// @Param ID body requests.ID true "ID"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {string} string
+// @Failure 500 {string} string
+// @Router /transaction/recurring [delete]
+func (t *TransactionController) DeleteRecurringTransactionByID(c *gin.Context) {
+	var data requests.ID
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	transactionModel := models.NewTransactionModel(t.Database)
+
+	isDeleted, err := transactionModel.DeleteRecurringTransactionByID(uid, data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if isDeleted {
+		c.JSON(http.StatusOK, gin.H{"message": "Recurring transaction deleted successfully."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"error": "Unauthorized delete."})
+}
+
+func (t *TransactionController) checkTransactionMethod(uid string, method requests.TransactionMethod, c *gin.Context) bool {
+	var (
+		methodUserID string
+		err          error
+	)
+
+	switch *method.Type {
+	case models.BankAcc:
+		bankAccModel := models.NewBankAccountModel(t.Database)
+
+		var bankAccount models.BankAccount
+		bankAccount, err = bankAccModel.GetBankAccountByID(method.MethodID)
+		methodUserID = bankAccount.UserID
+	case models.CreditCard:
+		cardModel := models.NewCardModel(t.Database)
+
+		var creditCard models.Card
+		creditCard, err = cardModel.GetCardByID(method.MethodID)
+		methodUserID = creditCard.UserID
+	default:
+		return false
+	}
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+
+		return true
+	} else if methodUserID != uid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errTransactionMethodUnauthorized,
+		})
+
+		return true
+	}
+
+	return false
+}
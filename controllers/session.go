@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"asset_backend/helpers"
+	"asset_backend/models"
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"net/http"
+	"os"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errInvalidRefreshToken = "Session is expired, please login again."
+	errSessionNotFound     = "Couldn't find session."
+)
+
+// Refresh Token
+// @Summary Refreshes access token
+// @Description Returns a new access token and rotates the refresh token, reusing an old refresh token revokes the session
+// @Tags auth
+// @Accept application/json
+// @Produce application/json
+// @Param refreshtoken body requests.RefreshToken true "Refresh Token"
+// @Success 200 {string} string "Token"
+// @Failure 401 {string} string
+// @Failure 500 {string} string
+// @Router /auth/refresh [post]
+func (u *UserController) RefreshToken(jwtToken *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var data requests.RefreshToken
+		if shouldReturn := bindJSONData(&data, c); shouldReturn {
+			return
+		}
+
+		sessionModel := models.NewSessionModel(u.Database)
+		tokenHash := helpers.HashRefreshToken(data.RefreshToken)
+
+		session, err := sessionModel.GetSessionByTokenHash(tokenHash)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidRefreshToken})
+			return
+		}
+
+		if session.RefreshTokenHash != tokenHash {
+			sessionModel.DeleteSessionByID(session.UserID, session.ID.Hex())
+			helpers.RevokeSessions(session.ID.Hex())
+
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidRefreshToken})
+			return
+		}
+
+		if !session.ExpiresAt.After(time.Now().UTC()) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidRefreshToken})
+			return
+		}
+
+		userModel := models.NewUserModel(u.Database)
+
+		user, err := userModel.FindUserByID(session.UserID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidRefreshToken})
+			return
+		}
+
+		refreshToken, err := helpers.GenerateRefreshToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		isRotated, err := sessionModel.RotateSessionToken(session, helpers.HashRefreshToken(refreshToken), time.Now().UTC().Add(helpers.RefreshTokenTimeout))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !isRotated {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidRefreshToken})
+			return
+		}
+
+		token, _, err := jwtToken.TokenGenerator(helpers.SessionIdentity{
+			User:      user,
+			SessionID: session.ID.Hex(),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.SetCookie("access_token", token, int(helpers.AccessTokenTimeout.Seconds()), "/", os.Getenv("BASE_URI"), true, true)
+		c.JSON(http.StatusOK, gin.H{"access_token": token, "refresh_token": refreshToken})
+	}
+}
+
+// Logout
+// @Summary Logout
+// @Description Revokes the session of the access token
+// @Tags auth
+// @Accept application/json
+// @Produce application/json
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {string} string
+// @Router /auth/logout [post]
+func (u *UserController) Logout(jwtToken *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, err := jwtToken.GetClaimsFromJWT(c); err == nil {
+			uid, _ := claims["id"].(string)
+
+			if sessionID := helpers.GetSessionID(claims); sessionID != "" {
+				sessionModel := models.NewSessionModel(u.Database)
+				sessionModel.DeleteSessionByID(uid, sessionID)
+				helpers.RevokeSessions(sessionID)
+			}
+		}
+
+		jwtToken.LogoutHandler(c)
+	}
+}
+
+// Get Sessions
+// @Summary Get active sessions
+// @Description Returns user's active sessions by device
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {array} responses.Session
+// @Failure 500 {string} string
+// @Router /user/sessions [get]
+func (u *UserController) GetSessions(c *gin.Context) {
+	claims := jwt.ExtractClaims(c)
+	uid := claims["id"].(string)
+	currentSessionID := helpers.GetSessionID(claims)
+
+	sessionModel := models.NewSessionModel(u.Database)
+
+	sessions, err := sessionModel.GetSessionsByUserID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	sessionList := make([]responses.Session, len(sessions))
+	for i, session := range sessions {
+		sessionList[i] = responses.Session{
+			ID:         session.ID.Hex(),
+			DeviceID:   session.DeviceID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			IsCurrent:  session.ID.Hex() == currentSessionID,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched sessions.", "data": sessionList})
+}
+
+// Delete Session
+// @Summary Revoke session
+// @Description Revokes the session, its device has to login again
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Param ID body requests.ID true "Session ID"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {string} string
+// @Failure 404 {string} string
+// @Failure 500 {string} string
+// @Router /user/sessions [delete]
+func (u *UserController) DeleteSession(c *gin.Context) {
+	var data requests.ID
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	sessionModel := models.NewSessionModel(u.Database)
+
+	isDeleted, err := sessionModel.DeleteSessionByID(uid, data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if !isDeleted {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": errSessionNotFound,
+		})
+
+		return
+	}
+
+	helpers.RevokeSessions(data.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully."})
+}
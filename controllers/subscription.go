@@ -2,10 +2,12 @@ package controllers
 
 import (
 	"asset_backend/db"
+	"asset_backend/entitlements"
 	"asset_backend/helpers"
 	"asset_backend/models"
 	"asset_backend/requests"
 	"asset_backend/responses"
+	"asset_backend/utils"
 	"context"
 	"net/http"
 	"sort"
@@ -30,7 +32,6 @@ var (
 	errSubscriptionInviteSelf          = "You cannot invite yourself."
 	errUnauthorizedCreditCard          = "Unauthorized credit card access. You're not the owner of this credit card."
 	errAlreadyShared                   = "This user already has access to subscription."
-	errSubscriptionPremium             = "Free members can add up to 5 subscriptions, you can get premium membership for unlimited access."
 	errSubscriptionNotificationPremium = "You should be premium user for this feature."
 )
 
@@ -55,19 +56,9 @@ func (s *SubscriptionController) CreateSubscription(c *gin.Context) {
 	}
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
-	userModel := models.NewUserModel(s.Database)
-	isPremium := userModel.IsUserPremium(uid)
-
-	subscriptionModel := models.NewSubscriptionModel(s.Database)
-	if !isPremium && subscriptionModel.GetUserSubscriptionCount(uid) >= 5 {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errSubscriptionPremium,
-		})
-
-		return
-	}
+	quotaService := entitlements.NewQuotaService(s.Database)
 
-	if data.NotificationTime != nil && !isPremium {
+	if data.NotificationTime != nil && !quotaService.HasFeature(uid, entitlements.FeatureSubscriptionNotification) {
 		c.JSON(http.StatusForbidden, gin.H{
 			"error": errSubscriptionNotificationPremium,
 		})
@@ -99,6 +90,7 @@ func (s *SubscriptionController) CreateSubscription(c *gin.Context) {
 		err                 error
 	)
 
+	subscriptionModel := models.NewSubscriptionModel(s.Database)
 	if createdSubscription, err = subscriptionModel.CreateSubscription(uid, data); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -109,6 +101,10 @@ func (s *SubscriptionController) CreateSubscription(c *gin.Context) {
 
 	go db.RedisDB.Del(context.TODO(), ("subscription/" + uid))
 
+	if s.isPasswordHidden(uid) {
+		hideSubscriptionPassword(createdSubscription.Account)
+	}
+
 	c.JSON(http.StatusCreated, gin.H{"message": "Successfully created.", "data": createdSubscription})
 }
 
@@ -123,6 +119,7 @@ func (s *SubscriptionController) CreateSubscription(c *gin.Context) {
 // @Param Authorization header string true "Authentication header"
 // @Success 200 {string} string
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /subscription/invite [post]
 func (s *SubscriptionController) InviteSubscriptionToUser(c *gin.Context) {
@@ -132,6 +129,10 @@ func (s *SubscriptionController) InviteSubscriptionToUser(c *gin.Context) {
 	}
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
+	if !s.isUserVerified(uid, c) {
+		return
+	}
+
 	userModel := models.NewUserModel(s.Database)
 
 	user, err := userModel.FindUserByEmail(data.InvitedUserMail)
@@ -265,6 +266,12 @@ func (s *SubscriptionController) GetSharedSubscriptionsByUserID(c *gin.Context)
 		return
 	}
 
+	if s.isPasswordHidden(uid) {
+		for _, subscription := range sharedSubscriptions {
+			hideSubscriptionPassword(subscription.Account)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": sharedSubscriptions})
 }
 
@@ -279,6 +286,7 @@ func (s *SubscriptionController) GetSharedSubscriptionsByUserID(c *gin.Context)
 // @Param Authorization header string true "Authentication header"
 // @Success 200 {string} string
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /subscription/invitation [post]
 func (s *SubscriptionController) HandleSubscriptionInvitation(c *gin.Context) {
@@ -288,6 +296,10 @@ func (s *SubscriptionController) HandleSubscriptionInvitation(c *gin.Context) {
 	}
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
+	if *data.IsAccepted && !s.isUserVerified(uid, c) {
+		return
+	}
+
 	subscriptionModel := models.NewSubscriptionModel(s.Database)
 
 	if err := subscriptionModel.HandleSubscriptionInvitation(data.ID, uid, *data.IsAccepted); err != nil {
@@ -338,6 +350,12 @@ func (s *SubscriptionController) GetSubscriptionsByCardID(c *gin.Context) {
 		return
 	}
 
+	if s.isPasswordHidden(uid) {
+		for _, subscription := range subscriptions {
+			hideSubscriptionPassword(subscription.Account)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": subscriptions})
 }
 
@@ -398,10 +416,15 @@ func (s *SubscriptionController) GetSubscriptionsAndStatsByUserID(c *gin.Context
 			Stats: subscriptionStats,
 		}
 
+		// Account passwords are cached encrypted.
+		convertSubscriptionPasswords(subscriptionAndStats.Data, utils.Encrypt)
 		marshalSubscriptionAndStats, _ := msgpack.Marshal(subscriptionAndStats)
+		convertSubscriptionPasswords(subscriptionAndStats.Data, utils.Decrypt)
+
 		go db.RedisDB.Set(context.TODO(), cacheKey, marshalSubscriptionAndStats, db.RedisLExpire)
 	} else {
 		msgpack.Unmarshal([]byte(result), &subscriptionAndStats)
+		convertSubscriptionPasswords(subscriptionAndStats.Data, utils.Decrypt)
 		sort.Slice(subscriptionAndStats.Data, func(i, j int) bool {
 			switch data.Sort {
 			case "name":
@@ -434,6 +457,12 @@ func (s *SubscriptionController) GetSubscriptionsAndStatsByUserID(c *gin.Context
 		})
 	}
 
+	if s.isPasswordHidden(uid) {
+		for _, subscription := range subscriptionAndStats.Data {
+			hideSubscriptionPassword(subscription.Account)
+		}
+	}
+
 	c.JSON(http.StatusOK, subscriptionAndStats)
 }
 
@@ -478,6 +507,10 @@ func (s *SubscriptionController) GetSubscriptionDetails(c *gin.Context) {
 		return
 	}
 
+	if s.isPasswordHidden(uid) {
+		hideSubscriptionPassword(subscription.Account)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": subscription})
 }
 
@@ -551,8 +584,8 @@ func (s *SubscriptionController) UpdateSubscription(c *gin.Context) {
 	var updatedSubscription responses.Subscription
 
 	if data.NotificationTime != nil {
-		userModel := models.NewUserModel(s.Database)
-		if isPremium := userModel.IsUserPremium(uid); !isPremium {
+		quotaService := entitlements.NewQuotaService(s.Database)
+		if !quotaService.HasFeature(uid, entitlements.FeatureSubscriptionNotification) {
 			c.JSON(http.StatusForbidden, gin.H{
 				"error": errSubscriptionNotificationPremium,
 			})
@@ -571,6 +604,10 @@ func (s *SubscriptionController) UpdateSubscription(c *gin.Context) {
 
 	go db.RedisDB.Del(context.TODO(), ("subscription/" + uid))
 
+	if s.isPasswordHidden(uid) {
+		hideSubscriptionPassword(updatedSubscription.Account)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Subscription updated.", "data": updatedSubscription})
 }
 
@@ -641,3 +678,116 @@ func (s *SubscriptionController) DeleteAllSubscriptionsByUserID(c *gin.Context)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Subscriptions deleted successfully by user id."})
 }
+
+// Subscription Account Password
+// @Summary Reveal subscription account password
+// @Description Returns the account password of the subscription, users with two factor need the code header
+// @Tags subscription
+// @Accept application/json
+// @Produce application/json
+// @Param ID query requests.ID true "ID"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Param X-TOTP-Code header string false "Two factor code"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Failure 429 {string} string
+// @Failure 500 {string} string
+// @Router /subscription/password [get]
+func (s *SubscriptionController) GetSubscriptionPassword(c *gin.Context) {
+	var data requests.ID
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validatorErrorHandler(err),
+		})
+
+		return
+	}
+
+	subscriptionModel := models.NewSubscriptionModel(s.Database)
+
+	subscription, err := subscriptionModel.GetSubscriptionByID(data.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if subscription.UserID == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": errSubscriptionNotFound})
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+
+	isAuthorized := uid == subscription.UserID
+	for _, userID := range subscription.SharedUsers {
+		if userID == uid {
+			isAuthorized = true
+		}
+	}
+
+	if !isAuthorized {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrUnauthorized})
+		return
+	}
+
+	var password *string
+	if subscription.Account != nil && subscription.Account.Password != nil {
+		decryptedPassword := utils.Decrypt(*subscription.Account.Password)
+		password = &decryptedPassword
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched.", "data": gin.H{"password": password}})
+}
+
+// isPasswordHidden reports whether account passwords are left out of user's
+// subscriptions, users with two factor reveal them with GetSubscriptionPassword.
+func (s *SubscriptionController) isPasswordHidden(uid string) bool {
+	userModel := models.NewUserModel(s.Database)
+
+	user, err := userModel.FindUserByID(uid)
+
+	return err != nil || user.TwoFactorEnabled
+}
+
+// isUserVerified responds with 403 when user's email isn't verified, sharing
+// subscriptions requires a verified email.
+func (s *SubscriptionController) isUserVerified(uid string, c *gin.Context) bool {
+	userModel := models.NewUserModel(s.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return false
+	}
+
+	if !user.IsVerified {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": errEmailNotVerified,
+		})
+
+		return false
+	}
+
+	return true
+}
+
+func convertSubscriptionPasswords(subscriptions []responses.Subscription, convert func(string) string) {
+	for _, subscription := range subscriptions {
+		if subscription.Account != nil && subscription.Account.Password != nil {
+			password := convert(*subscription.Account.Password)
+			subscription.Account.Password = &password
+		}
+	}
+}
+
+func hideSubscriptionPassword(account *responses.SubscriptionAccount) {
+	if account != nil {
+		account.Password = nil
+	}
+}
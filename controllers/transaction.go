@@ -2,10 +2,13 @@ package controllers
 
 import (
 	"asset_backend/db"
+	"asset_backend/entitlements"
+	"asset_backend/helpers"
 	"asset_backend/models"
 	"asset_backend/requests"
 	"asset_backend/responses"
 	"net/http"
+	"time"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
@@ -23,7 +26,8 @@ func NewTransactionController(mongoDB *db.MongoDB) TransactionController {
 
 var (
 	errTransactionMethodUnauthorized = "Unauthorized method access. You're not authorized for this method."
-	errTransactionPremium            = "Free members can add up to 10 transactions per day, you can get premium membership for unlimited access."
+	errCategoryUnauthorized          = "Unauthorized category access. You're not authorized for this category."
+	errCategoryArchived              = "Archived categories can't be used for new transactions."
 )
 
 // Create Transaction
@@ -37,6 +41,7 @@ var (
 // @Param Authorization header string true "Authentication header"
 // @Success 201 {object} models.Transaction
 // @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /transaction [post]
 func (t *TransactionController) CreateTransaction(c *gin.Context) {
@@ -46,18 +51,18 @@ func (t *TransactionController) CreateTransaction(c *gin.Context) {
 	}
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
-	userModel := models.NewUserModel(t.Database)
-	isPremium := userModel.IsUserPremium(uid)
 
-	transactionModel := models.NewTransactionModel(t.Database)
-	if !isPremium && transactionModel.GetUserTransactionCountByTime(uid, data.TransactionDate) >= 10 {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": errTransactionPremium,
-		})
+	if data.CategoryID != nil {
+		category, shouldReturn := t.resolveCategory(uid, *data.CategoryID, c)
+		if shouldReturn {
+			return
+		}
 
-		return
+		data.Category = &category
 	}
 
+	transactionModel := models.NewTransactionModel(t.Database)
+
 	if data.TransactionMethod != nil {
 		switch *data.TransactionMethod.Type {
 		case 0:
@@ -110,6 +115,8 @@ func (t *TransactionController) CreateTransaction(c *gin.Context) {
 		return
 	}
 
+	go t.sendBudgetAlerts(uid)
+
 	c.JSON(http.StatusCreated, gin.H{"message": "Successfully created.", "data": createdTransaction})
 }
 
@@ -172,8 +179,18 @@ func (t *TransactionController) GetTransactionStats(c *gin.Context) {
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
 	transactionModel := models.NewTransactionModel(t.Database)
+	categoryModel := models.NewCategoryModel(t.Database)
+
+	incomeCategoryIDs, err := categoryModel.GetBuiltInCategoryIDs(uid, models.Income)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
 
-	transactionDailyStats, err := transactionModel.GetTransactionStats(uid, data)
+	transactionDailyStats, err := transactionModel.GetTransactionStats(uid, data, incomeCategoryIDs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -223,9 +240,42 @@ func (t *TransactionController) GetTransactionsByUserIDAndFilterSort(c *gin.Cont
 	}
 
 	uid := jwt.ExtractClaims(c)["id"].(string)
+
+	var (
+		categoryIDs    []string
+		legacyCategory *int64
+	)
+
+	if data.CategoryID != nil {
+		categoryModel := models.NewCategoryModel(t.Database)
+
+		category, err := categoryModel.GetCategoryByID(*data.CategoryID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if category.UserID != uid {
+			c.JSON(http.StatusForbidden, gin.H{"error": ErrUnauthorized})
+			return
+		}
+
+		categories, err := categoryModel.GetCategoryWithSubCategories(category)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		for _, category := range categories {
+			categoryIDs = append(categoryIDs, category.ID.Hex())
+		}
+
+		legacyCategory = category.BuiltIn
+	}
+
 	transactionModel := models.NewTransactionModel(t.Database)
 
-	transactions, pagination, err := transactionModel.GetTransactionsByUserIDAndFilterSort(uid, data)
+	transactions, pagination, err := transactionModel.GetTransactionsByUserIDAndFilterSort(uid, data, categoryIDs, legacyCategory)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -272,15 +322,25 @@ func (t *TransactionController) UpdateTransaction(c *gin.Context) {
 		return
 	}
 
-	userModel := models.NewUserModel(t.Database)
-	isPremium := userModel.IsUserPremium(uid)
+	if data.CategoryID != nil {
+		category, shouldReturn := t.resolveCategory(uid, *data.CategoryID, c)
+		if shouldReturn {
+			return
+		}
 
-	if data.TransactionDate != nil && !isPremium && transactionModel.GetUserTransactionCountByTime(uid, *data.TransactionDate) >= 10 {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": errTransactionPremium,
-		})
+		data.Category = &category
+	}
 
-		return
+	// Moving the transaction within the same day doesn't add to the day's count.
+	if data.TransactionDate != nil && !isSameDay(*data.TransactionDate, transaction.TransactionDate) {
+		quotaService := entitlements.NewQuotaService(t.Database)
+		if message, ok := quotaService.CheckQuota(uid, entitlements.ResourceDailyTransaction, *data.TransactionDate); !ok {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": message,
+			})
+
+			return
+		}
 	}
 
 	if data.TransactionMethod != nil {
@@ -332,9 +392,16 @@ func (t *TransactionController) UpdateTransaction(c *gin.Context) {
 		return
 	}
 
+	go t.sendBudgetAlerts(uid)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Transaction updated.", "data": updatedTransaction})
 }
 
+func (t *TransactionController) sendBudgetAlerts(uid string) {
+	budgetModel := models.NewBudgetModel(t.Database)
+	helpers.SendBudgetAlerts(budgetModel.GetBudgetAlerts(&uid))
+}
+
 // Delete Transaction By ID
 // @Summary Delete transaction by transaction id
 // @Description Deletes transaction by id
@@ -398,3 +465,40 @@ func (t *TransactionController) DeleteAllTransactionsByUserID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Transactions deleted successfully by user id."})
 }
+
+// resolveCategory validates the user category and returns the built-in category
+// transactions of it are counted as.
+func (t *TransactionController) resolveCategory(uid, categoryID string, c *gin.Context) (int64, bool) {
+	categoryModel := models.NewCategoryModel(t.Database)
+
+	category, err := categoryModel.GetCategoryByID(categoryID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+
+		return 0, true
+	} else if category.UserID != uid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errCategoryUnauthorized,
+		})
+
+		return 0, true
+	} else if category.IsArchived {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errCategoryArchived,
+		})
+
+		return 0, true
+	}
+
+	return categoryModel.GetRootBuiltIn(category), false
+}
+
+// isSameDay compares the UTC days, transactions are counted per UTC day.
+func isSameDay(first, second time.Time) bool {
+	firstYear, firstMonth, firstDay := first.UTC().Date()
+	secondYear, secondMonth, secondDay := second.UTC().Date()
+
+	return firstYear == secondYear && firstMonth == secondMonth && firstDay == secondDay
+}
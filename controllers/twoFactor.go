@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"asset_backend/helpers"
+	"asset_backend/models"
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"asset_backend/utils"
+	"net/http"
+	"os"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errTwoFactorEnabled    = "Two factor authentication is already enabled."
+	errTwoFactorNotEnabled = "Two factor authentication is not enabled."
+	errTwoFactorNoSetup    = "Please start two factor setup first."
+)
+
+// Two Factor Login
+// @Summary Second step of login
+// @Description Verifies the TOTP or recovery code of the login challenge and returns tokens
+// @Tags auth
+// @Accept application/json
+// @Produce application/json
+// @Param twofactorlogin body requests.TwoFactorLogin true "Two Factor Login"
+// @Success 200 {string} string "Token"
+// @Failure 401 {string} string
+// @Failure 403 {string} string
+// @Failure 429 {string} string
+// @Failure 500 {string} string
+// @Router /auth/2fa [post]
+func (u *UserController) VerifyTwoFactorLogin(jwtToken *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var data requests.TwoFactorLogin
+		if shouldReturn := bindJSONData(&data, c); shouldReturn {
+			return
+		}
+
+		challenge, err := helpers.GetTwoFactorChallenge(data.ChallengeToken)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		userModel := models.NewUserModel(u.Database)
+
+		user, err := userModel.FindUserByID(challenge.UserID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": helpers.ErrTwoFactorChallenge.Error()})
+			return
+		}
+
+		if err := helpers.VerifyTwoFactorCode(u.Database, user, data.Code); err != nil {
+			c.JSON(helpers.GetTwoFactorErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		helpers.DeleteTwoFactorChallenge(data.ChallengeToken)
+
+		token, refreshToken, err := helpers.GenerateSessionTokens(c, jwtToken, u.Database, user, challenge.DeviceID, challenge.DeviceName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.SetCookie("access_token", token, int(helpers.AccessTokenTimeout.Seconds()), "/", os.Getenv("BASE_URI"), true, true)
+		c.JSON(http.StatusOK, gin.H{"access_token": token, "refresh_token": refreshToken})
+	}
+}
+
+// Setup Two Factor
+// @Summary Start two factor setup
+// @Description Creates a TOTP secret, it's enabled after a code of it is verified
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.TwoFactorSetup
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /user/2fa/setup [post]
+func (u *UserController) SetupTwoFactor(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(u.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if user.IsOAuthUser {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errOAuthUser,
+		})
+
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errTwoFactorEnabled,
+		})
+
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if err := userModel.SetTwoFactorSecret(uid, utils.Encrypt(secret)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Please verify a code of the authenticator app.", "data": responses.TwoFactorSetup{
+		Secret:     secret,
+		OTPAuthURL: utils.GetTOTPURI(secret, helpers.TwoFactorIssuer, user.EmailAddress),
+	}})
+}
+
+// Enable Two Factor
+// @Summary Enable two factor
+// @Description Verifies a code of the pending secret, enables two factor and returns recovery codes
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Param twofactorcode body requests.TwoFactorCode true "Two Factor Code"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.RecoveryCodes
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 429 {string} string
+// @Failure 500 {string} string
+// @Router /user/2fa/enable [post]
+func (u *UserController) EnableTwoFactor(c *gin.Context) {
+	var data requests.TwoFactorCode
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(u.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errTwoFactorEnabled,
+		})
+
+		return
+	}
+
+	if user.TwoFactorSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errTwoFactorNoSetup,
+		})
+
+		return
+	}
+
+	if err := helpers.VerifyTwoFactorCode(u.Database, user, data.Code); err != nil {
+		c.JSON(helpers.GetTwoFactorErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	u.setRecoveryCodes(c, uid, "Successfully enabled two factor authentication.")
+}
+
+// Regenerate Recovery Codes
+// @Summary Regenerate recovery codes
+// @Description Replaces the recovery codes, requires the two factor code header
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Param X-TOTP-Code header string true "Two factor code"
+// @Success 200 {object} responses.RecoveryCodes
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 429 {string} string
+// @Failure 500 {string} string
+// @Router /user/2fa/recovery-codes [post]
+func (u *UserController) RegenerateRecoveryCodes(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(u.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if !user.TwoFactorEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errTwoFactorNotEnabled,
+		})
+
+		return
+	}
+
+	u.setRecoveryCodes(c, uid, "Successfully created recovery codes.")
+}
+
+// Disable Two Factor
+// @Summary Disable two factor
+// @Description Disables two factor, requires the two factor code header
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Param X-TOTP-Code header string true "Two factor code"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 429 {string} string
+// @Failure 500 {string} string
+// @Router /user/2fa [delete]
+func (u *UserController) DisableTwoFactor(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(u.Database)
+
+	if err := userModel.DisableTwoFactor(uid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully disabled two factor authentication."})
+}
+
+func (u *UserController) setRecoveryCodes(c *gin.Context, uid, message string) {
+	recoveryCodes, hashes, err := helpers.GenerateRecoveryCodes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	userModel := models.NewUserModel(u.Database)
+	if err := userModel.EnableTwoFactor(uid, hashes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message, "data": responses.RecoveryCodes{
+		RecoveryCodes: recoveryCodes,
+	}})
+}
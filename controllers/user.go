@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"asset_backend/db"
+	"asset_backend/entitlements"
 	"asset_backend/helpers"
 	"asset_backend/models"
 	"asset_backend/requests"
@@ -16,6 +17,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/sethvargo/go-password/password"
+	"github.com/sirupsen/logrus"
 )
 
 type UserController struct {
@@ -29,12 +31,18 @@ func NewUserController(mongoDB *db.MongoDB) UserController {
 }
 
 var (
-	errAlreadyRegistered = "User already registered."
-	errPasswordNoMatch   = "Passwords do not match."
-	errNoUser            = "Sorry, couldn't find user."
-	errOAuthUser         = "Sorry, you can't do this action."
-	errMailAlreadySent   = "Password reset mail already sent, you have to wait 5 minutes before sending another. Please check spam mails."
-	errPremiumFeature    = "This feature requires premium membership."
+	errAlreadyRegistered   = "User already registered."
+	errPasswordNoMatch     = "Passwords do not match."
+	errNoUser              = "Sorry, couldn't find user."
+	errOAuthUser           = "Sorry, you can't do this action."
+	errMailAlreadySent     = "Password reset mail already sent, you have to wait 5 minutes before sending another. Please check spam mails."
+	errPremiumFeature      = "This feature requires premium membership."
+	errUserImportFile      = "Please upload the zip archive created by user export."
+	errReceiptVerification = "Purchase couldn't be verified, please try again."
+	errPurchaseOwner       = "This purchase belongs to another account."
+	errAlreadyVerified     = "Email address is already verified."
+	errVerificationSent    = "Verification mail already sent, you have to wait 5 minutes before sending another. Please check spam mails."
+	errEmailNotVerified    = "Please verify your email address to use this feature."
 )
 
 // Register
@@ -65,7 +73,84 @@ func (u *UserController) Register(c *gin.Context) {
 		return
 	}
 
-	if err := userModel.CreateUser(data); err != nil {
+	verificationToken := uuid.NewString()
+	createdUser, err := userModel.CreateUser(data, verificationToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	categoryModel := models.NewCategoryModel(u.Database)
+	categoryModel.CreateDefaultCategories(createdUser.ID.Hex())
+
+	go func() {
+		if err := helpers.SendVerificationEmail(verificationToken, data.EmailAddress); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"email": data.EmailAddress,
+			}).Error("failed to send verification email: ", err)
+		}
+	}()
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Registered successfully. Please check your email to verify your account."})
+}
+
+// Resend Verification Email
+// @Summary Resend verification email
+// @Description Sends a new email verification link, it can be sent once in 5 minutes
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Failure 429 {string} string
+// @Failure 500 {string} string
+// @Router /user/resend-verification [post]
+func (u *UserController) ResendVerificationEmail(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(u.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if user.IsVerified {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errAlreadyVerified,
+		})
+
+		return
+	}
+
+	const resendTime = 5 * time.Minute
+
+	if isSet, err := db.RedisDB.SetNX(context.TODO(), ("verification-mail/" + uid), true, resendTime).Result(); err == nil && !isSet {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": errVerificationSent,
+		})
+
+		return
+	}
+
+	verificationToken := uuid.NewString()
+	if err := userModel.SetVerificationToken(uid, verificationToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	if err := helpers.SendVerificationEmail(verificationToken, user.EmailAddress); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
@@ -73,7 +158,31 @@ func (u *UserController) Register(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Registered successfully."})
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully send verification email."})
+}
+
+// Confirm Email
+// @Summary Confirm email address
+// @Description Verifies user's email with the token from the verification email and serves the result page
+// @Tags auth
+// @Produce text/html
+// @Param token query string true "Verification token"
+// @Param mail query string true "Email address"
+// @Success 200 {string} string "Confirmation page"
+// @Router /auth/confirm-email [get]
+func (u *UserController) ConfirmEmail(c *gin.Context) {
+	token := c.Query("token")
+	email := c.Query("mail")
+
+	userModel := models.NewUserModel(u.Database)
+
+	isVerified, err := userModel.VerifyUserEmail(token, email)
+	if err != nil || !isVerified {
+		http.ServeFile(c.Writer, c.Request, "assets/error_email_verification.html")
+		return
+	}
+
+	http.ServeFile(c.Writer, c.Request, "assets/confirm_email.html")
 }
 
 // Change Currency
@@ -120,6 +229,48 @@ func (u *UserController) ChangeCurrency(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully changed currency."})
 }
 
+// Change Cost Basis Method
+// @Summary Change User Cost Basis Method
+// @Description Users can change the lot accounting method used for asset profit/loss
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Param changecostbasismethod body requests.ChangeCostBasisMethod true "Set cost basis method"
+// @Security ApiKeyAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {string} string
+// @Failure 500 {string} string
+// @Router /user/change-cost-basis [put]
+func (u *UserController) ChangeCostBasisMethod(c *gin.Context) {
+	var data requests.ChangeCostBasisMethod
+	if shouldReturn := bindJSONData(&data, c); shouldReturn {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(u.Database)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	user.CostBasisMethod = data.CostBasisMethod
+	if err = userModel.UpdateUser(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully changed cost basis method."})
+}
+
 // Update FCM Token
 // @Summary Updates FCM User Token
 // @Description Depending on logged in device fcm token will be updated
@@ -166,14 +317,16 @@ func (u *UserController) UpdateFCMToken(c *gin.Context) {
 
 // Change User Membership
 // @Summary Change User Membership
-// @Description User membership status will be updated depending on subscription status
+// @Description Verifies the App Store or Play Store receipt and updates membership from user's active purchases
 // @Tags user
 // @Accept application/json
 // @Produce application/json
-// @Param changemembership body requests.ChangeMembership true "Set Membership"
+// @Param changemembership body requests.ChangeMembership true "Purchase Receipt"
 // @Security ApiKeyAuth
 // @Param Authorization header string true "Authentication header"
-// @Success 200 {string} string
+// @Success 200 {object} responses.IsUserPremium
+// @Failure 400 {string} string
+// @Failure 403 {string} string
 // @Failure 500 {string} string
 // @Router /user/membership [put]
 func (u *UserController) ChangeUserMembership(c *gin.Context) {
@@ -182,10 +335,53 @@ func (u *UserController) ChangeUserMembership(c *gin.Context) {
 		return
 	}
 
+	verifier, err := helpers.GetReceiptVerifier(data.Platform)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	verification, err := verifier.VerifyReceipt(data.Receipt, data.ProductID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errReceiptVerification,
+		})
+
+		return
+	}
+
 	uid := jwt.ExtractClaims(c)["id"].(string)
+	purchaseModel := models.NewPurchaseModel(u.Database)
 
-	userModel := models.NewUserModel(u.Database)
-	if err := userModel.UpdateUserMembership(uid, data); err != nil {
+	if purchase, err := purchaseModel.GetPurchaseByTransactionID(data.Platform, verification.TransactionID); err == nil && purchase.UserID != uid {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": errPurchaseOwner,
+		})
+
+		return
+	}
+
+	if _, err := purchaseModel.UpsertPurchase(uid, data.Platform, data.Receipt, verification); err != nil {
+		if err == models.ErrPurchaseOwner {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": errPurchaseOwner,
+			})
+
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	membership, err := purchaseModel.UpdateUserMembershipByPurchases(uid)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
@@ -193,7 +389,7 @@ func (u *UserController) ChangeUserMembership(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Successfully updated membership."})
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully updated membership.", "data": membership})
 }
 
 // Change Notification Preference
@@ -249,8 +445,11 @@ func (u *UserController) ChangeNotificationPreference(c *gin.Context) {
 // @Param ChangePassword body requests.ChangePassword true "Set new password"
 // @Security ApiKeyAuth
 // @Param Authorization header string true "Authentication header"
+// @Param X-TOTP-Code header string false "Two factor code"
 // @Success 200 {string} string
 // @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Failure 429 {string} string
 // @Failure 500 {string} string
 // @Router /user/change-password [put]
 func (u *UserController) ChangePassword(c *gin.Context) {
@@ -259,7 +458,8 @@ func (u *UserController) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	uid := jwt.ExtractClaims(c)["id"].(string)
+	claims := jwt.ExtractClaims(c)
+	uid := claims["id"].(string)
 	userModel := models.NewUserModel(u.Database)
 
 	user, err := userModel.FindUserByID(uid)
@@ -296,6 +496,8 @@ func (u *UserController) ChangePassword(c *gin.Context) {
 		return
 	}
 
+	go helpers.RevokeUserSessions(u.Database, uid, helpers.GetSessionID(claims))
+
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully changed password."})
 }
 
@@ -424,6 +626,9 @@ func (u *UserController) ConfirmPasswordReset(c *gin.Context) {
 		return
 	}
 
+	go helpers.RevokeUserSessions(u.Database, user.ID.Hex(), "")
+	go helpers.ResetFailedLogins(user.ID.Hex())
+
 	if err := helpers.SendPasswordChangedEmail(generatedPass, user.EmailAddress); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -458,29 +663,22 @@ func (u *UserController) GetUserInfo(c *gin.Context) {
 	subscritionCount := subscriptionModel.GetUserSubscriptionCount(uid)
 	favInvestingCount := favInvestingModel.GetFavouriteInvestingsCount(uid)
 
-	var (
-		investingLimit    string
-		subscriptionLimit string
-		favInvestingLimit string
-	)
+	plan := entitlements.GetPlan(info.IsPremium || info.IsLifetimePremium)
 
-	if info.IsPremium {
-		investingLimit = fmt.Sprintf("%v", assetCount) + "/∞"
-		subscriptionLimit = fmt.Sprintf("%v", subscritionCount) + "/∞"
-		favInvestingLimit = fmt.Sprintf("%v", favInvestingCount) + "/10"
-	} else {
-		investingLimit = fmt.Sprintf("%v", assetCount) + "/10"
-		subscriptionLimit = fmt.Sprintf("%v", subscritionCount) + "/5"
-		favInvestingLimit = fmt.Sprintf("%v", favInvestingCount) + "/5"
-	}
+	investingLimit := getLimitText(assetCount, plan.GetLimit(entitlements.ResourceAsset))
+	subscriptionLimit := getLimitText(subscritionCount, plan.GetLimit(entitlements.ResourceSubscription))
+	favInvestingLimit := getLimitText(favInvestingCount, plan.GetLimit(entitlements.ResourceWatchlist))
 
 	userInfo := responses.UserInfo{
 		IsPremium:         info.IsPremium,
 		IsLifetimePremium: info.IsLifetimePremium,
 		IsOAuth:           info.IsOAuthUser,
+		IsTwoFactor:       info.TwoFactorEnabled,
+		IsVerified:        info.IsVerified,
 		AppNotification:   info.AppNotification,
 		EmailAddress:      info.EmailAddress,
 		Currency:          info.Currency,
+		CostBasisMethod:   info.CostBasisMethod,
 		FCMToken:          info.FCMToken,
 		InvestingLimit:    investingLimit,
 		SubscriptionLimit: subscriptionLimit,
@@ -490,6 +688,148 @@ func (u *UserController) GetUserInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched user info.", "data": userInfo})
 }
 
+// User Entitlements
+// @Summary User plan usage
+// @Description Returns usage and limits of every resource for user's plan
+// @Tags user
+// @Accept application/json
+// @Produce application/json
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 200 {object} responses.Entitlements
+// @Router /user/entitlements [get]
+func (u *UserController) GetEntitlements(c *gin.Context) {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+
+	quotaService := entitlements.NewQuotaService(u.Database)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully fetched entitlements.", "data": quotaService.GetEntitlements(uid)})
+}
+
+func getLimitText(usage, limit int64) string {
+	if limit == entitlements.Unlimited {
+		return fmt.Sprintf("%v/∞", usage)
+	}
+
+	return fmt.Sprintf("%v/%v", usage, limit)
+}
+
+// Export User Data
+// @Summary Exports user data
+// @Description Returns a zip archive with JSON and CSV files of every collection owned by user. Subscription account passwords are only included on opt-in.
+// @Tags user
+// @Accept application/json
+// @Produce application/zip
+// @Param userexport query requests.UserExport false "User Export"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Param X-TOTP-Code header string false "Two factor code, required for passwords"
+// @Success 200 {file} file
+// @Failure 403 {string} string
+// @Failure 429 {string} string
+// @Failure 500 {string} string
+// @Router /user/export [get]
+func (u *UserController) ExportUserData(c *gin.Context) {
+	var data requests.UserExport
+	if err := c.ShouldBindQuery(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": validatorErrorHandler(err),
+		})
+
+		return
+	}
+
+	if data.IncludePasswords && !helpers.RequireTwoFactor(c, u.Database) {
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userDataModel := models.NewUserDataModel(u.Database)
+
+	userDataCollections, err := userDataModel.GetUserExportData(uid, data.IncludePasswords)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	fileName := "kanma-export-" + time.Now().UTC().Format("2006-01-02") + ".zip"
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", "attachment; filename=\""+fileName+"\"")
+	c.Status(http.StatusOK)
+
+	if err := helpers.WriteUserDataArchive(c.Writer, uid, data.IncludePasswords, userDataCollections); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to write user data archive: ", err)
+	}
+}
+
+// Import User Data
+// @Summary Imports user data
+// @Description Recreates cards, bank accounts, categories, subscriptions, transactions, custom asset valuations, assets and watchlist from an export archive. Assets and transactions are validated like created ones. Items that are invalid, already exist or exceed membership limits are reported as conflicts.
+// @Tags user
+// @Accept multipart/form-data
+// @Produce application/json
+// @Param file formData file true "Export Archive"
+// @Security BearerAuth
+// @Param Authorization header string true "Authentication header"
+// @Success 201 {object} responses.UserDataImport
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /user/import [post]
+func (u *UserController) ImportUserData(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errUserImportFile,
+		})
+
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": errUserImportFile,
+		})
+
+		return
+	}
+	defer file.Close()
+
+	manifest, collections, err := helpers.ReadUserDataArchive(file, fileHeader.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userDataModel := models.NewUserDataModel(u.Database)
+
+	limits := entitlements.NewQuotaService(u.Database).GetUserPlan(uid).GetUserDataImportLimits()
+
+	userDataImport, err := userDataModel.ImportUserData(uid, limits, manifest.IncludePasswords, collections)
+
+	go db.RedisDB.Del(context.TODO(), ("card/" + uid), ("ba/" + uid), ("subscription/" + uid), ("watchlist/" + uid))
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Successfully imported user data.", "data": userDataImport})
+}
+
 // Delete User
 // @Summary Deletes user information
 // @Description Deletes everything related to user
@@ -498,7 +838,10 @@ func (u *UserController) GetUserInfo(c *gin.Context) {
 // @Produce application/json
 // @Security BearerAuth
 // @Param Authorization header string true "Authentication header"
+// @Param X-TOTP-Code header string false "Two factor code"
 // @Success 200 {string} string
+// @Failure 403 {string} string
+// @Failure 429 {string} string
 // @Error 500 {string} string
 // @Router /user [delete]
 func (u *UserController) DeleteUser(c *gin.Context) {
@@ -521,16 +864,32 @@ func (u *UserController) DeleteUser(c *gin.Context) {
 	transactionModel := models.NewTransactionModel(u.Database)
 	bankAccModel := models.NewBankAccountModel(u.Database)
 	favInvestingModel := models.NewFavouriteInvestingModel(u.Database)
+	priceAlertModel := models.NewPriceAlertModel(u.Database)
+	allocationModel := models.NewAllocationTargetModel(u.Database)
+	customModel := models.NewCustomAssetValuationModel(u.Database)
+	netWorthModel := models.NewNetWorthModel(u.Database)
+	purchaseModel := models.NewPurchaseModel(u.Database)
+	budgetModel := models.NewBudgetModel(u.Database)
+	categoryModel := models.NewCategoryModel(u.Database)
 
 	go assetModel.DeleteAllAssetsByUserID(uid)
 	go cardModel.DeleteAllCardsByUserID(uid)
 	go subscriptionModel.DeleteAllSubscriptionsByUserID(uid)
 	go subscriptionModel.DeleteAllSubscriptionInvitesByUserID(uid)
 	go dasModel.DeleteAllAssetStatsByUserID(uid)
+	go netWorthModel.DeleteAllNetWorthStatsByUserID(uid)
 	go logModel.DeleteAllLogsByUserID(uid)
 	go transactionModel.DeleteAllTransactionsByUserID(uid)
+	go transactionModel.DeleteAllRecurringTransactionsByUserID(uid)
+	go budgetModel.DeleteAllBudgetsByUserID(uid)
+	go categoryModel.DeleteAllCategoriesByUserID(uid)
 	go bankAccModel.DeleteAllBankAccountsByUserID(uid)
 	go favInvestingModel.DeleteAllFavouriteInvestingsByUserID(uid)
+	go priceAlertModel.DeleteAllPriceAlertsByUserID(uid)
+	go allocationModel.DeleteAllocationTargetByUserID(uid)
+	go customModel.DeleteCustomAssetValuationsByUserID(uid)
+	go purchaseModel.DeleteAllPurchasesByUserID(uid)
+	go helpers.RevokeUserSessions(u.Database, uid, "")
 
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully deleted user."})
 }
@@ -24,14 +24,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/asset": {
+        "/admin/corporate-action": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns assets and stats by user id",
+                "description": "Returns all corporate actions",
                 "consumes": [
                     "application/json"
                 ],
@@ -39,41 +34,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "asset"
+                    "admin"
                 ],
-                "summary": "Get Assets \u0026 Stats by User ID",
+                "summary": "Get Corporate Actions",
                 "parameters": [
                     {
                         "type": "string",
-                        "name": "assetType",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "name",
-                            "percentage",
-                            "amount",
-                            "profit"
-                        ],
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            1,
-                            -1
-                        ],
-                        "type": "integer",
-                        "name": "type",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
                         "in": "header",
                         "required": true
                     }
@@ -82,11 +50,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.AssetAndStats"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CorporateAction"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
@@ -99,13 +70,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Updates asset log by id",
+            "post": {
+                "description": "Registers a stock split or symbol change, asset logs created before the effective date are adjusted while aggregating",
                 "consumes": [
                     "application/json"
                 ],
@@ -113,32 +79,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "asset"
+                    "admin"
                 ],
-                "summary": "Update Asset Log by AssetID",
+                "summary": "Create Corporate Action",
                 "parameters": [
                     {
-                        "description": "Asset Update",
-                        "name": "assetupdate",
+                        "description": "Corporate Action Create",
+                        "name": "corporateactioncreate",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.AssetUpdate"
+                            "$ref": "#/definitions/requests.CorporateActionCreate"
                         }
                     },
                     {
                         "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
                         "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.CorporateAction"
                         }
                     },
                     "400": {
@@ -147,14 +113,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Unauthorized update",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Couldn't find user",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
@@ -167,13 +127,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Creates asset",
+            "delete": {
+                "description": "Deletes corporate action, chained symbol changes aren't reverted",
                 "consumes": [
                     "application/json"
                 ],
@@ -181,36 +136,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "asset"
+                    "admin"
                 ],
-                "summary": "Create Asset",
+                "summary": "Delete Corporate Action",
                 "parameters": [
                     {
-                        "description": "Asset Create",
-                        "name": "assetcreate",
+                        "description": "Corporate Action ID",
+                        "name": "ID",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.AssetCreate"
+                            "$ref": "#/definitions/requests.ID"
                         }
                     },
                     {
                         "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
                         "in": "header",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
@@ -222,14 +189,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Deletes all assets by user id",
+            }
+        },
+        "/admin/corporate-action/preview": {
+            "post": {
+                "description": "Returns the users and asset logs that would be adjusted by the corporate action without creating it",
                 "consumes": [
                     "application/json"
                 ],
@@ -237,14 +201,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "asset"
+                    "admin"
                 ],
-                "summary": "Delete All Assets by User ID",
+                "summary": "Preview Corporate Action",
                 "parameters": [
+                    {
+                        "description": "Corporate Action Create",
+                        "name": "corporateactioncreate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CorporateActionCreate"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
                         "in": "header",
                         "required": true
                     }
@@ -252,6 +225,18 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.CorporateActionPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
@@ -265,14 +250,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/asset/daily-stats": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns daily asset stats by user id",
+        "/admin/daily-asset-stats/backfill": {
+            "post": {
+                "description": "Reconstructs missing daily asset stats from asset logs, existing days are kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -280,25 +260,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "asset"
+                    "admin"
                 ],
-                "summary": "Get Daily Asset Stats by User ID",
+                "summary": "Backfill Daily Asset Stats",
                 "parameters": [
                     {
-                        "enum": [
-                            "weekly",
-                            "monthly",
-                            "yearly"
-                        ],
-                        "type": "string",
-                        "name": "interval",
-                        "in": "query",
-                        "required": true
+                        "description": "Daily Asset Stats Backfill",
+                        "name": "dailyassetstatsbackfill",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.DailyAssetStatsBackfill"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
+                        "description": "Admin key",
+                        "name": "X-Admin-Key",
                         "in": "header",
                         "required": true
                     }
@@ -307,7 +285,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.DailyAssetStats"
+                            "$ref": "#/definitions/responses.DailyAssetStatsBackfill"
                         }
                     },
                     "400": {
@@ -316,6 +294,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -325,14 +309,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/asset/details": {
+        "/asset": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns asset stats by asset and user id",
+                "description": "Returns assets and stats by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -342,23 +326,32 @@ const docTemplate = `{
                 "tags": [
                     "asset"
                 ],
-                "summary": "Get Asset Stats by Asset and User ID",
+                "summary": "Get Assets \u0026 Stats by User ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "name": "asset_market",
-                        "in": "query",
-                        "required": true
+                        "name": "assetType",
+                        "in": "query"
                     },
                     {
+                        "enum": [
+                            "name",
+                            "percentage",
+                            "amount",
+                            "profit"
+                        ],
                         "type": "string",
-                        "name": "from_asset",
+                        "name": "sort",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "name": "to_asset",
+                        "enum": [
+                            1,
+                            -1
+                        ],
+                        "type": "integer",
+                        "name": "type",
                         "in": "query",
                         "required": true
                     },
@@ -374,7 +367,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.AssetDetails"
+                            "$ref": "#/definitions/responses.AssetAndStats"
                         }
                     },
                     "400": {
@@ -390,16 +383,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/asset/log": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates asset log",
+                "description": "Updates asset log by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -409,15 +400,15 @@ const docTemplate = `{
                 "tags": [
                     "asset"
                 ],
-                "summary": "Create Asset Log",
+                "summary": "Update Asset Log by AssetID",
                 "parameters": [
                     {
-                        "description": "Asset Create",
-                        "name": "assetcreate",
+                        "description": "Asset Update",
+                        "name": "assetupdate",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.AssetCreate"
+                            "$ref": "#/definitions/requests.AssetUpdate"
                         }
                     },
                     {
@@ -429,8 +420,26 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized update",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Couldn't find user",
                         "schema": {
                             "type": "string"
                         }
@@ -443,13 +452,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes asset log by id",
+                "description": "Creates asset",
                 "consumes": [
                     "application/json"
                 ],
@@ -459,15 +468,15 @@ const docTemplate = `{
                 "tags": [
                     "asset"
                 ],
-                "summary": "Delete Asset Log by AssetID",
+                "summary": "Create Asset",
                 "parameters": [
                     {
-                        "description": "Asset ID",
-                        "name": "ID",
+                        "description": "Asset Create",
+                        "name": "assetcreate",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.ID"
+                            "$ref": "#/definitions/requests.AssetCreate"
                         }
                     },
                     {
@@ -479,14 +488,14 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -498,16 +507,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/asset/logs": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns asset logs by user id",
+                "description": "Deletes all assets by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -517,44 +524,8 @@ const docTemplate = `{
                 "tags": [
                     "asset"
                 ],
-                "summary": "Get Asset Logs by User ID",
+                "summary": "Delete All Assets by User ID",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "name": "asset_market",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "name": "from_asset",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            "newest",
-                            "oldest",
-                            "amount"
-                        ],
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "name": "to_asset",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -566,15 +537,6 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Asset"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -586,14 +548,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/asset/allocation": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes all asset logs by user id",
+                "description": "Returns target allocation of user",
                 "consumes": [
                     "application/json"
                 ],
@@ -603,17 +567,8 @@ const docTemplate = `{
                 "tags": [
                     "asset"
                 ],
-                "summary": "Delete Asset Logs by User ID",
+                "summary": "Get Allocation Targets",
                 "parameters": [
-                    {
-                        "description": "Asset Logs Delete",
-                        "name": "assetlogsdelete",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.AssetLogsDelete"
-                        }
-                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -626,32 +581,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.AllocationTarget"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/asset/stats": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns asset stats by user id",
+                "description": "Replaces target allocation of user. Targets are asset types or to_asset symbols and must add up to 100.",
                 "consumes": [
                     "application/json"
                 ],
@@ -661,8 +608,17 @@ const docTemplate = `{
                 "tags": [
                     "asset"
                 ],
-                "summary": "Get Asset Stats by User ID",
+                "summary": "Set Allocation Targets",
                 "parameters": [
+                    {
+                        "description": "Allocation Target Update",
+                        "name": "allocationtarget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.AllocationTargetUpdate"
+                        }
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -675,7 +631,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.AssetStats"
+                            "$ref": "#/definitions/models.AllocationTarget"
                         }
                     },
                     "400": {
@@ -691,11 +647,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Allows users to register",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes target allocation of user",
                 "consumes": [
                     "application/json"
                 ],
@@ -703,29 +662,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "asset"
                 ],
-                "summary": "User Registration",
+                "summary": "Delete Allocation Targets",
                 "parameters": [
                     {
-                        "description": "User registration info",
-                        "name": "register",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.Register"
-                        }
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "string"
                         }
@@ -739,14 +690,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/ba": {
+        "/asset/custom/valuation": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns bank accounts by user id",
+                "description": "Returns valuations of custom assets that aren't in investings",
                 "consumes": [
                     "application/json"
                 ],
@@ -754,9 +705,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bankaccount"
+                    "asset"
                 ],
-                "summary": "Get Bank Accounts by User ID",
+                "summary": "Get Custom Asset Valuations",
                 "parameters": [
                     {
                         "type": "string",
@@ -772,7 +723,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.BankAccount"
+                                "$ref": "#/definitions/models.CustomAssetValuation"
                             }
                         }
                     },
@@ -790,7 +741,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates bank account",
+                "description": "Updates the unit price of a custom asset, it's used to value the asset from now on",
                 "consumes": [
                     "application/json"
                 ],
@@ -798,17 +749,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bankaccount"
+                    "asset"
                 ],
-                "summary": "Update Bank Account",
+                "summary": "Update Custom Asset Valuation",
                 "parameters": [
                     {
-                        "description": "BankAccount Update",
-                        "name": "bankaccountupdate",
+                        "description": "Custom Asset Valuation Update",
+                        "name": "customassetvaluation",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.BankAccountUpdate"
+                            "$ref": "#/definitions/requests.CustomAssetValuationUpdate"
                         }
                     },
                     {
@@ -823,7 +774,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.BankAccount"
+                            "$ref": "#/definitions/models.CustomAssetValuation"
                         }
                     },
                     "400": {
@@ -832,14 +783,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Unauthorized update",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "404": {
-                        "description": "Couldn't find user",
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
@@ -851,14 +796,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/asset/daily-stats": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates bank account",
+                "description": "Returns daily asset stats by user id, custom and all ranges can be downsampled to buckets",
                 "consumes": [
                     "application/json"
                 ],
@@ -866,18 +813,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bankaccount"
+                    "asset"
                 ],
-                "summary": "Create BankAccount",
+                "summary": "Get Daily Asset Stats by User ID",
                 "parameters": [
                     {
-                        "description": "Bank Account Create",
-                        "name": "bankaccount",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.BankAccountCreate"
-                        }
+                        "enum": [
+                            "daily",
+                            "weekly",
+                            "monthly"
+                        ],
+                        "type": "string",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "weekly",
+                            "monthly",
+                            "yearly",
+                            "custom",
+                            "all"
+                        ],
+                        "type": "string",
+                        "name": "interval",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "startDate",
+                        "in": "query"
                     },
                     {
                         "type": "string",
@@ -888,14 +859,14 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.BankAccount"
+                            "$ref": "#/definitions/responses.DailyAssetStats"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -907,14 +878,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/asset/daily-stats/holding": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes bank account by id",
+                "description": "Returns daily amount, price, value and p/l of a holding in user's currency, yearly, long custom and all ranges are downsampled to weekly or monthly buckets",
                 "consumes": [
                     "application/json"
                 ],
@@ -922,18 +895,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bankaccount"
+                    "asset"
                 ],
-                "summary": "Delete bank account by bank account id",
+                "summary": "Get Daily Holding Stats by User ID",
                 "parameters": [
                     {
-                        "description": "ID",
-                        "name": "ID",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.ID"
-                        }
+                        "type": "string",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "fromAsset",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "weekly",
+                            "monthly",
+                            "yearly",
+                            "custom",
+                            "all"
+                        ],
+                        "type": "string",
+                        "name": "interval",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "toAsset",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
@@ -946,6 +945,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.DailyAssetHoldingStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -959,14 +964,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/ba/all": {
-            "delete": {
+        "/asset/daily-stats/type": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes all bank accounts by user id",
+                "description": "Returns daily value and p/l of an asset type in user's currency, yearly, long custom and all ranges are downsampled to weekly or monthly buckets",
                 "consumes": [
                     "application/json"
                 ],
@@ -974,10 +979,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bankaccount"
+                    "asset"
                 ],
-                "summary": "Delete all bank accounts by user id",
+                "summary": "Get Daily Asset Type Stats by User ID",
                 "parameters": [
+                    {
+                        "enum": [
+                            "crypto",
+                            "stock",
+                            "exchange",
+                            "commodity",
+                            "custom"
+                        ],
+                        "type": "string",
+                        "name": "assetType",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "weekly",
+                            "monthly",
+                            "yearly",
+                            "custom",
+                            "all"
+                        ],
+                        "type": "string",
+                        "name": "interval",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "startDate",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -989,6 +1030,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.DailyAssetTypeStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -1002,14 +1049,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/ba/stats": {
+        "/asset/details": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns bank accounts stats",
+                "description": "Returns asset stats by asset and user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1017,10 +1064,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bankaccount"
+                    "asset"
                 ],
-                "summary": "Get Bank Accounts Stats",
+                "summary": "Get Asset Stats by Asset and User ID",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "name": "asset_market",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "from_asset",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "to_asset",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -1033,7 +1098,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.TransactionTotal"
+                            "$ref": "#/definitions/responses.AssetDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -1045,22 +1116,76 @@ const docTemplate = `{
                 }
             }
         },
-        "/callback": {
-            "get": {
-                "description": "Callback from google auth",
+        "/asset/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Imports asset logs from a CSV export of up to 5 MB and 5000 rows. Rows are validated one by one and only valid rows are inserted.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "oauth2"
+                    "asset"
+                ],
+                "summary": "Import Asset Trade History",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV File",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "assetMarket",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "dryRun",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "generic",
+                            "binance",
+                            "coinbase"
+                        ],
+                        "type": "string",
+                        "name": "profile",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Callback from Google OAuth",
                 "responses": {
                     "200": {
-                        "description": "Token",
+                        "description": "Dry run result",
+                        "schema": {
+                            "$ref": "#/definitions/responses.AssetImport"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.AssetImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -1074,14 +1199,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/card": {
-            "get": {
+        "/asset/log": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns cards by user id",
+                "description": "Creates asset log",
                 "consumes": [
                     "application/json"
                 ],
@@ -1089,10 +1214,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "card"
+                    "asset"
                 ],
-                "summary": "Get Cards by User ID",
+                "summary": "Create Asset Log",
                 "parameters": [
+                    {
+                        "description": "Asset Create",
+                        "name": "assetcreate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.AssetCreate"
+                        }
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -1102,13 +1236,10 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Card"
-                            }
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -1119,13 +1250,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates card",
+                "description": "Deletes asset log by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1133,17 +1264,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "card"
+                    "asset"
                 ],
-                "summary": "Update Card",
+                "summary": "Delete Asset Log by AssetID",
                 "parameters": [
                     {
-                        "description": "Card Update",
-                        "name": "cardupdate",
+                        "description": "Asset ID",
+                        "name": "ID",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.CardUpdate"
+                            "$ref": "#/definitions/requests.ID"
                         }
                     },
                     {
@@ -1158,7 +1289,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Card"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1167,18 +1298,6 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Unauthorized update",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Couldn't find user",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1186,14 +1305,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/asset/logs": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates card",
+                "description": "Returns asset logs by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1201,18 +1322,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "card"
+                    "asset"
                 ],
-                "summary": "Create Card",
+                "summary": "Get Asset Logs by User ID",
                 "parameters": [
                     {
-                        "description": "Card Create",
-                        "name": "card",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.Card"
-                        }
+                        "type": "string",
+                        "name": "asset_market",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "from_asset",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "newest",
+                            "oldest",
+                            "amount"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "to_asset",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
@@ -1223,14 +1371,17 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Card"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Asset"
+                            }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -1249,7 +1400,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes card by id",
+                "description": "Deletes all asset logs by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1257,17 +1408,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "card"
+                    "asset"
                 ],
-                "summary": "Delete card by card id",
+                "summary": "Delete Asset Logs by User ID",
                 "parameters": [
                     {
-                        "description": "ID",
-                        "name": "ID",
+                        "description": "Asset Logs Delete",
+                        "name": "assetlogsdelete",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.ID"
+                            "$ref": "#/definitions/requests.AssetLogsDelete"
                         }
                     },
                     {
@@ -1285,6 +1436,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1294,14 +1451,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/card/all": {
-            "delete": {
+        "/asset/performance": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes all cards by user id",
+                "description": "Returns time-weighted return and XIRR per holding, per asset type and for the portfolio in user's currency. Range defaults to first asset log until today.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1309,10 +1466,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "card"
+                    "asset"
                 ],
-                "summary": "Delete all cards by user id",
+                "summary": "Get Asset Performance by User ID",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "startDate",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -1324,6 +1491,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.AssetPerformance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -1337,14 +1510,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/card/stats": {
+        "/asset/rebalance": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns card statistics",
+                "description": "Compares current values with allocation targets and returns buy/sell values in user's currency. Drifts within tolerance and trades smaller than min trade size are held.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1352,9 +1525,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "card"
+                    "asset"
                 ],
-                "summary": "Get Card Statistics by User ID \u0026 Card ID",
+                "summary": "Get Rebalancing Suggestions",
                 "parameters": [
                     {
                         "type": "string",
@@ -1368,10 +1541,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/responses.CardStats"
-                            }
+                            "$ref": "#/definitions/responses.AssetRebalance"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -1383,9 +1559,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/investings": {
+        "/asset/stats": {
             "get": {
-                "description": "Returns investing list by type and market",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns asset stats by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1393,20 +1574,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investing"
+                    "asset"
                 ],
-                "summary": "Get Investings by Type and Market",
+                "summary": "Get Asset Stats by User ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "name": "market",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "name": "type",
-                        "in": "query",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -1414,10 +1590,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/responses.InvestingResponse"
-                            }
+                            "$ref": "#/definitions/responses.AssetStats"
                         }
                     },
                     "400": {
@@ -1435,9 +1608,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/investings/prices": {
+        "/asset/tax-report": {
             "get": {
-                "description": "Returns investing price table by type and market",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns per disposal capital gains and yearly totals in user's currency, cost basis is converted at the acquisition date rate and proceeds at the disposal date rate",
                 "consumes": [
                     "application/json"
                 ],
@@ -1445,20 +1623,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "investing"
+                    "asset"
                 ],
-                "summary": "Get Investing Price Table by Type and Market",
+                "summary": "Get Capital Gains Tax Report by User ID",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "market",
+                        "minimum": 1970,
+                        "type": "integer",
+                        "name": "year",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "name": "type",
-                        "in": "query",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -1466,10 +1646,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/responses.InvestingTableResponse"
-                            }
+                            "$ref": "#/definitions/responses.AssetTaxReport"
                         }
                     },
                     "400": {
@@ -1487,14 +1664,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/log": {
+        "/auth/2fa": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Creates Log",
+                "description": "Verifies the TOTP or recovery code of the login challenge and returns tokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -1502,30 +1674,83 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "logs"
+                    "auth"
                 ],
-                "summary": "Create Log",
+                "summary": "Second step of login",
                 "parameters": [
                     {
-                        "description": "Log Create",
-                        "name": "log",
+                        "description": "Two Factor Login",
+                        "name": "twofactorlogin",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.CreateLog"
+                            "$ref": "#/definitions/requests.TwoFactorLogin"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
                         }
+                    }
+                }
+            }
+        },
+        "/auth/confirm-email": {
+            "get": {
+                "description": "Verifies user's email with the token from the verification email and serves the result page",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Confirm email address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Verification token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
-                        "in": "header",
+                        "description": "Email address",
+                        "name": "mail",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Confirmation page",
                         "schema": {
                             "type": "string"
                         }
@@ -1533,9 +1758,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/oauth/apple": {
+        "/auth/logout": {
             "post": {
-                "description": "Gets user info from apple and creates/finds user and returns token",
+                "description": "Revokes the session of the access token",
                 "consumes": [
                     "application/json"
                 ],
@@ -1543,18 +1768,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "oauth2"
+                    "auth"
+                ],
+                "summary": "Logout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "OAuth2 Apple Login",
                 "responses": {
                     "200": {
-                        "description": "Token",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                        "description": "OK",
                         "schema": {
                             "type": "string"
                         }
@@ -1562,9 +1790,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/oauth/google": {
+        "/auth/refresh": {
             "post": {
-                "description": "Gets user info from google and creates/finds user and returns token",
+                "description": "Returns a new access token and rotates the refresh token, reusing an old refresh token revokes the session",
                 "consumes": [
                     "application/json"
                 ],
@@ -1572,9 +1800,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "oauth2"
+                    "auth"
+                ],
+                "summary": "Refreshes access token",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "refreshtoken",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.RefreshToken"
+                        }
+                    }
                 ],
-                "summary": "OAuth2 Google Login",
                 "responses": {
                     "200": {
                         "description": "Token",
@@ -1582,6 +1821,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1591,14 +1836,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscription": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns subscriptions and stats by user id",
+        "/auth/register": {
+            "post": {
+                "description": "Allows users to register",
                 "consumes": [
                     "application/json"
                 ],
@@ -1606,45 +1846,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "auth"
                 ],
-                "summary": "Subscriptions and Stats by User ID",
+                "summary": "User Registration",
                 "parameters": [
                     {
-                        "enum": [
-                            "name",
-                            "currency",
-                            "price",
-                            "date"
-                        ],
-                        "type": "string",
-                        "name": "sort",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "enum": [
-                            1,
-                            -1
-                        ],
-                        "type": "integer",
-                        "name": "type",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "User registration info",
+                        "name": "register",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.Register"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/responses.SubscriptionAndStats"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -1660,14 +1880,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/ba": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates subscription",
+                "description": "Returns bank accounts by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1675,17 +1897,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "bankaccount"
                 ],
-                "summary": "Update Subscription",
+                "summary": "Get Bank Accounts by User ID",
                 "parameters": [
                     {
-                        "description": "Subscription Update",
-                        "name": "subscriptionupdate",
-                        "in": "body",
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BankAccount"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates bank account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bankaccount"
+                ],
+                "summary": "Update Bank Account",
+                "parameters": [
+                    {
+                        "description": "BankAccount Update",
+                        "name": "bankaccountupdate",
+                        "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.SubscriptionUpdate"
+                            "$ref": "#/definitions/requests.BankAccountUpdate"
                         }
                     },
                     {
@@ -1700,7 +1966,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.Subscription"
+                            "$ref": "#/definitions/models.BankAccount"
                         }
                     },
                     "400": {
@@ -1735,7 +2001,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates subscription",
+                "description": "Creates bank account",
                 "consumes": [
                     "application/json"
                 ],
@@ -1743,17 +2009,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "bankaccount"
                 ],
-                "summary": "Create Subscription",
+                "summary": "Create BankAccount",
                 "parameters": [
                     {
-                        "description": "Subscription Create",
-                        "name": "subscription",
+                        "description": "Bank Account Create",
+                        "name": "bankaccount",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.Subscription"
+                            "$ref": "#/definitions/requests.BankAccountCreate"
                         }
                     },
                     {
@@ -1768,13 +2034,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/responses.Subscription"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.BankAccount"
                         }
                     },
                     "403": {
@@ -1797,7 +2057,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes subscription by id",
+                "description": "Deletes bank account by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1805,9 +2065,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "bankaccount"
                 ],
-                "summary": "Delete subscription by subscription id",
+                "summary": "Delete bank account by bank account id",
                 "parameters": [
                     {
                         "description": "ID",
@@ -1842,14 +2102,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscription/all": {
+        "/ba/all": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes all subscriptions by user id",
+                "description": "Deletes all bank accounts by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1857,9 +2117,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "bankaccount"
                 ],
-                "summary": "Delete all subscriptions by user id",
+                "summary": "Delete all bank accounts by user id",
                 "parameters": [
                     {
                         "type": "string",
@@ -1885,14 +2145,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscription/cancel": {
-            "post": {
+        "/ba/stats": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cancels subscription invitation",
+                "description": "Returns bank accounts stats",
                 "consumes": [
                     "application/json"
                 ],
@@ -1900,16 +2160,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "bankaccount"
                 ],
-                "summary": "Cancel Subscription Invitation",
+                "summary": "Get Bank Accounts Stats",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -1922,13 +2176,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/responses.TransactionTotal"
                         }
                     },
                     "500": {
@@ -1940,14 +2188,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscription/card": {
+        "/budget": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns subscriptions by card id",
+                "description": "Returns spent, remaining and projected values of current month for every budget",
                 "consumes": [
                     "application/json"
                 ],
@@ -1955,16 +2203,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "budget"
                 ],
-                "summary": "Get Subscriptions by Card ID",
+                "summary": "Get Budget Status",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -1977,16 +2219,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Subscription"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/responses.BudgetStatus"
                         }
                     },
                     "500": {
@@ -1996,16 +2229,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/subscription/details": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns subscription details",
+                "description": "Updates budget limit",
                 "consumes": [
                     "application/json"
                 ],
@@ -2013,15 +2244,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "budget"
                 ],
-                "summary": "Get Subscription Details",
+                "summary": "Update Budget",
                 "parameters": [
                     {
-                        "type": "string",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Budget Update",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.BudgetUpdate"
+                        }
                     },
                     {
                         "type": "string",
@@ -2035,7 +2269,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.SubscriptionDetails"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
@@ -2044,8 +2278,8 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -2057,16 +2291,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/subscription/invitation": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Invited users can accept/deny invitation",
+                "description": "Creates monthly budget for a category in user's currency",
                 "consumes": [
                     "application/json"
                 ],
@@ -2074,17 +2306,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "budget"
                 ],
-                "summary": "Handles subscription invitation response",
+                "summary": "Create Budget",
                 "parameters": [
                     {
-                        "description": "SubscriptionInvitation",
-                        "name": "subscriptioninvitation",
+                        "description": "Budget Create",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.SubscriptionInvitation"
+                            "$ref": "#/definitions/requests.BudgetCreate"
                         }
                     },
                     {
@@ -2096,10 +2328,10 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
@@ -2108,6 +2340,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2115,16 +2353,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/subscription/invite": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Invites another user by email to subscription, if accepted they can view it",
+                "description": "Deletes budget by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2132,17 +2368,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "budget"
                 ],
-                "summary": "Sents invitation to another user for access to subscription.",
+                "summary": "Delete budget by budget id",
                 "parameters": [
                     {
-                        "description": "SubscriptionInvite",
-                        "name": "subscriptioninvite",
+                        "description": "ID",
+                        "name": "ID",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.SubscriptionInvite"
+                            "$ref": "#/definitions/requests.ID"
                         }
                     },
                     {
@@ -2160,12 +2396,6 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2175,14 +2405,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscription/shared": {
+        "/callback": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Returns shared subscriptions by user id",
+                "description": "Callback from google auth",
                 "consumes": [
                     "application/json"
                 ],
@@ -2190,36 +2415,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
-                ],
-                "summary": "Get Shared Subscriptions by User ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    }
+                    "oauth2"
                 ],
+                "summary": "Callback from Google OAuth",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Subscription"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "Token",
                         "schema": {
                             "type": "string"
                         }
@@ -2233,14 +2434,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/subscription/stats": {
+        "/card": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns subscription statistics",
+                "description": "Returns cards by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2248,9 +2449,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscription"
+                    "card"
                 ],
-                "summary": "Get Subscription Statistics by User ID",
+                "summary": "Get Cards by User ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -2266,7 +2467,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/responses.SubscriptionStatistics"
+                                "$ref": "#/definitions/models.Card"
                             }
                         }
                     },
@@ -2277,16 +2478,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/transaction": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns transactions by user id",
+                "description": "Updates card",
                 "consumes": [
                     "application/json"
                 ],
@@ -2294,61 +2493,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "card"
                 ],
-                "summary": "Get Transactions by User ID",
+                "summary": "Update Card",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Transaction"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Updates transaction",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "transaction"
-                ],
-                "summary": "Update Transaction",
-                "parameters": [
-                    {
-                        "description": "Transaction Update",
-                        "name": "transactionupdate",
+                        "description": "Card Update",
+                        "name": "cardupdate",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.TransactionUpdate"
+                            "$ref": "#/definitions/requests.CardUpdate"
                         }
                     },
                     {
@@ -2363,7 +2518,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Card"
                         }
                     },
                     "400": {
@@ -2398,7 +2553,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates transaction",
+                "description": "Creates card",
                 "consumes": [
                     "application/json"
                 ],
@@ -2406,17 +2561,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "card"
                 ],
-                "summary": "Create Transaction",
+                "summary": "Create Card",
                 "parameters": [
                     {
-                        "description": "Transaction Create",
-                        "name": "transaction",
+                        "description": "Card Create",
+                        "name": "card",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.TransactionCreate"
+                            "$ref": "#/definitions/requests.Card"
                         }
                     },
                     {
@@ -2431,11 +2586,11 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Card"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -2454,7 +2609,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes transaction by id",
+                "description": "Deletes card by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2462,9 +2617,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "card"
                 ],
-                "summary": "Delete transaction by transaction id",
+                "summary": "Delete card by card id",
                 "parameters": [
                     {
                         "description": "ID",
@@ -2499,14 +2654,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction/all": {
+        "/card/all": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes all transaction by user id",
+                "description": "Deletes all cards by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2514,9 +2669,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "card"
                 ],
-                "summary": "Delete all transaction by user id",
+                "summary": "Delete all cards by user id",
                 "parameters": [
                     {
                         "type": "string",
@@ -2542,14 +2697,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction/stats": {
+        "/card/stats": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns transaction statistics by interval",
+                "description": "Returns card statistics",
                 "consumes": [
                     "application/json"
                 ],
@@ -2557,9 +2712,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "card"
                 ],
-                "summary": "Get Transaction Statistics by Interval",
+                "summary": "Get Card Statistics by User ID \u0026 Card ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -2575,7 +2730,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/responses.TransactionStats"
+                                "$ref": "#/definitions/responses.CardStats"
                             }
                         }
                     },
@@ -2588,14 +2743,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction/total": {
+        "/category": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns total transaction value by interval",
+                "description": "Returns user's categories, default categories are created on registration",
                 "consumes": [
                     "application/json"
                 ],
@@ -2603,10 +2758,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "category"
                 ],
-                "summary": "Get Total Transaction Value by Interval",
+                "summary": "Get Categories by User ID",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "name": "includeArchived",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -2619,7 +2779,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.TransactionTotal"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
                         }
                     },
                     "500": {
@@ -2629,16 +2792,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user": {
-            "delete": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes everything related to user",
+                "description": "Renames, recolors or moves category under another parent",
                 "consumes": [
                     "application/json"
                 ],
@@ -2646,10 +2807,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "category"
                 ],
-                "summary": "Deletes user information",
+                "summary": "Update Category",
                 "parameters": [
+                    {
+                        "description": "Category Update",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CategoryUpdate"
+                        }
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -2661,21 +2831,37 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/user/change-currency": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Users can change their default currency",
+                "description": "Creates transaction category, optionally under a parent category",
                 "consumes": [
                     "application/json"
                 ],
@@ -2683,17 +2869,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "category"
                 ],
-                "summary": "Change User Currency",
+                "summary": "Create Category",
                 "parameters": [
                     {
-                        "description": "Set currency",
-                        "name": "changecurrency",
+                        "description": "Category Create",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.ChangeCurrency"
+                            "$ref": "#/definitions/requests.CategoryCreate"
                         }
                     },
                     {
@@ -2705,8 +2891,14 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -2720,14 +2912,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/change-notification": {
+        "/category/archive": {
             "put": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Users can change their notification preference",
+                "description": "Archived categories are hidden and can't be used for new transactions, existing transactions are kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -2735,17 +2927,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "category"
                 ],
-                "summary": "Change User Notification Preference",
+                "summary": "Archive/Unarchive Category",
                 "parameters": [
                     {
-                        "description": "Set notification",
-                        "name": "changenotification",
+                        "description": "Category Archive",
+                        "name": "categoryarchive",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.ChangeNotification"
+                            "$ref": "#/definitions/requests.CategoryArchive"
                         }
                     },
                     {
@@ -2759,6 +2951,18 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -2772,14 +2976,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/change-password": {
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Users can change their password",
+        "/investings": {
+            "get": {
+                "description": "Returns investing list by type and market",
                 "consumes": [
                     "application/json"
                 ],
@@ -2787,24 +2986,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "investing"
                 ],
-                "summary": "Change User Password",
+                "summary": "Get Investings by Type and Market",
                 "parameters": [
                     {
-                        "description": "Set new password",
-                        "name": "ChangePassword",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.ChangePassword"
-                        }
+                        "type": "string",
+                        "name": "market",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
-                        "in": "header",
+                        "name": "type",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -2812,7 +3007,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.InvestingResponse"
+                            }
                         }
                     },
                     "400": {
@@ -2830,9 +3028,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/forgot-password": {
-            "post": {
-                "description": "Users can change their password when they forgot",
+        "/investings/prices": {
+            "get": {
+                "description": "Returns investing price table by type and market",
                 "consumes": [
                     "application/json"
                 ],
@@ -2840,29 +3038,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "investing"
                 ],
-                "summary": "Will be used when user forgot password",
+                "summary": "Get Investing Price Table by Type and Market",
                 "parameters": [
                     {
-                        "description": "User's email",
-                        "name": "ForgotPassword",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.ForgotPassword"
-                        }
+                        "type": "string",
+                        "name": "market",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.InvestingTableResponse"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Couldn't find any user",
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -2876,14 +3080,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/info": {
-            "get": {
+        "/log": {
+            "post": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Returns users membership \u0026 investing/subscription limits",
+                "description": "Creates Log",
                 "consumes": [
                     "application/json"
                 ],
@@ -2891,10 +3095,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "logs"
                 ],
-                "summary": "User membership info",
+                "summary": "Create Log",
                 "parameters": [
+                    {
+                        "description": "Log Create",
+                        "name": "log",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.CreateLog"
+                        }
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -2904,23 +3117,18 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User Info",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/responses.UserInfo"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/user/membership": {
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "User membership status will be updated depending on subscription status",
+        "/oauth/apple": {
+            "post": {
+                "description": "Gets user info from apple and creates/finds user and returns token",
                 "consumes": [
                     "application/json"
                 ],
@@ -2928,30 +3136,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
-                ],
-                "summary": "Change User Membership",
-                "parameters": [
-                    {
-                        "description": "Set Membership",
-                        "name": "changemembership",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.ChangeMembership"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    }
+                    "oauth2"
                 ],
+                "summary": "OAuth2 Apple Login",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Token",
                         "schema": {
                             "type": "string"
                         }
@@ -2965,14 +3155,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/update-token": {
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Depending on logged in device fcm token will be updated",
+        "/oauth/google": {
+            "post": {
+                "description": "Gets user info from google and creates/finds user and returns token",
                 "consumes": [
                     "application/json"
                 ],
@@ -2980,30 +3165,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
-                ],
-                "summary": "Updates FCM User Token",
-                "parameters": [
-                    {
-                        "description": "Set token",
-                        "name": "changefcmtoken",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.ChangeFCMToken"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Authentication header",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    }
+                    "oauth2"
                 ],
+                "summary": "OAuth2 Google Login",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Token",
                         "schema": {
                             "type": "string"
                         }
@@ -3017,14 +3184,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/watchlist": {
+        "/price-alert": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns favourite investings by user id",
+                "description": "Returns price alerts of user with current prices",
                 "consumes": [
                     "application/json"
                 ],
@@ -3032,9 +3199,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favouriteinvesting"
+                    "pricealert"
                 ],
-                "summary": "Get Favourite Investings by User ID",
+                "summary": "Get Price Alerts",
                 "parameters": [
                     {
                         "type": "string",
@@ -3050,7 +3217,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/responses.FavouriteInvesting"
+                                "$ref": "#/definitions/responses.PriceAlert"
                             }
                         }
                     },
@@ -3068,7 +3235,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates favourite investings order",
+                "description": "Updates price alert, one-shot alerts can be activated again with is_active",
                 "consumes": [
                     "application/json"
                 ],
@@ -3076,10 +3243,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favouriteinvesting"
+                    "pricealert"
                 ],
-                "summary": "Updates Favourite Investings",
+                "summary": "Update Price Alert",
                 "parameters": [
+                    {
+                        "description": "Price Alert Update",
+                        "name": "pricealert",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.PriceAlertUpdate"
+                        }
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -3091,6 +3267,18 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceAlert"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
@@ -3109,7 +3297,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates favourite investing",
+                "description": "Creates price alert for investing. Alerts are checked hourly.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3117,17 +3305,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favouriteinvesting"
+                    "pricealert"
                 ],
-                "summary": "Create Favourite Investing",
+                "summary": "Create Price Alert",
                 "parameters": [
                     {
-                        "description": "Favourite Investing Create",
-                        "name": "favouriteinvestingcreate",
+                        "description": "Price Alert Create",
+                        "name": "pricealert",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.FavouriteInvestingCreate"
+                            "$ref": "#/definitions/requests.PriceAlertCreate"
                         }
                     },
                     {
@@ -3141,6 +3329,12 @@ const docTemplate = `{
                 "responses": {
                     "201": {
                         "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceAlert"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -3165,7 +3359,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes favourite investing by id",
+                "description": "Deletes price alert by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -3173,9 +3367,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favouriteinvesting"
+                    "pricealert"
                 ],
-                "summary": "Delete favourite investing by favourite investing id",
+                "summary": "Delete price alert by id",
                 "parameters": [
                     {
                         "description": "ID",
@@ -3210,14 +3404,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/watchlist/all": {
-            "delete": {
+        "/subscription": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes favourite investing by user id",
+                "description": "Returns subscriptions and stats by user id",
                 "consumes": [
                     "application/json"
                 ],
@@ -3225,10 +3419,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "favouriteinvesting"
+                    "subscription"
                 ],
-                "summary": "Delete all favourite investings by user id",
+                "summary": "Subscriptions and Stats by User ID",
                 "parameters": [
+                    {
+                        "enum": [
+                            "name",
+                            "currency",
+                            "price",
+                            "date"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            1,
+                            -1
+                        ],
+                        "type": "integer",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Authentication header",
@@ -3240,6 +3456,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SubscriptionAndStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -3251,130 +3473,3480 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "models.Asset": {
-            "type": "object",
-            "properties": {
-                "_id": {
-                    "type": "string"
-                },
-                "amount": {
-                    "type": "number"
-                },
-                "asset_market": {
-                    "type": "string"
-                },
-                "asset_type": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "from_asset": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "number"
-                },
-                "to_asset": {
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "number"
-                }
-            }
-        },
-        "models.BankAccount": {
-            "type": "object",
-            "properties": {
-                "_id": {
-                    "type": "string"
-                },
-                "account_holder": {
-                    "type": "string"
-                },
-                "currency": {
-                    "type": "string"
-                },
-                "iban": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.BillCycle": {
-            "type": "object",
-            "properties": {
-                "day": {
-                    "type": "integer"
-                },
-                "month": {
-                    "type": "integer"
-                },
-                "year": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.Card": {
-            "type": "object",
-            "properties": {
-                "_id": {
-                    "type": "string"
-                },
-                "card_holder": {
-                    "type": "string"
-                },
-                "color": {
-                    "type": "string"
-                },
-                "currency": {
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Update Subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription Update",
+                        "name": "subscriptionupdate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.SubscriptionUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized update",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Couldn't find user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Create Subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription Create",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.Subscription"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes subscription by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Delete subscription by subscription id",
+                "parameters": [
+                    {
+                        "description": "ID",
+                        "name": "ID",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ID"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/all": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes all subscriptions by user id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Delete all subscriptions by user id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels subscription invitation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Cancel Subscription Invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/card": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns subscriptions by card id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Get Subscriptions by Card ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Subscription"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/details": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns subscription details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Get Subscription Details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SubscriptionDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/invitation": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invited users can accept/deny invitation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Handles subscription invitation response",
+                "parameters": [
+                    {
+                        "description": "SubscriptionInvitation",
+                        "name": "subscriptioninvitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.SubscriptionInvitation"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/invite": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invites another user by email to subscription, if accepted they can view it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Sents invitation to another user for access to subscription.",
+                "parameters": [
+                    {
+                        "description": "SubscriptionInvite",
+                        "name": "subscriptioninvite",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.SubscriptionInvite"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/password": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the account password of the subscription, users with two factor need the code header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Reveal subscription account password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two factor code",
+                        "name": "X-TOTP-Code",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/shared": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns shared subscriptions by user id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Get Shared Subscriptions by User ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Subscription"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscription/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns subscription statistics",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Get Subscription Statistics by User ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.SubscriptionStatistics"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns transactions by user id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get Transactions by User ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Transaction"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Update Transaction",
+                "parameters": [
+                    {
+                        "description": "Transaction Update",
+                        "name": "transactionupdate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.TransactionUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized update",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Couldn't find user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Create Transaction",
+                "parameters": [
+                    {
+                        "description": "Transaction Create",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.TransactionCreate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes transaction by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Delete transaction by transaction id",
+                "parameters": [
+                    {
+                        "description": "ID",
+                        "name": "ID",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ID"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/all": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes all transaction by user id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Delete all transaction by user id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/recurring": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns recurring transaction templates of user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get Recurring Transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RecurringTransaction"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates recurring transaction template, already created transactions are not changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Update Recurring Transaction",
+                "parameters": [
+                    {
+                        "description": "Recurring Transaction Update",
+                        "name": "recurringtransaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.RecurringTransactionUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates recurring transaction template. Transactions are created by daily job when they're due.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Create Recurring Transaction",
+                "parameters": [
+                    {
+                        "description": "Recurring Transaction Create",
+                        "name": "recurringtransaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.RecurringTransactionCreate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes recurring transaction template, already created transactions are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Delete recurring transaction by id",
+                "parameters": [
+                    {
+                        "description": "ID",
+                        "name": "ID",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ID"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/recurring/pause": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pauses or resumes recurring transaction. Occurrences while paused are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Pause/Resume Recurring Transaction",
+                "parameters": [
+                    {
+                        "description": "Recurring Transaction Pause",
+                        "name": "recurringpause",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.RecurringTransactionPause"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/recurring/preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns upcoming occurrence dates of recurring transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Preview Recurring Transaction",
+                "parameters": [
+                    {
+                        "maximum": 50,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.RecurringTransactionPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns transaction statistics by interval",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get Transaction Statistics by Interval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.TransactionStats"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/total": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns total transaction value by interval",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get Total Transaction Value by Interval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.TransactionTotal"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes everything related to user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Deletes user information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two factor code",
+                        "name": "X-TOTP-Code",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disables two factor, requires the two factor code header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Disable two factor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two factor code",
+                        "name": "X-TOTP-Code",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verifies a code of the pending secret, enables two factor and returns recovery codes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Enable two factor",
+                "parameters": [
+                    {
+                        "description": "Two Factor Code",
+                        "name": "twofactorcode",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.TwoFactorCode"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the recovery codes, requires the two factor code header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Regenerate recovery codes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two factor code",
+                        "name": "X-TOTP-Code",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/2fa/setup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a TOTP secret, it's enabled after a code of it is verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Start two factor setup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.TwoFactorSetup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/change-cost-basis": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Users can change the lot accounting method used for asset profit/loss",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change User Cost Basis Method",
+                "parameters": [
+                    {
+                        "description": "Set cost basis method",
+                        "name": "changecostbasismethod",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ChangeCostBasisMethod"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/change-currency": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Users can change their default currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change User Currency",
+                "parameters": [
+                    {
+                        "description": "Set currency",
+                        "name": "changecurrency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ChangeCurrency"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/change-notification": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Users can change their notification preference",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change User Notification Preference",
+                "parameters": [
+                    {
+                        "description": "Set notification",
+                        "name": "changenotification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ChangeNotification"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/change-password": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Users can change their password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change User Password",
+                "parameters": [
+                    {
+                        "description": "Set new password",
+                        "name": "ChangePassword",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ChangePassword"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two factor code",
+                        "name": "X-TOTP-Code",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/entitlements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns usage and limits of every resource for user's plan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "User plan usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Entitlements"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a zip archive with JSON and CSV files of every collection owned by user. Subscription account passwords are only included on opt-in.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Exports user data",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "name": "includePasswords",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two factor code, required for passwords",
+                        "name": "X-TOTP-Code",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/forgot-password": {
+            "post": {
+                "description": "Users can change their password when they forgot",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Will be used when user forgot password",
+                "parameters": [
+                    {
+                        "description": "User's email",
+                        "name": "ForgotPassword",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ForgotPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Couldn't find any user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recreates cards, bank accounts, categories, subscriptions, transactions, custom asset valuations, assets and watchlist from an export archive. Assets and transactions are validated like created ones. Items that are invalid, already exist or exceed membership limits are reported as conflicts.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Imports user data",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Export Archive",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.UserDataImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/info": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns users membership \u0026 investing/subscription limits",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "User membership info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User Info",
+                        "schema": {
+                            "$ref": "#/definitions/responses.UserInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/membership": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Verifies the App Store or Play Store receipt and updates membership from user's active purchases",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change User Membership",
+                "parameters": [
+                    {
+                        "description": "Purchase Receipt",
+                        "name": "changemembership",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ChangeMembership"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.IsUserPremium"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/net-worth": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns total assets, bank account balances and card outstanding balances in user's currency",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get Net Worth",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.NetWorth"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/resend-verification": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends a new email verification link, it can be sent once in 5 minutes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Resend verification email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns user's active sessions by device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get active sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.Session"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the session, its device has to login again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Revoke session",
+                "parameters": [
+                    {
+                        "description": "Session ID",
+                        "name": "ID",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ID"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/update-token": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Depending on logged in device fcm token will be updated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Updates FCM User Token",
+                "parameters": [
+                    {
+                        "description": "Set token",
+                        "name": "changefcmtoken",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ChangeFCMToken"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns favourite investings by user id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favouriteinvesting"
+                ],
+                "summary": "Get Favourite Investings by User ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.FavouriteInvesting"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates favourite investings order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favouriteinvesting"
+                ],
+                "summary": "Updates Favourite Investings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates favourite investing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favouriteinvesting"
+                ],
+                "summary": "Create Favourite Investing",
+                "parameters": [
+                    {
+                        "description": "Favourite Investing Create",
+                        "name": "favouriteinvestingcreate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.FavouriteInvestingCreate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes favourite investing by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favouriteinvesting"
+                ],
+                "summary": "Delete favourite investing by favourite investing id",
+                "parameters": [
+                    {
+                        "description": "ID",
+                        "name": "ID",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.ID"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/watchlist/all": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes favourite investing by user id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favouriteinvesting"
+                ],
+                "summary": "Delete all favourite investings by user id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication header",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AllocationTarget": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "drift_tolerance": {
+                    "type": "number"
+                },
+                "min_trade_size": {
+                    "type": "number"
+                },
+                "target_type": {
+                    "type": "string"
+                },
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AllocationTargetItem"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AllocationTargetItem": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Asset": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "asset_market": {
+                    "type": "string"
+                },
+                "asset_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_asset": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "split_ratio": {
+                    "type": "number"
+                },
+                "to_asset": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.BankAccount": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "account_holder": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "iban": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opening_balance": {
+                    "description": "Balance before the first transaction, the balance is calculated from it.",
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BillCycle": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "integer"
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Card": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "card_holder": {
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "last_digit": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opening_balance": {
+                    "description": "Outstanding balance before the first transaction.",
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "built_in": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "is_archived": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CorporateAction": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "action_type": {
+                    "type": "string"
+                },
+                "asset_market": {
+                    "type": "string"
+                },
+                "asset_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_date": {
+                    "type": "string"
+                },
+                "new_symbol": {
+                    "type": "string"
+                },
+                "split_ratio": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "symbols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CustomAssetValuation": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "from_asset": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "to_asset": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InvestingID": {
+            "type": "object",
+            "properties": {
+                "market": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceAlert": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "alert_type": {
+                    "type": "string"
+                },
+                "investing_id": {
+                    "$ref": "#/definitions/models.InvestingID"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_recurring": {
+                    "type": "boolean"
+                },
+                "last_triggered_at": {
+                    "type": "string"
+                },
+                "send_email": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.RecurringTransaction": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "is_paused": {
+                    "type": "boolean"
+                },
+                "last_occurrence": {
+                    "type": "string"
+                },
+                "method": {
+                    "$ref": "#/definitions/models.TransactionMethod"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Subscription": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "account": {
+                    "$ref": "#/definitions/models.SubscriptionAccount"
+                },
+                "bill_cycle": {
+                    "$ref": "#/definitions/models.BillCycle"
+                },
+                "bill_date": {
+                    "type": "string"
+                },
+                "card_id": {
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "invited_users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notification_time": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "shared_users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SubscriptionAccount": {
+            "type": "object",
+            "properties": {
+                "email_address": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Transaction": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "method": {
+                    "$ref": "#/definitions/models.TransactionMethod"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "recurring_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transaction_date": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TransactionMethod": {
+            "type": "object",
+            "properties": {
+                "method_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.AllocationTargetItem": {
+            "type": "object",
+            "required": [
+                "key",
+                "percentage"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "number",
+                    "maximum": 100
+                }
+            }
+        },
+        "requests.AllocationTargetUpdate": {
+            "type": "object",
+            "required": [
+                "target_type",
+                "targets"
+            ],
+            "properties": {
+                "drift_tolerance": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "min_trade_size": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "target_type": {
+                    "type": "string",
+                    "enum": [
+                        "asset_type",
+                        "asset"
+                    ]
+                },
+                "targets": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/requests.AllocationTargetItem"
+                    }
+                }
+            }
+        },
+        "requests.AssetCreate": {
+            "type": "object",
+            "required": [
+                "amount",
+                "asset_type",
+                "from_asset",
+                "to_asset",
+                "type"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "asset_market": {
+                    "type": "string"
+                },
+                "asset_type": {
+                    "type": "string",
+                    "enum": [
+                        "crypto",
+                        "stock",
+                        "exchange",
+                        "commodity",
+                        "custom"
+                    ]
+                },
+                "from_asset": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "to_asset": {
                     "type": "string"
                 },
-                "last_digit": {
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "sell",
+                        "buy",
+                        "dividend",
+                        "staking",
+                        "airdrop",
+                        "split",
+                        "fee"
+                    ]
+                }
+            }
+        },
+        "requests.AssetLogsDelete": {
+            "type": "object",
+            "required": [
+                "asset_market",
+                "from_asset",
+                "to_asset"
+            ],
+            "properties": {
+                "asset_market": {
                     "type": "string"
                 },
-                "name": {
+                "from_asset": {
+                    "type": "string"
+                },
+                "to_asset": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.AssetUpdate": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "id": {
                     "type": "string"
                 },
+                "price": {
+                    "type": "number"
+                },
                 "type": {
+                    "type": "string",
+                    "enum": [
+                        "sell",
+                        "buy",
+                        "dividend",
+                        "staking",
+                        "airdrop",
+                        "fee"
+                    ]
+                }
+            }
+        },
+        "requests.BankAccountCreate": {
+            "type": "object",
+            "required": [
+                "account_holder",
+                "currency",
+                "iban",
+                "name"
+            ],
+            "properties": {
+                "account_holder": {
                     "type": "string"
                 },
-                "user_id": {
+                "currency": {
+                    "type": "string"
+                },
+                "iban": {
+                    "type": "string"
+                },
+                "name": {
                     "type": "string"
+                },
+                "opening_balance": {
+                    "type": "number"
                 }
             }
         },
-        "models.Subscription": {
+        "requests.BankAccountUpdate": {
             "type": "object",
+            "required": [
+                "id"
+            ],
             "properties": {
-                "_id": {
+                "account_holder": {
                     "type": "string"
                 },
-                "account": {
-                    "$ref": "#/definitions/models.SubscriptionAccount"
+                "currency": {
+                    "type": "string"
                 },
-                "bill_cycle": {
-                    "$ref": "#/definitions/models.BillCycle"
+                "iban": {
+                    "type": "string"
                 },
-                "bill_date": {
+                "id": {
                     "type": "string"
                 },
-                "card_id": {
+                "name": {
+                    "type": "string"
+                },
+                "opening_balance": {
+                    "type": "number"
+                }
+            }
+        },
+        "requests.BillCycle": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "integer"
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "requests.BudgetCreate": {
+            "type": "object",
+            "required": [
+                "category",
+                "limit"
+            ],
+            "properties": {
+                "category": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "number"
+                }
+            }
+        },
+        "requests.BudgetUpdate": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "number"
+                }
+            }
+        },
+        "requests.Card": {
+            "type": "object",
+            "required": [
+                "card_holder",
+                "color",
+                "currency",
+                "last_digit",
+                "name",
+                "type"
+            ],
+            "properties": {
+                "card_holder": {
                     "type": "string"
                 },
                 "color": {
@@ -3383,105 +6955,217 @@ const docTemplate = `{
                 "currency": {
                     "type": "string"
                 },
-                "description": {
+                "last_digit": {
                     "type": "string"
                 },
-                "image": {
+                "name": {
                     "type": "string"
                 },
-                "invited_users": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "opening_balance": {
+                    "type": "number"
                 },
-                "name": {
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.CardUpdate": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "card_holder": {
                     "type": "string"
                 },
-                "notification_time": {
+                "color": {
                     "type": "string"
                 },
-                "price": {
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_digit": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opening_balance": {
                     "type": "number"
                 },
-                "shared_users": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.CategoryArchive": {
+            "type": "object",
+            "required": [
+                "id",
+                "is_archived"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
                 },
-                "user_id": {
+                "is_archived": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "requests.CategoryCreate": {
+            "type": "object",
+            "required": [
+                "color",
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.CategoryUpdate": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "delete_parent": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.ChangeCostBasisMethod": {
+            "type": "object",
+            "required": [
+                "cost_basis_method"
+            ],
+            "properties": {
+                "cost_basis_method": {
+                    "type": "string",
+                    "enum": [
+                        "fifo",
+                        "lifo",
+                        "average"
+                    ]
+                }
+            }
+        },
+        "requests.ChangeCurrency": {
+            "type": "object",
+            "required": [
+                "currency"
+            ],
+            "properties": {
+                "currency": {
                     "type": "string"
                 }
             }
         },
-        "models.SubscriptionAccount": {
+        "requests.ChangeFCMToken": {
             "type": "object",
+            "required": [
+                "fcm_token"
+            ],
             "properties": {
-                "email_address": {
-                    "type": "string"
-                },
-                "password": {
+                "fcm_token": {
                     "type": "string"
                 }
             }
         },
-        "models.Transaction": {
+        "requests.ChangeMembership": {
             "type": "object",
+            "required": [
+                "platform",
+                "product_id",
+                "receipt"
+            ],
             "properties": {
-                "_id": {
-                    "type": "string"
-                },
-                "category": {
-                    "type": "integer"
-                },
-                "currency": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "method": {
-                    "$ref": "#/definitions/models.TransactionMethod"
-                },
-                "price": {
-                    "type": "number"
+                "platform": {
+                    "type": "string",
+                    "enum": [
+                        "app_store",
+                        "play_store"
+                    ]
                 },
-                "title": {
+                "product_id": {
                     "type": "string"
                 },
-                "transaction_date": {
+                "receipt": {
                     "type": "string"
+                }
+            }
+        },
+        "requests.ChangeNotification": {
+            "type": "object",
+            "required": [
+                "app_notification",
+                "mail_notification"
+            ],
+            "properties": {
+                "app_notification": {
+                    "type": "boolean"
                 },
-                "user_id": {
-                    "type": "string"
+                "mail_notification": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.TransactionMethod": {
+        "requests.ChangePassword": {
             "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
             "properties": {
-                "method_id": {
-                    "type": "string"
+                "new_password": {
+                    "type": "string",
+                    "minLength": 6
                 },
-                "type": {
-                    "type": "integer"
+                "old_password": {
+                    "type": "string",
+                    "minLength": 6
                 }
             }
         },
-        "requests.AssetCreate": {
+        "requests.CorporateActionCreate": {
             "type": "object",
             "required": [
-                "amount",
+                "action_type",
+                "asset_market",
                 "asset_type",
-                "from_asset",
-                "price",
-                "to_asset",
-                "type"
+                "effective_date",
+                "symbol"
             ],
             "properties": {
-                "amount": {
-                    "type": "number"
+                "action_type": {
+                    "type": "string",
+                    "enum": [
+                        "split",
+                        "symbol_change"
+                    ]
                 },
                 "asset_market": {
                     "type": "string"
@@ -3495,304 +7179,317 @@ const docTemplate = `{
                         "commodity"
                     ]
                 },
-                "from_asset": {
+                "effective_date": {
                     "type": "string"
                 },
-                "price": {
+                "new_symbol": {
+                    "type": "string"
+                },
+                "split_ratio": {
                     "type": "number"
                 },
-                "to_asset": {
+                "symbol": {
                     "type": "string"
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "sell",
-                        "buy"
-                    ]
                 }
             }
         },
-        "requests.AssetLogsDelete": {
+        "requests.CreateLog": {
             "type": "object",
             "required": [
-                "asset_market",
-                "from_asset",
-                "to_asset"
+                "log",
+                "log_type"
             ],
             "properties": {
-                "asset_market": {
-                    "type": "string"
-                },
-                "from_asset": {
+                "log": {
                     "type": "string"
                 },
-                "to_asset": {
-                    "type": "string"
+                "log_type": {
+                    "type": "integer"
                 }
             }
         },
-        "requests.AssetUpdate": {
+        "requests.CustomAssetValuationUpdate": {
             "type": "object",
             "required": [
-                "id"
+                "from_asset",
+                "price",
+                "to_asset"
             ],
             "properties": {
-                "amount": {
-                    "type": "number"
+                "from_asset": {
+                    "type": "string"
                 },
-                "id": {
+                "name": {
                     "type": "string"
                 },
                 "price": {
-                    "type": "number"
+                    "type": "number",
+                    "minimum": 0
                 },
-                "type": {
+                "to_asset": {
                     "type": "string"
                 }
             }
         },
-        "requests.BankAccountCreate": {
+        "requests.DailyAssetStatsBackfill": {
             "type": "object",
             "required": [
-                "account_holder",
-                "currency",
-                "iban",
-                "name"
+                "start_date"
             ],
             "properties": {
-                "account_holder": {
+                "end_date": {
                     "type": "string"
                 },
-                "currency": {
-                    "type": "string"
-                },
-                "iban": {
+                "start_date": {
                     "type": "string"
                 },
-                "name": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "requests.BankAccountUpdate": {
+        "requests.FavouriteInvestingCreate": {
             "type": "object",
             "required": [
-                "id"
+                "market",
+                "priority",
+                "symbol",
+                "type"
             ],
             "properties": {
-                "account_holder": {
-                    "type": "string"
-                },
-                "currency": {
+                "market": {
                     "type": "string"
                 },
-                "iban": {
-                    "type": "string"
+                "priority": {
+                    "type": "integer"
                 },
-                "id": {
+                "symbol": {
                     "type": "string"
                 },
-                "name": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "requests.BillCycle": {
+        "requests.ForgotPassword": {
             "type": "object",
+            "required": [
+                "email_address"
+            ],
             "properties": {
-                "day": {
-                    "type": "integer"
-                },
-                "month": {
-                    "type": "integer"
-                },
-                "year": {
-                    "type": "integer"
+                "email_address": {
+                    "type": "string"
                 }
             }
         },
-        "requests.Card": {
+        "requests.ID": {
             "type": "object",
             "required": [
-                "card_holder",
-                "color",
-                "currency",
-                "last_digit",
-                "name",
-                "type"
+                "id"
             ],
             "properties": {
-                "card_holder": {
-                    "type": "string"
-                },
-                "color": {
-                    "type": "string"
-                },
-                "currency": {
-                    "type": "string"
-                },
-                "last_digit": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "type": {
+                "id": {
                     "type": "string"
                 }
             }
         },
-        "requests.CardUpdate": {
+        "requests.PriceAlertCreate": {
             "type": "object",
             "required": [
-                "id"
+                "alert_type",
+                "market",
+                "symbol",
+                "type",
+                "value"
             ],
             "properties": {
-                "card_holder": {
-                    "type": "string"
-                },
-                "color": {
-                    "type": "string"
+                "alert_type": {
+                    "type": "string",
+                    "enum": [
+                        "above",
+                        "below",
+                        "change"
+                    ]
                 },
-                "currency": {
-                    "type": "string"
+                "is_recurring": {
+                    "type": "boolean"
                 },
-                "id": {
+                "market": {
                     "type": "string"
                 },
-                "last_digit": {
-                    "type": "string"
+                "send_email": {
+                    "type": "boolean"
                 },
-                "name": {
+                "symbol": {
                     "type": "string"
                 },
                 "type": {
                     "type": "string"
+                },
+                "value": {
+                    "type": "number"
                 }
             }
         },
-        "requests.ChangeCurrency": {
-            "type": "object",
-            "required": [
-                "currency"
-            ],
-            "properties": {
-                "currency": {
-                    "type": "string"
-                }
-            }
-        },
-        "requests.ChangeFCMToken": {
+        "requests.PriceAlertUpdate": {
             "type": "object",
             "required": [
-                "fcm_token"
+                "id"
             ],
             "properties": {
-                "fcm_token": {
+                "id": {
                     "type": "string"
-                }
-            }
-        },
-        "requests.ChangeMembership": {
-            "type": "object",
-            "properties": {
-                "is_lifetime_premium": {
-                    "type": "boolean"
                 },
-                "is_premium": {
+                "is_active": {
                     "type": "boolean"
-                }
-            }
-        },
-        "requests.ChangeNotification": {
-            "type": "object",
-            "required": [
-                "app_notification",
-                "mail_notification"
-            ],
-            "properties": {
-                "app_notification": {
+                },
+                "is_recurring": {
                     "type": "boolean"
                 },
-                "mail_notification": {
+                "send_email": {
                     "type": "boolean"
+                },
+                "value": {
+                    "type": "number"
                 }
             }
         },
-        "requests.ChangePassword": {
+        "requests.RecurringTransactionCreate": {
             "type": "object",
             "required": [
-                "new_password",
-                "old_password"
+                "currency",
+                "frequency",
+                "interval",
+                "price",
+                "start_date",
+                "title"
             ],
             "properties": {
-                "new_password": {
-                    "type": "string",
-                    "minLength": 6
+                "category": {
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
                 },
-                "old_password": {
+                "category_id": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "frequency": {
                     "type": "string",
-                    "minLength": 6
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ]
+                },
+                "interval": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "method": {
+                    "$ref": "#/definitions/requests.TransactionMethod"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "requests.CreateLog": {
+        "requests.RecurringTransactionPause": {
             "type": "object",
             "required": [
-                "log",
-                "log_type"
+                "id",
+                "is_paused"
             ],
             "properties": {
-                "log": {
+                "id": {
                     "type": "string"
                 },
-                "log_type": {
-                    "type": "integer"
+                "is_paused": {
+                    "type": "boolean"
                 }
             }
         },
-        "requests.FavouriteInvestingCreate": {
+        "requests.RecurringTransactionUpdate": {
             "type": "object",
             "required": [
-                "market",
-                "priority",
-                "symbol",
-                "type"
+                "id"
             ],
             "properties": {
-                "market": {
+                "category": {
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
+                },
+                "category_id": {
                     "type": "string"
                 },
-                "priority": {
-                    "type": "integer"
+                "currency": {
+                    "type": "string"
                 },
-                "symbol": {
+                "delete_end_date": {
+                    "type": "boolean"
+                },
+                "delete_method": {
+                    "type": "boolean"
+                },
+                "description": {
                     "type": "string"
                 },
-                "type": {
+                "end_date": {
                     "type": "string"
-                }
-            }
-        },
-        "requests.ForgotPassword": {
-            "type": "object",
-            "required": [
-                "email_address"
-            ],
-            "properties": {
-                "email_address": {
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "method": {
+                    "$ref": "#/definitions/requests.TransactionMethod"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "requests.ID": {
+        "requests.RefreshToken": {
             "type": "object",
             "required": [
-                "id"
+                "refresh_token"
             ],
             "properties": {
-                "id": {
+                "refresh_token": {
                     "type": "string"
                 }
             }
@@ -3951,7 +7648,6 @@ const docTemplate = `{
         "requests.TransactionCreate": {
             "type": "object",
             "required": [
-                "category",
                 "currency",
                 "price",
                 "title",
@@ -3959,7 +7655,12 @@ const docTemplate = `{
             ],
             "properties": {
                 "category": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
+                },
+                "category_id": {
+                    "type": "string"
                 },
                 "currency": {
                     "type": "string"
@@ -4003,7 +7704,12 @@ const docTemplate = `{
             ],
             "properties": {
                 "category": {
-                    "type": "integer"
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
+                },
+                "category_id": {
+                    "type": "string"
                 },
                 "currency": {
                     "type": "string"
@@ -4031,6 +7737,32 @@ const docTemplate = `{
                 }
             }
         },
+        "requests.TwoFactorCode": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.TwoFactorLogin": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
         "responses.Asset": {
             "type": "object",
             "properties": {
@@ -4040,6 +7772,9 @@ const docTemplate = `{
                 "asset_type": {
                     "type": "string"
                 },
+                "cost_basis": {
+                    "type": "number"
+                },
                 "current_total_value": {
                     "type": "number"
                 },
@@ -4055,6 +7790,9 @@ const docTemplate = `{
                 "pl_percentage": {
                     "type": "number"
                 },
+                "realized_p/l": {
+                    "type": "number"
+                },
                 "remaining_amount": {
                     "type": "number"
                 },
@@ -4064,8 +7802,17 @@ const docTemplate = `{
                 "total_bought": {
                     "type": "number"
                 },
+                "total_fees": {
+                    "type": "number"
+                },
+                "total_income": {
+                    "type": "number"
+                },
                 "total_sold": {
                     "type": "number"
+                },
+                "unrealized_p/l": {
+                    "type": "number"
                 }
             }
         },
@@ -4078,45 +7825,216 @@ const docTemplate = `{
                         "$ref": "#/definitions/responses.Asset"
                     }
                 },
-                "stats": {
-                    "$ref": "#/definitions/responses.AssetStats"
+                "stats": {
+                    "$ref": "#/definitions/responses.AssetStats"
+                }
+            }
+        },
+        "responses.AssetDetails": {
+            "type": "object",
+            "properties": {
+                "asset_market": {
+                    "type": "string"
+                },
+                "asset_type": {
+                    "type": "string"
+                },
+                "cost_basis": {
+                    "type": "number"
+                },
+                "cost_basis_method": {
+                    "type": "string"
+                },
+                "current_total_value": {
+                    "type": "number"
+                },
+                "from_asset": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "p/l": {
+                    "type": "number"
+                },
+                "pl_percentage": {
+                    "type": "number"
+                },
+                "realized_p/l": {
+                    "type": "number"
+                },
+                "remaining_amount": {
+                    "type": "number"
+                },
+                "to_asset": {
+                    "type": "string"
+                },
+                "total_bought": {
+                    "type": "number"
+                },
+                "total_fees": {
+                    "type": "number"
+                },
+                "total_income": {
+                    "type": "number"
+                },
+                "total_sold": {
+                    "type": "number"
+                },
+                "unrealized_p/l": {
+                    "type": "number"
+                }
+            }
+        },
+        "responses.AssetImport": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.AssetImportError"
+                    }
+                },
+                "inserted_rows": {
+                    "type": "integer"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "valid_rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "responses.AssetImportError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "responses.AssetPerformance": {
+            "type": "object",
+            "properties": {
+                "asset_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.AssetPerformanceItem"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "holdings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.AssetPerformanceItem"
+                    }
+                },
+                "portfolio": {
+                    "$ref": "#/definitions/responses.AssetPerformanceItem"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.AssetPerformanceItem": {
+            "type": "object",
+            "properties": {
+                "annualized_twr": {
+                    "type": "number"
+                },
+                "asset_type": {
+                    "type": "string"
+                },
+                "end_value": {
+                    "type": "number"
+                },
+                "from_asset": {
+                    "type": "string"
+                },
+                "net_cash_flow": {
+                    "type": "number"
+                },
+                "start_value": {
+                    "type": "number"
+                },
+                "to_asset": {
+                    "type": "string"
+                },
+                "twr": {
+                    "type": "number"
+                },
+                "xirr": {
+                    "type": "number"
                 }
             }
         },
-        "responses.AssetDetails": {
+        "responses.AssetRebalance": {
             "type": "object",
             "properties": {
-                "asset_market": {
+                "currency": {
                     "type": "string"
                 },
-                "asset_type": {
-                    "type": "string"
+                "drift_tolerance": {
+                    "type": "number"
                 },
-                "current_total_value": {
+                "is_balanced": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.AssetRebalanceItem"
+                    }
+                },
+                "min_trade_size": {
                     "type": "number"
                 },
-                "from_asset": {
+                "target_type": {
                     "type": "string"
                 },
-                "name": {
+                "total_value": {
+                    "type": "number"
+                }
+            }
+        },
+        "responses.AssetRebalanceItem": {
+            "type": "object",
+            "properties": {
+                "action": {
                     "type": "string"
                 },
-                "p/l": {
+                "current_percentage": {
                     "type": "number"
                 },
-                "pl_percentage": {
+                "current_value": {
                     "type": "number"
                 },
-                "remaining_amount": {
+                "drift": {
                     "type": "number"
                 },
-                "to_asset": {
+                "key": {
                     "type": "string"
                 },
-                "total_bought": {
+                "target_percentage": {
                     "type": "number"
                 },
-                "total_sold": {
+                "trade_amount": {
+                    "type": "number"
+                },
+                "trade_value": {
                     "type": "number"
                 }
             }
@@ -4145,6 +8063,15 @@ const docTemplate = `{
                 "currency": {
                     "type": "string"
                 },
+                "custom_assets": {
+                    "type": "number"
+                },
+                "custom_p/l": {
+                    "type": "number"
+                },
+                "custom_percentage": {
+                    "type": "number"
+                },
                 "exchange_assets": {
                     "type": "number"
                 },
@@ -4169,6 +8096,12 @@ const docTemplate = `{
                 "total_bought": {
                     "type": "number"
                 },
+                "total_fees": {
+                    "type": "number"
+                },
+                "total_income": {
+                    "type": "number"
+                },
                 "total_p/l": {
                     "type": "number"
                 },
@@ -4180,6 +8113,85 @@ const docTemplate = `{
                 }
             }
         },
+        "responses.AssetTaxDisposal": {
+            "type": "object",
+            "properties": {
+                "acquired_at": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "asset_market": {
+                    "type": "string"
+                },
+                "asset_type": {
+                    "type": "string"
+                },
+                "cost_basis": {
+                    "type": "number"
+                },
+                "cost_basis_exchange_rate": {
+                    "type": "number"
+                },
+                "disposed_at": {
+                    "type": "string"
+                },
+                "from_asset": {
+                    "type": "string"
+                },
+                "gain": {
+                    "type": "number"
+                },
+                "holding_period": {
+                    "type": "string"
+                },
+                "proceeds": {
+                    "type": "number"
+                },
+                "proceeds_exchange_rate": {
+                    "type": "number"
+                },
+                "to_asset": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.AssetTaxReport": {
+            "type": "object",
+            "properties": {
+                "cost_basis_method": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "disposals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.AssetTaxDisposal"
+                    }
+                },
+                "long_term_gain": {
+                    "type": "number"
+                },
+                "short_term_gain": {
+                    "type": "number"
+                },
+                "total_cost_basis": {
+                    "type": "number"
+                },
+                "total_gain": {
+                    "type": "number"
+                },
+                "total_proceeds": {
+                    "type": "number"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
         "responses.BillCycle": {
             "type": "object",
             "properties": {
@@ -4194,6 +8206,49 @@ const docTemplate = `{
                 }
             }
         },
+        "responses.BudgetCategoryStatus": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "number"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "projected": {
+                    "type": "number"
+                },
+                "remaining": {
+                    "type": "number"
+                },
+                "spent": {
+                    "type": "number"
+                }
+            }
+        },
+        "responses.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.BudgetCategoryStatus"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "month": {
+                    "type": "string"
+                }
+            }
+        },
         "responses.Card": {
             "type": "object",
             "properties": {
@@ -4214,26 +8269,165 @@ const docTemplate = `{
                 "subscription_stats": {
                     "$ref": "#/definitions/responses.CardSubscriptionStatistics"
                 },
-                "transaction_stats": {
-                    "$ref": "#/definitions/responses.TransactionTotal"
+                "transaction_stats": {
+                    "$ref": "#/definitions/responses.TransactionTotal"
+                }
+            }
+        },
+        "responses.CardSubscriptionStatistics": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "total_monthly_payment": {
+                    "type": "number"
+                },
+                "total_payment": {
+                    "type": "number"
+                }
+            }
+        },
+        "responses.CorporateActionPreview": {
+            "type": "object",
+            "properties": {
+                "log_count": {
+                    "type": "integer"
+                },
+                "user_count": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.CorporateActionPreviewUser"
+                    }
+                }
+            }
+        },
+        "responses.CorporateActionPreviewUser": {
+            "type": "object",
+            "properties": {
+                "adjusted_amount": {
+                    "type": "number"
+                },
+                "log_count": {
+                    "type": "integer"
+                },
+                "remaining_amount": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.DailyAssetHoldingStats": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "dates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "total_assets": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "total_p/l": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "responses.DailyAssetStats": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "dates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "min": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "open": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "total_assets": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "total_p/l": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
                 }
             }
         },
-        "responses.CardSubscriptionStatistics": {
+        "responses.DailyAssetStatsBackfill": {
             "type": "object",
             "properties": {
-                "currency": {
+                "day_count": {
+                    "type": "integer"
+                },
+                "end_date": {
                     "type": "string"
                 },
-                "total_monthly_payment": {
-                    "type": "number"
+                "start_date": {
+                    "type": "string"
                 },
-                "total_payment": {
-                    "type": "number"
+                "user_count": {
+                    "type": "integer"
                 }
             }
         },
-        "responses.DailyAssetStats": {
+        "responses.DailyAssetTypeStats": {
             "type": "object",
             "properties": {
                 "currency": {
@@ -4259,6 +8453,46 @@ const docTemplate = `{
                 }
             }
         },
+        "responses.EntitlementUsage": {
+            "type": "object",
+            "properties": {
+                "is_reached": {
+                    "type": "boolean"
+                },
+                "is_unlimited": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "usage": {
+                    "type": "integer"
+                }
+            }
+        },
+        "responses.Entitlements": {
+            "type": "object",
+            "properties": {
+                "features": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "plan": {
+                    "type": "string"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.EntitlementUsage"
+                    }
+                }
+            }
+        },
         "responses.FavouriteInvesting": {
             "type": "object",
             "properties": {
@@ -4327,6 +8561,167 @@ const docTemplate = `{
                 }
             }
         },
+        "responses.IsUserPremium": {
+            "type": "object",
+            "properties": {
+                "is_lifetime_premium": {
+                    "type": "boolean"
+                },
+                "is_premium": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "responses.NetWorth": {
+            "type": "object",
+            "properties": {
+                "bank_accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.NetWorthAccount"
+                    }
+                },
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.NetWorthAccount"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "net_worth": {
+                    "type": "number"
+                },
+                "total_assets": {
+                    "type": "number"
+                },
+                "total_bank_balance": {
+                    "type": "number"
+                },
+                "total_card_balance": {
+                    "type": "number"
+                }
+            }
+        },
+        "responses.NetWorthAccount": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "converted_balance": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.PriceAlert": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "alert_type": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "investing_id": {
+                    "$ref": "#/definitions/responses.FavouriteInvestingID"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_recurring": {
+                    "type": "boolean"
+                },
+                "last_triggered_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "send_email": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "responses.RecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "responses.RecurringTransactionPreview": {
+            "type": "object",
+            "properties": {
+                "occurrences": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recurring_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.Session": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "device_name": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "is_current": {
+                    "type": "boolean"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
         "responses.Subscription": {
             "type": "object",
             "properties": {
@@ -4472,6 +8867,18 @@ const docTemplate = `{
                 "_id": {
                     "type": "integer"
                 },
+                "category_id": {
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
                 "total_transaction": {
                     "type": "number"
                 }
@@ -4489,17 +8896,46 @@ const docTemplate = `{
                 "currency": {
                     "type": "string"
                 },
+                "currency_breakdown": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.TransactionCurrencyTotal"
+                    }
+                },
                 "total_transaction": {
                     "type": "number"
                 }
             }
         },
+        "responses.TransactionCurrencyTotal": {
+            "type": "object",
+            "properties": {
+                "converted_total": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "missing_exchange_rate": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
         "responses.TransactionDailyStats": {
             "type": "object",
             "properties": {
                 "currency": {
                     "type": "string"
                 },
+                "currency_breakdown": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.TransactionCurrencyTotal"
+                    }
+                },
                 "date": {
                     "type": "string"
                 },
@@ -4534,17 +8970,76 @@ const docTemplate = `{
                 "currency": {
                     "type": "string"
                 },
+                "currency_breakdown": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.TransactionCurrencyTotal"
+                    }
+                },
                 "total_transaction": {
                     "type": "number"
                 }
             }
         },
+        "responses.TwoFactorSetup": {
+            "type": "object",
+            "properties": {
+                "otpauth_url": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.UserDataImport": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.UserDataImportResult"
+                    }
+                }
+            }
+        },
+        "responses.UserDataImportConflict": {
+            "type": "object",
+            "properties": {
+                "_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.UserDataImportResult": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "type": "string"
+                },
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.UserDataImportConflict"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                }
+            }
+        },
         "responses.UserInfo": {
             "type": "object",
             "properties": {
                 "app_notification": {
                     "type": "boolean"
                 },
+                "cost_basis_method": {
+                    "type": "string"
+                },
                 "currency": {
                     "type": "string"
                 },
@@ -4566,6 +9061,12 @@ const docTemplate = `{
                 "is_premium": {
                     "type": "boolean"
                 },
+                "is_two_factor": {
+                    "type": "boolean"
+                },
+                "is_verified": {
+                    "type": "boolean"
+                },
                 "subscription_limit": {
                     "type": "string"
                 },
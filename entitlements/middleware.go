@@ -0,0 +1,71 @@
+package entitlements
+
+import (
+	"asset_backend/db"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+const errTransactionDate = "Transaction date is missing or invalid."
+
+// QuotaMiddleware rejects creating an item of the resource when user's plan
+// limit is reached, it has to come after the JWT middleware. Daily
+// transactions are counted for the transaction_date of the request body.
+func QuotaMiddleware(mongoDB *db.MongoDB, resource string) gin.HandlerFunc {
+	service := NewQuotaService(mongoDB)
+
+	return func(c *gin.Context) {
+		uid := jwt.ExtractClaims(c)["id"].(string)
+
+		date := time.Now().UTC()
+		if resource == ResourceDailyTransaction {
+			transactionDate, ok := getTransactionDate(c)
+			if !ok {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": errTransactionDate,
+				})
+
+				return
+			}
+
+			date = transactionDate
+		}
+
+		if message, ok := service.CheckQuota(uid, resource, date); !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": message,
+			})
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// getTransactionDate reads transaction_date and restores the body for the
+// handler's binding.
+func getTransactionDate(c *gin.Context) (time.Time, bool) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var data struct {
+		TransactionDate time.Time `json:"transaction_date"`
+	}
+
+	if err := json.Unmarshal(body, &data); err != nil || data.TransactionDate.IsZero() {
+		return time.Time{}, false
+	}
+
+	return data.TransactionDate, true
+}
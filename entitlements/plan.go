@@ -0,0 +1,182 @@
+package entitlements
+
+import (
+	"asset_backend/models"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	PlanFree    = "free"
+	PlanPremium = "premium"
+)
+
+// Resources with quotas, daily transactions are counted by transaction date
+// and assets by to_asset/from_asset pair.
+const (
+	ResourceAsset            = "asset"
+	ResourceSubscription     = "subscription"
+	ResourceCard             = "card"
+	ResourceBankAccount      = "bank_account"
+	ResourceDailyTransaction = "daily_transaction"
+	ResourceBudget           = "budget"
+	ResourcePriceAlert       = "price_alert"
+	ResourceWatchlist        = "watchlist"
+)
+
+// Features that aren't counted, plans either have them or not.
+const (
+	FeatureSubscriptionNotification = "subscription_notification"
+	FeatureDailyStatsInterval       = "daily_stats_interval"
+)
+
+var Features = []string{FeatureSubscriptionNotification, FeatureDailyStatsInterval}
+
+// Unlimited is the limit of resources without quota.
+const Unlimited int64 = -1
+
+var Resources = []string{
+	ResourceAsset, ResourceSubscription, ResourceCard, ResourceBankAccount,
+	ResourceDailyTransaction, ResourceBudget, ResourcePriceAlert, ResourceWatchlist,
+}
+
+type Plan struct {
+	Name     string           `json:"name"`
+	Limits   map[string]int64 `json:"limits"`
+	Features map[string]bool  `json:"features"`
+}
+
+/**
+* Default plans can be overridden with the ENTITLEMENT_PLANS env as JSON, e.g.
+* {"free": {"asset": 15}, "premium": {"watchlist": 20}}. Only the given limits
+* are replaced, -1 removes the quota. Features are enabled with 1 and disabled
+* with 0, e.g. {"free": {"daily_stats_interval": 1}}.
+**/
+var (
+	plans = map[string]Plan{
+		PlanFree: {
+			Name: PlanFree,
+			Limits: map[string]int64{
+				ResourceAsset:            10,
+				ResourceSubscription:     5,
+				ResourceCard:             3,
+				ResourceBankAccount:      2,
+				ResourceDailyTransaction: 10,
+				ResourceBudget:           3,
+				ResourcePriceAlert:       3,
+				ResourceWatchlist:        5,
+			},
+			Features: map[string]bool{
+				FeatureSubscriptionNotification: false,
+				FeatureDailyStatsInterval:       false,
+			},
+		},
+		PlanPremium: {
+			Name: PlanPremium,
+			Limits: map[string]int64{
+				ResourceAsset:            Unlimited,
+				ResourceSubscription:     Unlimited,
+				ResourceCard:             Unlimited,
+				ResourceBankAccount:      Unlimited,
+				ResourceDailyTransaction: Unlimited,
+				ResourceBudget:           Unlimited,
+				ResourcePriceAlert:       20,
+				ResourceWatchlist:        10,
+			},
+			Features: map[string]bool{
+				FeatureSubscriptionNotification: true,
+				FeatureDailyStatsInterval:       true,
+			},
+		},
+	}
+	loadPlansOnce sync.Once
+)
+
+func GetPlan(isPremium bool) Plan {
+	loadPlansOnce.Do(loadPlans)
+
+	if isPremium {
+		return plans[PlanPremium]
+	}
+
+	return plans[PlanFree]
+}
+
+// GetLimit returns the quota of the resource, resources missing from the plan
+// are unlimited.
+func (plan Plan) GetLimit(resource string) int64 {
+	if limit, ok := plan.Limits[resource]; ok {
+		return limit
+	}
+
+	return Unlimited
+}
+
+// IsLimitReached reports whether count has reached the resource's quota.
+func (plan Plan) IsLimitReached(resource string, count int64) bool {
+	limit := plan.GetLimit(resource)
+
+	return limit != Unlimited && count >= limit
+}
+
+// HasFeature reports whether the plan includes the feature.
+func (plan Plan) HasFeature(feature string) bool {
+	return plan.Features[feature]
+}
+
+func loadPlans() {
+	config := os.Getenv("ENTITLEMENT_PLANS")
+	if config == "" {
+		return
+	}
+
+	var planLimits map[string]map[string]int64
+	if err := json.Unmarshal([]byte(config), &planLimits); err != nil {
+		logrus.Error("failed to parse entitlement plans, using defaults: ", err)
+		return
+	}
+
+	for name, limits := range planLimits {
+		plan, ok := plans[name]
+		if !ok {
+			logrus.WithFields(logrus.Fields{
+				"plan": name,
+			}).Error("unknown entitlement plan")
+
+			continue
+		}
+
+		for resource, limit := range limits {
+			if isFeature(resource) {
+				plan.Features[resource] = limit != 0
+				continue
+			}
+
+			plan.Limits[resource] = limit
+		}
+	}
+}
+
+func isFeature(name string) bool {
+	for _, feature := range Features {
+		if feature == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (plan Plan) GetUserDataImportLimits() models.UserDataImportLimits {
+	return models.UserDataImportLimits{
+		Assets:            plan.GetLimit(ResourceAsset),
+		Subscriptions:     plan.GetLimit(ResourceSubscription),
+		Cards:             plan.GetLimit(ResourceCard),
+		BankAccounts:      plan.GetLimit(ResourceBankAccount),
+		DailyTransactions: plan.GetLimit(ResourceDailyTransaction),
+		Watchlist:         plan.GetLimit(ResourceWatchlist),
+	}
+}
@@ -0,0 +1,137 @@
+package entitlements
+
+import (
+	"asset_backend/db"
+	"asset_backend/models"
+	"asset_backend/responses"
+	"fmt"
+	"time"
+)
+
+type QuotaService struct {
+	Database *db.MongoDB
+}
+
+func NewQuotaService(mongoDB *db.MongoDB) *QuotaService {
+	return &QuotaService{
+		Database: mongoDB,
+	}
+}
+
+var resourceNames = map[string]string{
+	ResourceAsset:            "assets",
+	ResourceSubscription:     "subscriptions",
+	ResourceCard:             "credit cards",
+	ResourceBankAccount:      "bank accounts",
+	ResourceDailyTransaction: "transactions per day",
+	ResourceBudget:           "budgets",
+	ResourcePriceAlert:       "price alerts",
+	ResourceWatchlist:        "watchlist items",
+}
+
+const (
+	errQuotaPremium         = "Free members can add up to %d %s, you can get premium membership for unlimited access."
+	errQuotaPremiumIncrease = "Free members can add up to %d %s, you can get premium membership to increase the limit."
+	errQuotaLimit           = "You've reached the limit."
+)
+
+func (service *QuotaService) GetUserPlan(uid string) Plan {
+	userModel := models.NewUserModel(service.Database)
+
+	return GetPlan(userModel.IsUserPremium(uid))
+}
+
+// GetUsage counts user's items of the resource, date is only used for daily
+// transactions.
+func (service *QuotaService) GetUsage(uid, resource string, date time.Time) int64 {
+	switch resource {
+	case ResourceAsset:
+		return models.NewAssetModel(service.Database).GetUserAssetCount(uid)
+	case ResourceSubscription:
+		return models.NewSubscriptionModel(service.Database).GetUserSubscriptionCount(uid)
+	case ResourceCard:
+		return models.NewCardModel(service.Database).GetUserCardCount(uid)
+	case ResourceBankAccount:
+		return models.NewBankAccountModel(service.Database).GetUserBankAccountCount(uid)
+	case ResourceDailyTransaction:
+		return models.NewTransactionModel(service.Database).GetUserTransactionCountByTime(uid, date)
+	case ResourceBudget:
+		return models.NewBudgetModel(service.Database).GetUserBudgetCount(uid)
+	case ResourcePriceAlert:
+		return models.NewPriceAlertModel(service.Database).GetPriceAlertCount(uid)
+	case ResourceWatchlist:
+		return models.NewFavouriteInvestingModel(service.Database).GetFavouriteInvestingsCount(uid)
+	default:
+		return 0
+	}
+}
+
+// CheckQuota returns the error message when user can't add another item of
+// the resource.
+func (service *QuotaService) CheckQuota(uid, resource string, date time.Time) (string, bool) {
+	plan := service.GetUserPlan(uid)
+	if plan.GetLimit(resource) == Unlimited {
+		return "", true
+	}
+
+	if plan.IsLimitReached(resource, service.GetUsage(uid, resource, date)) {
+		return GetQuotaError(plan, resource), false
+	}
+
+	return "", true
+}
+
+// GetQuotaError suggests premium membership when it has a higher limit.
+func GetQuotaError(plan Plan, resource string) string {
+	if plan.Name != PlanFree {
+		return errQuotaLimit
+	}
+
+	limit := plan.GetLimit(resource)
+
+	premiumLimit := GetPlan(true).GetLimit(resource)
+	if premiumLimit == Unlimited {
+		return fmt.Sprintf(errQuotaPremium, limit, resourceNames[resource])
+	} else if premiumLimit > limit {
+		return fmt.Sprintf(errQuotaPremiumIncrease, limit, resourceNames[resource])
+	}
+
+	return errQuotaLimit
+}
+
+// HasFeature reports whether user's plan includes the feature.
+func (service *QuotaService) HasFeature(uid, feature string) bool {
+	return service.GetUserPlan(uid).HasFeature(feature)
+}
+
+// GetEntitlements reports usage and limits of every resource, daily
+// transactions are counted for today.
+func (service *QuotaService) GetEntitlements(uid string) responses.Entitlements {
+	plan := service.GetUserPlan(uid)
+	now := time.Now().UTC()
+
+	entitlements := responses.Entitlements{
+		Plan:      plan.Name,
+		Resources: []responses.EntitlementUsage{},
+		Features:  map[string]bool{},
+	}
+
+	for _, feature := range Features {
+		entitlements.Features[feature] = plan.HasFeature(feature)
+	}
+
+	for _, resource := range Resources {
+		limit := plan.GetLimit(resource)
+		usage := service.GetUsage(uid, resource, now)
+
+		entitlements.Resources = append(entitlements.Resources, responses.EntitlementUsage{
+			Resource:    resource,
+			Usage:       usage,
+			Limit:       limit,
+			IsUnlimited: limit == Unlimited,
+			IsReached:   plan.IsLimitReached(resource, usage),
+		})
+	}
+
+	return entitlements
+}
@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+const adminKeyHeader = "X-Admin-Key"
+
+// AdminMiddleware only lets requests with ADMIN_SECRET_KEY in the X-Admin-Key
+// header through, every request is rejected when the key isn't set.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminKey := os.Getenv("ADMIN_SECRET_KEY")
+		requestKey := c.GetHeader(adminKeyHeader)
+
+		if adminKey == "" || subtle.ConstantTimeCompare([]byte(adminKey), []byte(requestKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized access."})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,307 @@
+package helpers
+
+import (
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AssetImportMaxRows     = 5000
+	AssetImportMaxFileSize = 5 << 20
+)
+
+var (
+	errUnknownImportProfile = errors.New("Unknown import profile.")
+	errImportHeaderNotFound = errors.New("Couldn't find the expected CSV header for the selected profile.")
+	errImportTooManyRows    = fmt.Errorf("CSV files can contain up to %d rows.", AssetImportMaxRows)
+)
+
+type assetImportProfile struct {
+	columns []string
+	parse   func(record map[string]string, defaultMarket string) (requests.AssetImportRow, error)
+}
+
+// Column names are matched case insensitively. Exchange exports don't carry the
+// market, so the market supplied with the upload is used for them.
+var assetImportProfiles = map[string]assetImportProfile{
+	"generic": {
+		columns: []string{"date", "to_asset", "from_asset", "asset_type", "type", "amount", "price"},
+		parse:   parseGenericImportRecord,
+	},
+	"binance": {
+		columns: []string{"date(utc)", "pair", "side", "price", "executed"},
+		parse:   parseBinanceImportRecord,
+	},
+	"coinbase": {
+		columns: []string{
+			"timestamp", "transaction type", "asset", "quantity transacted",
+			"spot price currency", "spot price at transaction",
+		},
+		parse: parseCoinbaseImportRecord,
+	},
+}
+
+var importDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05Z",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02",
+}
+
+// Quote assets ordered so that longer symbols are matched before their prefixes.
+var binanceQuoteAssets = []string{"USDT", "BUSD", "USDC", "TUSD", "FDUSD", "USD", "EUR", "GBP", "TRY", "BTC", "ETH", "BNB"}
+
+func ParseAssetImportCSV(reader io.Reader, profileName, defaultMarket string) ([]requests.AssetImportRow, []responses.AssetImportError, error) {
+	profile, ok := assetImportProfiles[profileName]
+	if !ok {
+		return nil, nil, errUnknownImportProfile
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	var (
+		columnIndexes map[string]int
+		dataRows      int
+		rows          []requests.AssetImportRow
+		rowErrors     []responses.AssetImportError
+	)
+
+	// Rows are parsed while reading, so files over the row limit are rejected
+	// without reading them to the end.
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("Failed to read CSV file: %w", err)
+		}
+
+		// Some exports have a preamble, so the header is the first line containing every column.
+		if columnIndexes == nil {
+			if indexes, ok := getImportColumnIndexes(record, profile.columns); ok {
+				columnIndexes = indexes
+			}
+
+			continue
+		}
+
+		if dataRows++; dataRows > AssetImportMaxRows {
+			return nil, nil, errImportTooManyRows
+		}
+
+		rowNumber, _ := csvReader.FieldPos(0)
+
+		if isEmptyImportRecord(record) {
+			continue
+		}
+
+		mappedRecord := make(map[string]string, len(columnIndexes))
+		for column, index := range columnIndexes {
+			if index < len(record) {
+				mappedRecord[column] = strings.TrimSpace(record[index])
+			}
+		}
+
+		row, err := profile.parse(mappedRecord, defaultMarket)
+		if err != nil {
+			rowErrors = append(rowErrors, responses.AssetImportError{
+				Row:   rowNumber,
+				Error: err.Error(),
+			})
+
+			continue
+		}
+
+		row.Row = rowNumber
+		rows = append(rows, row)
+	}
+
+	if columnIndexes == nil {
+		return nil, nil, errImportHeaderNotFound
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseGenericImportRecord(record map[string]string, defaultMarket string) (requests.AssetImportRow, error) {
+	date, err := parseImportDate(record["date"])
+	if err != nil {
+		return requests.AssetImportRow{}, err
+	}
+
+	amount, err := parseImportNumber("amount", record["amount"])
+	if err != nil {
+		return requests.AssetImportRow{}, err
+	}
+
+	price, err := parseImportNumber("price", record["price"])
+	if err != nil {
+		return requests.AssetImportRow{}, err
+	}
+
+	market := record["asset_market"]
+	if market == "" {
+		market = defaultMarket
+	}
+
+	return requests.AssetImportRow{
+		AssetCreate: requests.AssetCreate{
+			ToAsset:     record["to_asset"],
+			FromAsset:   record["from_asset"],
+			Price:       price,
+			Amount:      amount,
+			AssetType:   strings.ToLower(record["asset_type"]),
+			AssetMarket: market,
+			Type:        strings.ToLower(record["type"]),
+		},
+		CreatedAt: date,
+	}, nil
+}
+
+func parseBinanceImportRecord(record map[string]string, defaultMarket string) (requests.AssetImportRow, error) {
+	date, err := parseImportDate(record["date(utc)"])
+	if err != nil {
+		return requests.AssetImportRow{}, err
+	}
+
+	price, err := parseImportNumber("price", record["price"])
+	if err != nil {
+		return requests.AssetImportRow{}, err
+	}
+
+	pair := strings.ToUpper(record["pair"])
+
+	var toAsset, fromAsset string
+	for _, quoteAsset := range binanceQuoteAssets {
+		if strings.HasSuffix(pair, quoteAsset) && len(pair) > len(quoteAsset) {
+			toAsset = strings.TrimSuffix(pair, quoteAsset)
+			fromAsset = quoteAsset
+
+			break
+		}
+	}
+
+	if toAsset == "" {
+		return requests.AssetImportRow{}, fmt.Errorf("Couldn't resolve pair %s.", record["pair"])
+	}
+
+	// Executed is formatted as amount followed by the base asset, e.g. 0.0150BTC.
+	executed := strings.TrimSuffix(strings.ToUpper(record["executed"]), toAsset)
+
+	amount, err := parseImportNumber("executed", executed)
+	if err != nil {
+		return requests.AssetImportRow{}, err
+	}
+
+	return requests.AssetImportRow{
+		AssetCreate: requests.AssetCreate{
+			ToAsset:     toAsset,
+			FromAsset:   fromAsset,
+			Price:       price,
+			Amount:      amount,
+			AssetType:   "crypto",
+			AssetMarket: defaultMarket,
+			Type:        strings.ToLower(record["side"]),
+		},
+		CreatedAt: date,
+	}, nil
+}
+
+func parseCoinbaseImportRecord(record map[string]string, defaultMarket string) (requests.AssetImportRow, error) {
+	var tType string
+
+	switch strings.ToLower(record["transaction type"]) {
+	case "buy", "advanced trade buy":
+		tType = "buy"
+	case "sell", "advanced trade sell":
+		tType = "sell"
+	case "staking income", "rewards income", "inflation reward":
+		tType = "staking"
+	case "learning reward":
+		tType = "airdrop"
+	default:
+		return requests.AssetImportRow{}, fmt.Errorf("Unsupported transaction type %s.", record["transaction type"])
+	}
+
+	date, err := parseImportDate(record["timestamp"])
+	if err != nil {
+		return requests.AssetImportRow{}, err
+	}
+
+	amount, err := parseImportNumber("quantity transacted", record["quantity transacted"])
+	if err != nil {
+		return requests.AssetImportRow{}, err
+	}
+
+	price, err := parseImportNumber("spot price at transaction", record["spot price at transaction"])
+	if err != nil {
+		return requests.AssetImportRow{}, err
+	}
+
+	return requests.AssetImportRow{
+		AssetCreate: requests.AssetCreate{
+			ToAsset:     strings.ToUpper(record["asset"]),
+			FromAsset:   record["spot price currency"],
+			Price:       price,
+			Amount:      amount,
+			AssetType:   "crypto",
+			AssetMarket: defaultMarket,
+			Type:        tType,
+		},
+		CreatedAt: date,
+	}, nil
+}
+
+func getImportColumnIndexes(header, columns []string) (map[string]int, bool) {
+	headerIndexes := make(map[string]int, len(header))
+	for i, column := range header {
+		headerIndexes[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range columns {
+		if _, ok := headerIndexes[column]; !ok {
+			return nil, false
+		}
+	}
+
+	return headerIndexes, true
+}
+
+func isEmptyImportRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range importDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid date %s.", value)
+}
+
+func parseImportNumber(column, value string) (float64, error) {
+	number, err := strconv.ParseFloat(strings.TrimLeft(strings.ReplaceAll(value, ",", ""), "$€£"), 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("Invalid %s value %s.", column, value)
+	}
+
+	return number, nil
+}
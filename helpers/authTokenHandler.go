@@ -32,7 +32,7 @@ func SetupJWTHandler(mongoDB *db.MongoDB) *jwt.GinJWTMiddleware {
 	authMiddleware, err := jwt.New(&jwt.GinJWTMiddleware{
 		Realm:       "asset-manager",
 		Key:         []byte(os.Getenv("JWT_SECRET_KEY")),
-		Timeout:     time.Hour * 72,
+		Timeout:     AccessTokenTimeout,
 		IdentityKey: identityKey,
 		Authenticator: func(c *gin.Context) (interface{}, error) {
 			var data requests.Login
@@ -50,38 +50,75 @@ func SetupJWTHandler(mongoDB *db.MongoDB) *jwt.GinJWTMiddleware {
 				return "", errEmptyPassword
 			}
 
+			uid := user.ID.Hex()
+			if lockTime, isLocked := GetLoginLockTime(uid); isLocked {
+				return "", getLoginLockError(lockTime)
+			}
+
 			if err := utils.CheckPassword([]byte(user.Password), []byte(data.Password)); err != nil {
 				logrus.WithFields(logrus.Fields{
 					"email_address": data.EmailAddress,
 					"uid":           user.ID,
 				}).Error("failed to check password: ", err)
 
+				RecordFailedLogin(uid)
+
 				return "", errIncorrectAuth
 			}
 
-			return user, nil
+			ResetFailedLogins(uid)
+
+			// Password logins of users with two factor continue with the code
+			// of the challenge.
+			if user.TwoFactorEnabled {
+				challengeToken, err := CreateTwoFactorChallenge(uid, data.DeviceID, data.DeviceName)
+				if err != nil {
+					return "", err
+				}
+
+				c.Set(twoFactorChallengeContextKey, challengeToken)
+
+				return "", ErrTwoFactorRequired
+			}
+
+			identity, refreshToken, err := CreateSession(c, mongoDB, user, data.DeviceID, data.DeviceName)
+			if err != nil {
+				return "", err
+			}
+
+			c.Set(refreshTokenContextKey, refreshToken)
+
+			return identity, nil
 		},
 		PayloadFunc: func(data interface{}) jwt.MapClaims {
-			if user, ok := data.(models.User); ok {
+			if identity, ok := data.(SessionIdentity); ok {
 				return jwt.MapClaims{
-					identityKey: user.ID,
+					identityKey: identity.User.ID,
+					sessionKey:  identity.SessionID,
 				}
 			}
 			return jwt.MapClaims{}
 		},
+		// Tokens of revoked sessions are rejected until they expire.
+		Authorizator: func(data interface{}, c *gin.Context) bool {
+			sessionID := GetSessionID(jwt.ExtractClaims(c))
+
+			return sessionID == "" || !isSessionRevoked(sessionID)
+		},
 		Unauthorized: func(c *gin.Context, code int, message string) {
+			if challengeToken, ok := c.Get(twoFactorChallengeContextKey); ok {
+				c.JSON(http.StatusOK, gin.H{"two_factor_required": true, "challenge_token": challengeToken})
+				return
+			}
+
 			c.JSON(code, gin.H{
 				"code":    code,
 				"message": message,
 			})
 		},
 		LoginResponse: func(c *gin.Context, code int, token string, expire time.Time) {
-			c.SetCookie("access_token", token, 259200, "/", os.Getenv("BASE_URI"), true, true)
-			c.JSON(http.StatusOK, gin.H{"access_token": token})
-		},
-		RefreshResponse: func(c *gin.Context, code int, token string, expire time.Time) {
-			c.SetCookie("access_token", token, 259200, "/", os.Getenv("BASE_URI"), true, true)
-			c.JSON(http.StatusOK, gin.H{"access_token": token})
+			c.SetCookie("access_token", token, int(AccessTokenTimeout.Seconds()), "/", os.Getenv("BASE_URI"), true, true)
+			c.JSON(http.StatusOK, gin.H{"access_token": token, "refresh_token": c.GetString(refreshTokenContextKey)})
 		},
 		TokenLookup:    "header: Authorization, cookie: jwt_token",
 		TimeFunc:       time.Now,
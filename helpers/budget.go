@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"asset_backend/models"
+	"fmt"
+)
+
+var budgetCategoryNames = map[int64]string{
+	models.Food:           "Food",
+	models.Shopping:       "Shopping",
+	models.Transportation: "Transportation",
+	models.Entertainment:  "Entertainment",
+	models.Software:       "Software",
+	models.Health:         "Health",
+	models.Income:         "Income",
+	models.Others:         "Others",
+}
+
+func SendBudgetAlerts(alerts []models.BudgetAlert) {
+	dataType := "budget"
+
+	for _, alert := range alerts {
+		dataID := alert.BudgetID
+		categoryName := budgetCategoryNames[alert.Category]
+
+		var message string
+		if alert.Threshold >= 100 {
+			message = fmt.Sprintf(
+				"You've exceeded your %s budget. Spent %s %.2f of %.2f.",
+				categoryName, alert.Currency, alert.Spent, alert.Limit,
+			)
+		} else {
+			message = fmt.Sprintf(
+				"You've used %d%% of your %s budget. Spent %s %.2f of %.2f.",
+				alert.Threshold, categoryName, alert.Currency, alert.Spent, alert.Limit,
+			)
+		}
+
+		SendNotification(alert.FCMToken, categoryName+" Budget", message, &dataType, &dataID)
+	}
+}
@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"asset_backend/db"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/**
+* Accounts are locked after 5 failed password logins in a day, the lock starts
+* at a minute and doubles with every following failure up to an hour. It's
+* per account, so it works together with the IP rate limiter.
+**/
+const (
+	loginLockThreshold  = 5
+	loginBaseLockTime   = time.Minute
+	loginMaxLockTime    = time.Hour
+	loginFailureTimeout = 24 * time.Hour
+
+	loginFailurePrefix = "login-failures/"
+	loginLockPrefix    = "login-lock/"
+)
+
+// GetLoginLockTime returns the remaining lock time of the account.
+func GetLoginLockTime(uid string) (time.Duration, bool) {
+	lockTime, err := db.RedisDB.TTL(context.TODO(), loginLockPrefix+uid).Result()
+	if err != nil || lockTime <= 0 {
+		return 0, false
+	}
+
+	return lockTime, true
+}
+
+func RecordFailedLogin(uid string) {
+	failures, err := db.RedisDB.Incr(context.TODO(), loginFailurePrefix+uid).Result()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to record failed login: ", err)
+
+		return
+	}
+
+	db.RedisDB.Expire(context.TODO(), loginFailurePrefix+uid, loginFailureTimeout)
+
+	if failures < loginLockThreshold {
+		return
+	}
+
+	const maxExponent = 6
+
+	lockTime := loginMaxLockTime
+	if exponent := failures - loginLockThreshold; exponent < maxExponent {
+		lockTime = loginBaseLockTime << exponent
+	}
+
+	if lockTime > loginMaxLockTime {
+		lockTime = loginMaxLockTime
+	}
+
+	db.RedisDB.Set(context.TODO(), loginLockPrefix+uid, failures, lockTime)
+}
+
+func ResetFailedLogins(uid string) {
+	db.RedisDB.Del(context.TODO(), loginFailurePrefix+uid, loginLockPrefix+uid)
+}
+
+func getLoginLockError(lockTime time.Duration) error {
+	return fmt.Errorf("Too many failed login attempts, please try again in %d minutes", int(math.Ceil(lockTime.Minutes())))
+}
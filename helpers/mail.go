@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"net/smtp"
+	"net/url"
 	"os"
 
 	"github.com/jordan-wright/email"
@@ -99,6 +100,98 @@ func SendForgotPasswordEmail(token, mail string) error {
 	return nil
 }
 
+func SendVerificationEmail(token, mail string) error {
+	verificationURL := (os.Getenv("BASE_URI") + "/confirm-email?token=" + url.QueryEscape(token) + "&mail=" + url.QueryEscape(mail))
+
+	e := email.NewEmail()
+	e.From = "Kanma <" + os.Getenv("FROM_MAIL") + ">"
+	e.To = []string{mail}
+	e.Subject = "Verify Your Email"
+	e.HTML = []byte(
+		`<!doctype html>
+		<html lang="en-US">
+		
+		<head>
+			<meta content="text/html; charset=utf-8" http-equiv="Content-Type" />
+			<title>Verify Email</title>
+			<meta name="description" content="Verify Email.">
+			<style type="text/css">
+				a:hover {text-decoration: underline !important;}
+			</style>
+		</head>
+		
+		<body marginheight="0" topmargin="0" marginwidth="0" style="margin: 0px; background-color: #f2f3f8;" leftmargin="0">
+			<table cellspacing="0" border="0" cellpadding="0" width="100%" bgcolor="#f2f3f8"
+				style="@import url(https://fonts.googleapis.com/css?family=Rubik:300,400,500,700|Open+Sans:300,400,600,700); font-family: 'Open Sans', sans-serif;">
+				<tr>
+					<td>
+						<table style="background-color: #f2f3f8; max-width:670px;  margin:0 auto;" width="100%" border="0"
+							align="center" cellpadding="0" cellspacing="0">
+							<tr>
+								<td style="height:80px;">&nbsp;</td>
+							</tr>
+							<tr>
+								<td style="text-align:center;">
+									<img width="100" src="https://user-images.githubusercontent.com/25686023/155740270-208e9079-a139-4810-b02c-2977c602919d.png" title="logo" alt="logo">
+								</td>
+							</tr>
+							<tr>
+								<td style="height:20px;">&nbsp;</td>
+							</tr>
+							<tr>
+								<td>
+									<table width="95%" border="0" align="center" cellpadding="0" cellspacing="0"
+										style="max-width:670px;background:#fff; border-radius:3px; text-align:center;-webkit-box-shadow:0 6px 18px 0 rgba(0,0,0,.06);-moz-box-shadow:0 6px 18px 0 rgba(0,0,0,.06);box-shadow:0 6px 18px 0 rgba(0,0,0,.06);">
+										<tr>
+											<td style="height:40px;">&nbsp;</td>
+										</tr>
+										<tr>
+											<td style="padding:0 35px;">
+												<h1 style="color:#1e1e2d; font-weight:500; margin:0;font-size:32px;font-family:'Rubik',sans-serif;">Verify your email
+													address</h1>
+												<span
+													style="display:inline-block; vertical-align:middle; margin:29px 0 26px; border-bottom:1px solid #cecece; width:100px;"></span>
+												<p style="color:#455056; font-size:15px;line-height:24px; margin:0;">
+													Thanks for signing up. Please verify your email address by clicking the
+													following link, the link expires in 24 hours. You can ignore this email
+													if you didn't create an account.
+												</p>
+												<a href="` + verificationURL + `"
+													style="background:#20e277;text-decoration:none !important; font-weight:500; margin-top:35px; color:#fff;text-transform:uppercase; font-size:14px;padding:10px 24px;display:inline-block;border-radius:50px;">Verify
+													Email</a>
+											</td>
+										</tr>
+										<tr>
+											<td style="height:40px;">&nbsp;</td>
+										</tr>
+									</table>
+								</td>
+							<tr>
+								<td style="height:20px;">&nbsp;</td>
+							</tr>
+							<tr>
+								<td style="text-align:center;">
+									<p style="font-size:14px; color:rgba(69, 80, 86, 0.7411764705882353); line-height:18px; margin:0 0 0;">&copy; <strong>Kanma</strong></p>
+								</td>
+							</tr>
+							<tr>
+								<td style="height:80px;">&nbsp;</td>
+							</tr>
+						</table>
+					</td>
+				</tr>
+			</table>
+		</body>
+		</html>`,
+	)
+	err := e.Send("smtp.gmail.com:587", smtp.PlainAuth("", os.Getenv("FROM_MAIL"), os.Getenv("FROM_MAIL_PASSWORD"), "smtp.gmail.com"))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func SendPasswordChangedEmail(content, mail string) error {
 	e := email.NewEmail()
 	e.From = "Kanma <" + os.Getenv("FROM_MAIL") + ">"
@@ -183,3 +276,85 @@ func SendPasswordChangedEmail(content, mail string) error {
 
 	return nil
 }
+
+func SendPriceAlertEmail(title, content, mail string) error {
+	e := email.NewEmail()
+	e.From = "Kanma <" + os.Getenv("FROM_MAIL") + ">"
+	e.To = []string{mail}
+	e.Subject = title
+	e.HTML = []byte(
+		`<!doctype html>
+		<html lang="en-US">
+		<head>
+			<meta content="text/html; charset=utf-8" http-equiv="Content-Type" />
+			<title>Price Alert</title>
+			<meta name="description" content="Price alert.">
+			<style type="text/css">
+				a:hover {text-decoration: underline !important;}
+			</style>
+		</head>
+
+		<body marginheight="0" topmargin="0" marginwidth="0" style="margin: 0px; background-color: #f2f3f8;" leftmargin="0">
+			<table cellspacing="0" border="0" cellpadding="0" width="100%" bgcolor="#f2f3f8"
+				style="@import url(https://fonts.googleapis.com/css?family=Rubik:300,400,500,700|Open+Sans:300,400,600,700); font-family: 'Open Sans', sans-serif;">
+				<tr>
+					<td>
+						<table style="background-color: #f2f3f8; max-width:670px;  margin:0 auto;" width="100%" border="0"
+							align="center" cellpadding="0" cellspacing="0">
+							<tr>
+								<td style="height:80px;">&nbsp;</td>
+							</tr>
+							<tr>
+								<td style="text-align:center;">
+									<img width="100" src="https://user-images.githubusercontent.com/25686023/155740270-208e9079-a139-4810-b02c-2977c602919d.png" title="logo" alt="logo">
+								</td>
+							</tr>
+							<tr>
+								<td style="height:20px;">&nbsp;</td>
+							</tr>
+							<tr>
+								<td>
+									<table width="95%" border="0" align="center" cellpadding="0" cellspacing="0"
+										style="max-width:670px;background:#fff; border-radius:3px; text-align:center;-webkit-box-shadow:0 6px 18px 0 rgba(0,0,0,.06);-moz-box-shadow:0 6px 18px 0 rgba(0,0,0,.06);box-shadow:0 6px 18px 0 rgba(0,0,0,.06);">
+										<tr>
+											<td style="height:40px;">&nbsp;</td>
+										</tr>
+										<tr>
+											<td style="padding:0 35px;">
+												<h1 style="color:#1e1e2d; font-weight:500; margin:0;font-size:32px;font-family:'Rubik',sans-serif;">` + title + `</h1>
+												<span
+													style="display:inline-block; vertical-align:middle; margin:29px 0 26px; border-bottom:1px solid #cecece; width:100px;"></span>
+												<p style="color:#455056; font-size:15px;line-height:24px; margin:0;">` + content + `</p>
+											</td>
+										</tr>
+										<tr>
+											<td style="height:40px;">&nbsp;</td>
+										</tr>
+									</table>
+								</td>
+							<tr>
+								<td style="height:20px;">&nbsp;</td>
+							</tr>
+							<tr>
+								<td style="text-align:center;">
+									<p style="font-size:14px; color:rgba(69, 80, 86, 0.7411764705882353); line-height:18px; margin:0 0 0;">&copy; <strong>Kanma</strong></p>
+								</td>
+							</tr>
+							<tr>
+								<td style="height:80px;">&nbsp;</td>
+							</tr>
+						</table>
+					</td>
+				</tr>
+			</table>
+		</body>
+		</html>
+		`,
+	)
+	err := e.Send("smtp.gmail.com:587", smtp.PlainAuth("", os.Getenv("FROM_MAIL"), os.Getenv("FROM_MAIL_PASSWORD"), "smtp.gmail.com"))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package helpers
+
+import (
+	"asset_backend/models"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+func SendPriceAlerts(notifications []models.PriceAlertNotification) {
+	dataType := "price_alert"
+
+	for _, notification := range notifications {
+		dataID := notification.AlertID
+		title := notification.InvestingID.Symbol + " Price Alert"
+
+		var message string
+		switch notification.AlertType {
+		case models.PriceAlertAbove:
+			message = fmt.Sprintf(
+				"%s is above %.2f. Current price: %s %.2f",
+				notification.InvestingID.Symbol, notification.Value, notification.Currency, notification.Price,
+			)
+		case models.PriceAlertBelow:
+			message = fmt.Sprintf(
+				"%s is below %.2f. Current price: %s %.2f",
+				notification.InvestingID.Symbol, notification.Value, notification.Currency, notification.Price,
+			)
+		default:
+			message = fmt.Sprintf(
+				"%s changed %.2f%% in the last 24 hours. Current price: %s %.2f",
+				notification.InvestingID.Symbol, notification.Change, notification.Currency, notification.Price,
+			)
+		}
+
+		if notification.FCMToken != "" {
+			SendNotification(notification.FCMToken, title, message, &dataType, &dataID)
+		}
+
+		if notification.EmailAddress != "" {
+			if err := SendPriceAlertEmail(title, message, notification.EmailAddress); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"price_alert_id": notification.AlertID,
+				}).Error("failed to send price alert email: ", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package helpers
+
+import (
+	"asset_backend/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2/google"
+)
+
+// ReceiptVerifier validates a store receipt of the product. Stores are set by
+// platform, so they can be replaced with fakes.
+type ReceiptVerifier interface {
+	VerifyReceipt(receipt, productID string) (models.ReceiptVerification, error)
+}
+
+var (
+	receiptVerifiers     = make(map[string]ReceiptVerifier)
+	receiptVerifierMutex sync.Mutex
+
+	errUnknownProduct     = errors.New("Product is not available.")
+	errReceiptBundleID    = invalidReceiptError("Receipt belongs to another app.")
+	errProductNotFound    = invalidReceiptError("Product is not in the receipt.")
+	errSubscriptionExpiry = invalidReceiptError("Subscription has no expiry.")
+)
+
+// invalidReceiptError is returned when the store rejects the receipt, other
+// errors are transport or configuration errors that can be retried.
+type invalidReceiptError string
+
+func (err invalidReceiptError) Error() string {
+	return string(err)
+}
+
+func IsInvalidReceipt(err error) bool {
+	var invalidReceipt invalidReceiptError
+
+	return errors.As(err, &invalidReceipt)
+}
+
+const receiptVerifierTimeout = 15 * time.Second
+
+func SetReceiptVerifier(platform string, verifier ReceiptVerifier) {
+	receiptVerifierMutex.Lock()
+	defer receiptVerifierMutex.Unlock()
+
+	receiptVerifiers[platform] = verifier
+}
+
+// GetReceiptVerifier returns the verifier of the platform, store verifiers
+// are created from env on first use.
+func GetReceiptVerifier(platform string) (ReceiptVerifier, error) {
+	receiptVerifierMutex.Lock()
+	defer receiptVerifierMutex.Unlock()
+
+	if verifier, ok := receiptVerifiers[platform]; ok {
+		return verifier, nil
+	}
+
+	var verifier ReceiptVerifier
+	switch platform {
+	case models.PurchasePlatformAppStore:
+		verifier = &AppStoreVerifier{
+			SharedSecret:       os.Getenv("APP_STORE_SHARED_SECRET"),
+			BundleID:           os.Getenv("APP_STORE_BUNDLE_ID"),
+			ProductIDs:         getProductIDsFromEnv("APP_STORE_PRODUCT_IDS"),
+			LifetimeProductIDs: getProductIDsFromEnv("APP_STORE_LIFETIME_PRODUCT_IDS"),
+			Client:             &http.Client{Timeout: receiptVerifierTimeout},
+		}
+	case models.PurchasePlatformPlayStore:
+		client, err := google.JWTConfigFromJSON(
+			[]byte(os.Getenv("PLAY_STORE_SERVICE_ACCOUNT")),
+			"https://www.googleapis.com/auth/androidpublisher",
+		)
+		if err != nil {
+			logrus.Error("failed to create play store client: ", err)
+
+			return nil, fmt.Errorf("Play Store verification is not configured.")
+		}
+
+		verifier = &PlayStoreVerifier{
+			PackageName:        os.Getenv("PLAY_STORE_PACKAGE_NAME"),
+			ProductIDs:         getProductIDsFromEnv("PLAY_STORE_PRODUCT_IDS"),
+			LifetimeProductIDs: getProductIDsFromEnv("PLAY_STORE_LIFETIME_PRODUCT_IDS"),
+			Client:             client.Client(context.Background()),
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported platform.")
+	}
+
+	receiptVerifiers[platform] = verifier
+
+	return verifier, nil
+}
+
+// getProductIDsFromEnv returns the comma separated product ids of the env.
+func getProductIDsFromEnv(key string) []string {
+	var productIDs []string
+	for _, productID := range strings.Split(os.Getenv(key), ",") {
+		if productID = strings.TrimSpace(productID); productID != "" {
+			productIDs = append(productIDs, productID)
+		}
+	}
+
+	return productIDs
+}
+
+// getProductType returns if the product is a lifetime product, products that
+// aren't in either list are rejected.
+func getProductType(productID string, productIDs, lifetimeProductIDs []string) (isLifetime bool, err error) {
+	for _, lifetimeProductID := range lifetimeProductIDs {
+		if lifetimeProductID == productID {
+			return true, nil
+		}
+	}
+
+	for _, subscriptionProductID := range productIDs {
+		if subscriptionProductID == productID {
+			return false, nil
+		}
+	}
+
+	return false, errUnknownProduct
+}
+
+/**
+* App Store receipts are verified with verifyReceipt, sandbox receipts are sent
+* to the sandbox endpoint. Receipts have to belong to the bundle and products
+* have to be configured, ProductIDs are subscriptions and LifetimeProductIDs
+* are non-consumables. The latest transaction of the product is used.
+**/
+type AppStoreVerifier struct {
+	SharedSecret       string
+	BundleID           string
+	ProductIDs         []string
+	LifetimeProductIDs []string
+	Client             *http.Client
+}
+
+type appStoreResponse struct {
+	Status  int `json:"status"`
+	Receipt struct {
+		BundleID string                `json:"bundle_id"`
+		InApp    []appStoreTransaction `json:"in_app"`
+	} `json:"receipt"`
+	LatestReceiptInfo []appStoreTransaction `json:"latest_receipt_info"`
+}
+
+type appStoreTransaction struct {
+	ProductID             string `json:"product_id"`
+	OriginalTransactionID string `json:"original_transaction_id"`
+	PurchaseDateMs        string `json:"purchase_date_ms"`
+	ExpiresDateMs         string `json:"expires_date_ms"`
+	CancellationDateMs    string `json:"cancellation_date_ms"`
+}
+
+const (
+	appStoreProductionURL = "https://buy.itunes.apple.com/verifyReceipt"
+	appStoreSandboxURL    = "https://sandbox.itunes.apple.com/verifyReceipt"
+	appStoreSandboxStatus = 21007
+)
+
+// App Store statuses that reject the receipt itself, the others are server,
+// request or shared secret errors.
+var appStoreInvalidReceiptStatuses = map[int]bool{
+	21003: true,
+	21006: true,
+	21010: true,
+}
+
+// Play Store statuses that reject the purchase token itself.
+var playStoreInvalidPurchaseStatuses = map[int]bool{
+	http.StatusBadRequest: true,
+	http.StatusNotFound:   true,
+	http.StatusGone:       true,
+}
+
+func (verifier *AppStoreVerifier) VerifyReceipt(receipt, productID string) (models.ReceiptVerification, error) {
+	isLifetime, err := getProductType(productID, verifier.ProductIDs, verifier.LifetimeProductIDs)
+	if err != nil {
+		return models.ReceiptVerification{}, err
+	}
+
+	response, err := verifier.postReceipt(appStoreProductionURL, receipt)
+	if err == nil && response.Status == appStoreSandboxStatus {
+		response, err = verifier.postReceipt(appStoreSandboxURL, receipt)
+	}
+
+	if err != nil {
+		return models.ReceiptVerification{}, err
+	}
+
+	return verifier.getVerification(response, productID, isLifetime)
+}
+
+// getVerification returns the latest transaction of the product in the
+// receipt, subscription transactions without expiry are skipped.
+func (verifier *AppStoreVerifier) getVerification(response appStoreResponse, productID string, isLifetime bool) (models.ReceiptVerification, error) {
+	if response.Status != 0 {
+		err := fmt.Errorf("App Store receipt is invalid, status %d.", response.Status)
+		if appStoreInvalidReceiptStatuses[response.Status] {
+			err = invalidReceiptError(err.Error())
+		}
+
+		return models.ReceiptVerification{}, err
+	}
+
+	if response.Receipt.BundleID != verifier.BundleID {
+		return models.ReceiptVerification{}, errReceiptBundleID
+	}
+
+	var (
+		verification models.ReceiptVerification
+		isFound      bool
+	)
+
+	for _, transaction := range append(response.LatestReceiptInfo, response.Receipt.InApp...) {
+		if transaction.ProductID != productID || transaction.CancellationDateMs != "" {
+			continue
+		}
+
+		current := models.ReceiptVerification{
+			ProductID:     transaction.ProductID,
+			TransactionID: transaction.OriginalTransactionID,
+			IsLifetime:    isLifetime,
+			PurchasedAt:   parseMillis(transaction.PurchaseDateMs),
+		}
+
+		if !isLifetime {
+			if transaction.ExpiresDateMs == "" {
+				continue
+			}
+
+			expiresAt := parseMillis(transaction.ExpiresDateMs)
+			current.ExpiresAt = &expiresAt
+		}
+
+		if !isFound || (!isLifetime && current.ExpiresAt.After(*verification.ExpiresAt)) {
+			verification = current
+			isFound = true
+		}
+	}
+
+	if !isFound {
+		return models.ReceiptVerification{}, errProductNotFound
+	}
+
+	return verification, nil
+}
+
+func (verifier *AppStoreVerifier) postReceipt(endpoint, receipt string) (appStoreResponse, error) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"receipt-data":             receipt,
+		"password":                 verifier.SharedSecret,
+		"exclude-old-transactions": true,
+	})
+
+	response, err := verifier.Client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		logrus.Error("failed to verify app store receipt: ", err)
+
+		return appStoreResponse{}, fmt.Errorf("Failed to reach App Store.")
+	}
+	defer response.Body.Close()
+
+	var appStoreResp appStoreResponse
+	if err := json.NewDecoder(response.Body).Decode(&appStoreResp); err != nil {
+		logrus.Error("failed to decode app store response: ", err)
+
+		return appStoreResponse{}, fmt.Errorf("Failed to verify App Store receipt.")
+	}
+
+	return appStoreResp, nil
+}
+
+/**
+* Play Store receipts are purchase tokens verified with the Android Publisher
+* API. Products have to be configured, LifetimeProductIDs are one-time
+* products and ProductIDs are subscriptions.
+**/
+type PlayStoreVerifier struct {
+	PackageName        string
+	ProductIDs         []string
+	LifetimeProductIDs []string
+	Client             *http.Client
+}
+
+type playStoreSubscription struct {
+	StartTimeMillis  string `json:"startTimeMillis"`
+	ExpiryTimeMillis string `json:"expiryTimeMillis"`
+}
+
+type playStoreProduct struct {
+	PurchaseState      int    `json:"purchaseState"`
+	PurchaseTimeMillis string `json:"purchaseTimeMillis"`
+}
+
+const playStoreBaseURL = "https://androidpublisher.googleapis.com/androidpublisher/v3/applications/"
+
+func (verifier *PlayStoreVerifier) VerifyReceipt(receipt, productID string) (models.ReceiptVerification, error) {
+	isLifetime, err := getProductType(productID, verifier.ProductIDs, verifier.LifetimeProductIDs)
+	if err != nil {
+		return models.ReceiptVerification{}, err
+	}
+
+	verification := models.ReceiptVerification{
+		ProductID:     productID,
+		TransactionID: receipt,
+		IsLifetime:    isLifetime,
+	}
+
+	if isLifetime {
+		var product playStoreProduct
+		if err := verifier.get("products", receipt, productID, &product); err != nil {
+			return models.ReceiptVerification{}, err
+		}
+
+		if product.PurchaseState != 0 {
+			return models.ReceiptVerification{}, invalidReceiptError("Play Store purchase is not completed.")
+		}
+
+		verification.PurchasedAt = parseMillis(product.PurchaseTimeMillis)
+
+		return verification, nil
+	}
+
+	var subscription playStoreSubscription
+	if err := verifier.get("subscriptions", receipt, productID, &subscription); err != nil {
+		return models.ReceiptVerification{}, err
+	}
+
+	if subscription.ExpiryTimeMillis == "" {
+		return models.ReceiptVerification{}, errSubscriptionExpiry
+	}
+
+	expiresAt := parseMillis(subscription.ExpiryTimeMillis)
+	verification.PurchasedAt = parseMillis(subscription.StartTimeMillis)
+	verification.ExpiresAt = &expiresAt
+
+	return verification, nil
+}
+
+func (verifier *PlayStoreVerifier) get(purchaseType, token, productID string, result interface{}) error {
+	endpoint := playStoreBaseURL + url.PathEscape(verifier.PackageName) + "/purchases/" + purchaseType + "/" +
+		url.PathEscape(productID) + "/tokens/" + url.PathEscape(token)
+
+	response, err := verifier.Client.Get(endpoint)
+	if err != nil {
+		logrus.Error("failed to verify play store purchase: ", err)
+
+		return fmt.Errorf("Failed to reach Play Store.")
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		err := fmt.Errorf("Play Store purchase is invalid, status %d.", response.StatusCode)
+		if playStoreInvalidPurchaseStatuses[response.StatusCode] {
+			err = invalidReceiptError(err.Error())
+		}
+
+		return err
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		logrus.Error("failed to decode play store response: ", err)
+
+		return fmt.Errorf("Failed to verify Play Store purchase.")
+	}
+
+	return nil
+}
+
+// RenewExpiredPurchases verifies expired subscriptions again, renewed ones get
+// the new expiry and the ones the store rejects or didn't renew are expired.
+// Purchases that couldn't be verified are left as they are and retried on the
+// next run.
+func RenewExpiredPurchases(purchaseModel *models.PurchaseModel) {
+	purchases, err := purchaseModel.GetExpiredPurchases()
+	if err != nil {
+		return
+	}
+
+	userIDs := make(map[string]bool)
+	for _, purchase := range purchases {
+		verifier, err := GetReceiptVerifier(purchase.Platform)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"purchase_id": purchase.ID,
+				"platform":    purchase.Platform,
+			}).Error("failed to get receipt verifier for renewal: ", err)
+
+			continue
+		}
+
+		verification, err := verifier.VerifyReceipt(purchase.Receipt, purchase.ProductID)
+		if err != nil && !IsInvalidReceipt(err) {
+			logrus.WithFields(logrus.Fields{
+				"purchase_id": purchase.ID,
+				"platform":    purchase.Platform,
+			}).Error("failed to verify purchase for renewal: ", err)
+
+			continue
+		}
+
+		userIDs[purchase.UserID] = true
+
+		if err == nil && verification.ExpiresAt != nil && verification.ExpiresAt.After(time.Now().UTC()) {
+			purchaseModel.UpsertPurchase(purchase.UserID, purchase.Platform, purchase.Receipt, verification)
+			continue
+		}
+
+		purchaseModel.SetPurchaseExpired(purchase)
+	}
+
+	for uid := range userIDs {
+		purchaseModel.UpdateUserMembershipByPurchases(uid)
+	}
+}
+
+func parseMillis(millis string) time.Time {
+	value, _ := strconv.ParseInt(millis, 10, 64)
+
+	return time.UnixMilli(value).UTC()
+}
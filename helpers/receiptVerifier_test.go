@@ -0,0 +1,196 @@
+package helpers
+
+import (
+	"asset_backend/models"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type fakeReceiptVerifier struct {
+	verification models.ReceiptVerification
+	err          error
+}
+
+func (verifier *fakeReceiptVerifier) VerifyReceipt(receipt, productID string) (models.ReceiptVerification, error) {
+	return verifier.verification, verifier.err
+}
+
+func newTestAppStoreVerifier() *AppStoreVerifier {
+	return &AppStoreVerifier{
+		BundleID:           "com.kantan.app",
+		ProductIDs:         []string{"premium_monthly"},
+		LifetimeProductIDs: []string{"premium_lifetime"},
+	}
+}
+
+func newTestAppStoreResponse(transactions ...appStoreTransaction) appStoreResponse {
+	var response appStoreResponse
+	response.Receipt.BundleID = "com.kantan.app"
+	response.LatestReceiptInfo = transactions
+
+	return response
+}
+
+func millis(t time.Time) string {
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
+
+func TestAppStoreVerifierValidSubscription(t *testing.T) {
+	now := time.Now().UTC()
+	response := newTestAppStoreResponse(
+		appStoreTransaction{
+			ProductID:             "premium_monthly",
+			OriginalTransactionID: "1000",
+			PurchaseDateMs:        millis(now.AddDate(0, -2, 0)),
+			ExpiresDateMs:         millis(now.AddDate(0, -1, 0)),
+		},
+		appStoreTransaction{
+			ProductID:             "premium_monthly",
+			OriginalTransactionID: "1000",
+			PurchaseDateMs:        millis(now.AddDate(0, -1, 0)),
+			ExpiresDateMs:         millis(now.AddDate(0, 1, 0)),
+		},
+	)
+
+	verification, err := newTestAppStoreVerifier().getVerification(response, "premium_monthly", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if verification.IsLifetime || verification.ExpiresAt == nil || !verification.ExpiresAt.After(now) {
+		t.Fatalf("expected the latest active subscription, got %+v", verification)
+	}
+
+	if verification.TransactionID != "1000" {
+		t.Fatalf("expected original transaction id, got %s", verification.TransactionID)
+	}
+}
+
+func TestAppStoreVerifierExpiredSubscription(t *testing.T) {
+	now := time.Now().UTC()
+	response := newTestAppStoreResponse(appStoreTransaction{
+		ProductID:             "premium_monthly",
+		OriginalTransactionID: "1000",
+		PurchaseDateMs:        millis(now.AddDate(0, -2, 0)),
+		ExpiresDateMs:         millis(now.AddDate(0, -1, 0)),
+	})
+
+	verification, err := newTestAppStoreVerifier().getVerification(response, "premium_monthly", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if verification.ExpiresAt == nil || verification.ExpiresAt.After(now) {
+		t.Fatalf("expected an expired subscription, got %+v", verification)
+	}
+}
+
+func TestAppStoreVerifierLifetimeProduct(t *testing.T) {
+	response := newTestAppStoreResponse(appStoreTransaction{
+		ProductID:             "premium_lifetime",
+		OriginalTransactionID: "2000",
+		PurchaseDateMs:        millis(time.Now().UTC()),
+	})
+
+	verification, err := newTestAppStoreVerifier().getVerification(response, "premium_lifetime", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !verification.IsLifetime || verification.ExpiresAt != nil {
+		t.Fatalf("expected a lifetime purchase, got %+v", verification)
+	}
+}
+
+func TestAppStoreVerifierSubscriptionWithoutExpiry(t *testing.T) {
+	response := newTestAppStoreResponse(appStoreTransaction{
+		ProductID:             "premium_monthly",
+		OriginalTransactionID: "1000",
+		PurchaseDateMs:        millis(time.Now().UTC()),
+	})
+
+	if _, err := newTestAppStoreVerifier().getVerification(response, "premium_monthly", false); err != errProductNotFound {
+		t.Fatalf("expected %v, got %v", errProductNotFound, err)
+	}
+}
+
+func TestAppStoreVerifierWrongBundle(t *testing.T) {
+	response := newTestAppStoreResponse(appStoreTransaction{
+		ProductID:             "premium_lifetime",
+		OriginalTransactionID: "2000",
+	})
+	response.Receipt.BundleID = "com.other.app"
+
+	if _, err := newTestAppStoreVerifier().getVerification(response, "premium_lifetime", true); err != errReceiptBundleID {
+		t.Fatalf("expected %v, got %v", errReceiptBundleID, err)
+	}
+}
+
+func TestVerifiersRejectUnknownProducts(t *testing.T) {
+	verifiers := []ReceiptVerifier{
+		newTestAppStoreVerifier(),
+		&PlayStoreVerifier{
+			ProductIDs:         []string{"premium_monthly"},
+			LifetimeProductIDs: []string{"premium_lifetime"},
+		},
+	}
+
+	for _, verifier := range verifiers {
+		if _, err := verifier.VerifyReceipt("receipt", "premium_free"); err != errUnknownProduct {
+			t.Fatalf("expected %v, got %v", errUnknownProduct, err)
+		}
+	}
+}
+
+func TestGetProductIDsFromEnv(t *testing.T) {
+	t.Setenv("TEST_PRODUCT_IDS", " premium_monthly, ,premium_yearly")
+
+	productIDs := getProductIDsFromEnv("TEST_PRODUCT_IDS")
+	if len(productIDs) != 2 || productIDs[0] != "premium_monthly" || productIDs[1] != "premium_yearly" {
+		t.Fatalf("unexpected product ids: %v", productIDs)
+	}
+
+	if productIDs := getProductIDsFromEnv("TEST_MISSING_PRODUCT_IDS"); len(productIDs) != 0 {
+		t.Fatalf("expected no product ids, got %v", productIDs)
+	}
+}
+
+func TestSetReceiptVerifier(t *testing.T) {
+	expiresAt := time.Now().UTC().AddDate(0, 1, 0)
+	fake := &fakeReceiptVerifier{verification: models.ReceiptVerification{
+		ProductID:     "premium_monthly",
+		TransactionID: "1000",
+		ExpiresAt:     &expiresAt,
+	}}
+
+	SetReceiptVerifier(models.PurchasePlatformAppStore, fake)
+	defer func() {
+		receiptVerifierMutex.Lock()
+		defer receiptVerifierMutex.Unlock()
+
+		delete(receiptVerifiers, models.PurchasePlatformAppStore)
+	}()
+
+	verifier, err := GetReceiptVerifier(models.PurchasePlatformAppStore)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	verification, err := verifier.VerifyReceipt("receipt", "premium_monthly")
+	if err != nil || verification.TransactionID != "1000" {
+		t.Fatalf("expected the fake verification, got %+v, %v", verification, err)
+	}
+}
+
+func TestIsInvalidReceipt(t *testing.T) {
+	if !IsInvalidReceipt(errProductNotFound) || !IsInvalidReceipt(fmt.Errorf("wrapped: %w", errReceiptBundleID)) {
+		t.Fatal("expected store rejections to be invalid receipts")
+	}
+
+	if IsInvalidReceipt(errUnknownProduct) || IsInvalidReceipt(errors.New("connection refused")) {
+		t.Fatal("expected transport and config errors to be retried")
+	}
+}
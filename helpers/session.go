@@ -0,0 +1,153 @@
+package helpers
+
+import (
+	"asset_backend/db"
+	"asset_backend/models"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	AccessTokenTimeout  = 15 * time.Minute
+	RefreshTokenTimeout = 30 * 24 * time.Hour
+
+	sessionKey             = "sid"
+	refreshTokenContextKey = "refresh_token"
+	sessionDenylistPrefix  = "session-denylist/"
+)
+
+// SessionIdentity is the payload of access tokens, session ID is used to
+// reject the tokens of revoked sessions.
+type SessionIdentity struct {
+	User      models.User
+	SessionID string
+}
+
+// CreateSession starts a session for the device, devices without an ID get a
+// new session on every login.
+func CreateSession(c *gin.Context, mongoDB *db.MongoDB, user models.User, deviceID, deviceName string) (SessionIdentity, string, error) {
+	if deviceID == "" {
+		deviceID = uuid.NewString()
+	}
+
+	refreshToken, err := GenerateRefreshToken()
+	if err != nil {
+		return SessionIdentity{}, "", err
+	}
+
+	sessionModel := models.NewSessionModel(mongoDB)
+
+	session, err := sessionModel.CreateSession(models.Session{
+		UserID:           user.ID.Hex(),
+		DeviceID:         deviceID,
+		DeviceName:       deviceName,
+		UserAgent:        c.Request.UserAgent(),
+		IPAddress:        c.ClientIP(),
+		RefreshTokenHash: HashRefreshToken(refreshToken),
+		ExpiresAt:        time.Now().UTC().Add(RefreshTokenTimeout),
+	})
+	if err != nil {
+		return SessionIdentity{}, "", err
+	}
+
+	return SessionIdentity{
+		User:      user,
+		SessionID: session.ID.Hex(),
+	}, refreshToken, nil
+}
+
+// GenerateSessionTokens creates a session and its access token for logins
+// that don't go through the JWT login handler.
+func GenerateSessionTokens(
+	c *gin.Context, authMiddleware *jwt.GinJWTMiddleware, mongoDB *db.MongoDB,
+	user models.User, deviceID, deviceName string,
+) (string, string, error) {
+	identity, refreshToken, err := CreateSession(c, mongoDB, user, deviceID, deviceName)
+	if err != nil {
+		return "", "", err
+	}
+
+	accessToken, _, err := authMiddleware.TokenGenerator(identity)
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+func GenerateRefreshToken() (string, error) {
+	const tokenLength = 32
+
+	token := make([]byte, tokenLength)
+	if _, err := rand.Read(token); err != nil {
+		logrus.Error("failed to generate refresh token: ", err)
+
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func HashRefreshToken(refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// GetSessionID returns the session of the request's access token.
+func GetSessionID(claims jwt.MapClaims) string {
+	sessionID, _ := claims[sessionKey].(string)
+
+	return sessionID
+}
+
+// RevokeSessions denylists the sessions until their last access token
+// expires, sessions should be deleted before so they can't be refreshed.
+func RevokeSessions(sessionIDs ...string) {
+	for _, sessionID := range sessionIDs {
+		if err := db.RedisDB.Set(context.TODO(), sessionDenylistPrefix+sessionID, true, AccessTokenTimeout).Err(); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"session_id": sessionID,
+			}).Error("failed to revoke session: ", err)
+		}
+	}
+}
+
+// RevokeUserSessions deletes and revokes all sessions of the user except the
+// given one.
+func RevokeUserSessions(mongoDB *db.MongoDB, uid, exceptSessionID string) error {
+	sessionModel := models.NewSessionModel(mongoDB)
+
+	sessionIDs, err := sessionModel.DeleteSessionsByUserID(uid, exceptSessionID)
+	if err != nil {
+		return err
+	}
+
+	RevokeSessions(sessionIDs...)
+
+	return nil
+}
+
+// isSessionRevoked lets the request through when Redis is unavailable, access
+// tokens are short-lived.
+func isSessionRevoked(sessionID string) bool {
+	count, err := db.RedisDB.Exists(context.TODO(), sessionDenylistPrefix+sessionID).Result()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"session_id": sessionID,
+		}).Error("failed to check session denylist: ", err)
+
+		return false
+	}
+
+	return count > 0
+}
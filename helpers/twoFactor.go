@@ -0,0 +1,207 @@
+package helpers
+
+import (
+	"asset_backend/db"
+	"asset_backend/models"
+	"asset_backend/utils"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// TwoFactorCodeHeader carries the TOTP or recovery code of sensitive requests.
+const TwoFactorCodeHeader = "X-TOTP-Code"
+
+const (
+	TwoFactorIssuer = "Kantan"
+
+	twoFactorMaxAttempts      = 5
+	twoFactorLockTime         = 15 * time.Minute
+	twoFactorChallengeTimeout = 5 * time.Minute
+	twoFactorStepTimeout      = 2 * time.Minute
+	recoveryCodeCount         = 10
+
+	twoFactorChallengeContextKey = "two_factor_challenge"
+	twoFactorAttemptPrefix       = "2fa-attempts/"
+	twoFactorChallengePrefix     = "2fa-challenge/"
+	twoFactorStepPrefix          = "2fa-step/"
+)
+
+var (
+	ErrTwoFactorRequired    = errors.New("Two factor authentication is required")
+	ErrTwoFactorCode        = errors.New("Invalid two factor code")
+	ErrTwoFactorAttempts    = errors.New("Too many two factor attempts, please try again later")
+	ErrTwoFactorChallenge   = errors.New("Two factor challenge is expired, please login again")
+	errTwoFactorCodeMissing = "Two factor code is required in the " + TwoFactorCodeHeader + " header."
+)
+
+// TwoFactorChallenge is the pending login of a user with two factor, the
+// session is created for the device once the code is verified.
+type TwoFactorChallenge struct {
+	UserID     string `json:"user_id"`
+	DeviceID   string `json:"device_id"`
+	DeviceName string `json:"device_name"`
+}
+
+func CreateTwoFactorChallenge(uid, deviceID, deviceName string) (string, error) {
+	token, err := GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	challenge, _ := json.Marshal(TwoFactorChallenge{
+		UserID:     uid,
+		DeviceID:   deviceID,
+		DeviceName: deviceName,
+	})
+
+	if err := db.RedisDB.Set(context.TODO(), twoFactorChallengePrefix+HashRefreshToken(token), challenge, twoFactorChallengeTimeout).Err(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to create two factor challenge: ", err)
+
+		return "", fmt.Errorf("Failed to create two factor challenge.")
+	}
+
+	return token, nil
+}
+
+func GetTwoFactorChallenge(token string) (TwoFactorChallenge, error) {
+	result, err := db.RedisDB.Get(context.TODO(), twoFactorChallengePrefix+HashRefreshToken(token)).Result()
+	if err != nil {
+		return TwoFactorChallenge{}, ErrTwoFactorChallenge
+	}
+
+	var challenge TwoFactorChallenge
+	if err := json.Unmarshal([]byte(result), &challenge); err != nil {
+		return TwoFactorChallenge{}, ErrTwoFactorChallenge
+	}
+
+	return challenge, nil
+}
+
+func DeleteTwoFactorChallenge(token string) {
+	db.RedisDB.Del(context.TODO(), twoFactorChallengePrefix+HashRefreshToken(token))
+}
+
+/**
+* VerifyTwoFactorCode accepts a TOTP code of user's secret or one of the
+* recovery codes. TOTP codes can't be used twice and failed attempts lock two
+* factor verification of the user for a while.
+**/
+func VerifyTwoFactorCode(mongoDB *db.MongoDB, user models.User, code string) error {
+	uid := user.ID.Hex()
+	attemptKey := twoFactorAttemptPrefix + uid
+
+	if attempts, _ := db.RedisDB.Get(context.TODO(), attemptKey).Int(); attempts >= twoFactorMaxAttempts {
+		return ErrTwoFactorAttempts
+	}
+
+	code = strings.TrimSpace(code)
+
+	if step, ok := utils.ValidateTOTP(utils.Decrypt(user.TwoFactorSecret), code, time.Now()); ok {
+		isFirstUse, err := db.RedisDB.SetNX(context.TODO(), fmt.Sprintf("%s%s/%d", twoFactorStepPrefix, uid, step), true, twoFactorStepTimeout).Result()
+		if err != nil || isFirstUse {
+			db.RedisDB.Del(context.TODO(), attemptKey)
+			return nil
+		}
+	} else if len(user.RecoveryCodes) > 0 {
+		userModel := models.NewUserModel(mongoDB)
+		if userModel.UseRecoveryCode(uid, HashRecoveryCode(code)) {
+			db.RedisDB.Del(context.TODO(), attemptKey)
+			return nil
+		}
+	}
+
+	if attempts, err := db.RedisDB.Incr(context.TODO(), attemptKey).Result(); err == nil && attempts == 1 {
+		db.RedisDB.Expire(context.TODO(), attemptKey, twoFactorLockTime)
+	}
+
+	return ErrTwoFactorCode
+}
+
+// RequireTwoFactor verifies the code header of users with two factor enabled,
+// it responds and returns false when the request can't continue.
+func RequireTwoFactor(c *gin.Context, mongoDB *db.MongoDB) bool {
+	uid := jwt.ExtractClaims(c)["id"].(string)
+	userModel := models.NewUserModel(mongoDB)
+
+	user, err := userModel.FindUserByID(uid)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+
+	if !user.TwoFactorEnabled {
+		return true
+	}
+
+	code := c.GetHeader(TwoFactorCodeHeader)
+	if code == "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": errTwoFactorCodeMissing})
+		return false
+	}
+
+	if err := VerifyTwoFactorCode(mongoDB, user, code); err != nil {
+		c.AbortWithStatusJSON(GetTwoFactorErrorStatus(err), gin.H{"error": err.Error()})
+		return false
+	}
+
+	return true
+}
+
+// TwoFactorMiddleware protects sensitive routes of users with two factor
+// enabled, it has to come after the JWT middleware.
+func TwoFactorMiddleware(mongoDB *db.MongoDB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if RequireTwoFactor(c, mongoDB) {
+			c.Next()
+		}
+	}
+}
+
+func GetTwoFactorErrorStatus(err error) int {
+	if err == ErrTwoFactorAttempts {
+		return http.StatusTooManyRequests
+	}
+
+	return http.StatusForbidden
+}
+
+// GenerateRecoveryCodes returns the codes to show once and their hashes to
+// save.
+func GenerateRecoveryCodes() ([]string, []string, error) {
+	const codeLength = 5
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		code := make([]byte, codeLength)
+		if _, err := rand.Read(code); err != nil {
+			logrus.Error("failed to generate recovery code: ", err)
+
+			return nil, nil, fmt.Errorf("Failed to generate recovery codes.")
+		}
+
+		encodedCode := hex.EncodeToString(code)
+		codes[i] = encodedCode[:codeLength] + "-" + encodedCode[codeLength:]
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+func HashRecoveryCode(code string) string {
+	return HashRefreshToken(strings.ToLower(strings.ReplaceAll(code, "-", "")))
+}
@@ -0,0 +1,284 @@
+package helpers
+
+import (
+	"archive/zip"
+	"asset_backend/models"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const UserDataArchiveVersion = 1
+
+// Limits of uncompressed archive content, so a small zip can't exhaust memory
+// while it's decoded.
+const (
+	userDataArchiveMaxEntries   = 100
+	userDataArchiveMaxEntrySize = 32 << 20
+	userDataArchiveMaxSize      = 128 << 20
+)
+
+var errUserDataArchiveTooLarge = fmt.Errorf("Archive is too large.")
+
+type UserDataArchiveManifest struct {
+	Version          int       `json:"version"`
+	UserID           string    `json:"user_id"`
+	ExportedAt       time.Time `json:"exported_at"`
+	IncludePasswords bool      `json:"include_passwords"`
+	Collections      []string  `json:"collections"`
+}
+
+// WriteUserDataArchive writes manifest.json plus a json/<collection>.json and
+// csv/<collection>.csv entry for every collection. JSON files use relaxed
+// extended JSON so they can be restored without losing types.
+func WriteUserDataArchive(writer io.Writer, uid string, includePasswords bool, collections []models.UserDataCollection) error {
+	zipWriter := zip.NewWriter(writer)
+
+	manifest := UserDataArchiveManifest{
+		Version:          UserDataArchiveVersion,
+		UserID:           uid,
+		ExportedAt:       time.Now().UTC(),
+		IncludePasswords: includePasswords,
+		Collections:      make([]string, len(collections)),
+	}
+
+	for i, collection := range collections {
+		manifest.Collections[i] = collection.Name
+
+		if err := writeArchiveJSON(zipWriter, collection); err != nil {
+			return err
+		}
+
+		if err := writeArchiveCSV(zipWriter, collection); err != nil {
+			return err
+		}
+	}
+
+	manifestWriter, err := zipWriter.Create("manifest.json")
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+
+	encoder := json.NewEncoder(manifestWriter)
+	encoder.SetIndent("", "  ")
+
+	if err = encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return zipWriter.Close()
+}
+
+func writeArchiveJSON(zipWriter *zip.Writer, collection models.UserDataCollection) error {
+	fileWriter, err := zipWriter.Create("json/" + collection.Name + ".json")
+	if err != nil {
+		return fmt.Errorf("failed to create %s json: %w", collection.Name, err)
+	}
+
+	if _, err = io.WriteString(fileWriter, "["); err != nil {
+		return fmt.Errorf("failed to write %s json: %w", collection.Name, err)
+	}
+
+	for i, document := range collection.Documents {
+		extJSON, err := bson.MarshalExtJSON(document, false, false)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s json: %w", collection.Name, err)
+		}
+
+		separator := "\n"
+		if i > 0 {
+			separator = ",\n"
+		}
+
+		if _, err = io.WriteString(fileWriter, separator+string(extJSON)); err != nil {
+			return fmt.Errorf("failed to write %s json: %w", collection.Name, err)
+		}
+	}
+
+	if _, err = io.WriteString(fileWriter, "\n]\n"); err != nil {
+		return fmt.Errorf("failed to write %s json: %w", collection.Name, err)
+	}
+
+	return nil
+}
+
+func writeArchiveCSV(zipWriter *zip.Writer, collection models.UserDataCollection) error {
+	fileWriter, err := zipWriter.Create("csv/" + collection.Name + ".csv")
+	if err != nil {
+		return fmt.Errorf("failed to create %s csv: %w", collection.Name, err)
+	}
+
+	columnSet := make(map[string]bool)
+	for _, document := range collection.Documents {
+		for column := range document {
+			columnSet[column] = true
+		}
+	}
+
+	columns := make([]string, 0, len(columnSet))
+	for column := range columnSet {
+		columns = append(columns, column)
+	}
+
+	sort.Slice(columns, func(i, j int) bool {
+		if columns[i] == "_id" || columns[j] == "_id" {
+			return columns[i] == "_id"
+		}
+
+		return columns[i] < columns[j]
+	})
+
+	csvWriter := csv.NewWriter(fileWriter)
+	if err = csvWriter.Write(columns); err != nil {
+		return fmt.Errorf("failed to write %s csv: %w", collection.Name, err)
+	}
+
+	for _, document := range collection.Documents {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = formatArchiveCSVValue(document[column])
+		}
+
+		if err = csvWriter.Write(record); err != nil {
+			return fmt.Errorf("failed to write %s csv: %w", collection.Name, err)
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+func formatArchiveCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int32, int64:
+		return fmt.Sprintf("%d", v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case primitive.ObjectID:
+		return v.Hex()
+	case primitive.DateTime:
+		return v.Time().UTC().Format(time.RFC3339)
+	default:
+		encodedValue, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+
+		return string(encodedValue)
+	}
+}
+
+// ReadUserDataArchive reads an archive created by WriteUserDataArchive and
+// returns the documents of every JSON collection file by collection name.
+func ReadUserDataArchive(reader io.ReaderAt, size int64) (UserDataArchiveManifest, map[string][]bson.M, error) {
+	var manifest UserDataArchiveManifest
+
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return manifest, nil, fmt.Errorf("Invalid archive file.")
+	}
+
+	if len(zipReader.File) > userDataArchiveMaxEntries {
+		return manifest, nil, fmt.Errorf("Archive contains too many files.")
+	}
+
+	remainingSize := int64(userDataArchiveMaxSize)
+
+	files := make(map[string]*zip.File, len(zipReader.File))
+	for _, file := range zipReader.File {
+		files[file.Name] = file
+	}
+
+	manifestFile, ok := files["manifest.json"]
+	if !ok {
+		return manifest, nil, fmt.Errorf("Archive doesn't contain manifest.json.")
+	}
+
+	if err = readArchiveFile(manifestFile, &remainingSize, func(fileReader io.Reader) error {
+		return json.NewDecoder(fileReader).Decode(&manifest)
+	}); err != nil {
+		return manifest, nil, fmt.Errorf("Failed to read manifest: %w", err)
+	}
+
+	if manifest.Version != UserDataArchiveVersion {
+		return manifest, nil, fmt.Errorf("Unsupported archive version %d.", manifest.Version)
+	}
+
+	collections := make(map[string][]bson.M, len(manifest.Collections))
+
+	for _, name := range manifest.Collections {
+		file, ok := files["json/"+name+".json"]
+		if !ok {
+			continue
+		}
+
+		var documents []bson.M
+		if err = readArchiveFile(file, &remainingSize, func(fileReader io.Reader) error {
+			var rawDocuments []json.RawMessage
+			if err := json.NewDecoder(fileReader).Decode(&rawDocuments); err != nil {
+				return err
+			}
+
+			documents = make([]bson.M, len(rawDocuments))
+			for i, rawDocument := range rawDocuments {
+				if err := bson.UnmarshalExtJSON(rawDocument, false, &documents[i]); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return manifest, nil, fmt.Errorf("Failed to read %s: %w", name, err)
+		}
+
+		collections[name] = documents
+	}
+
+	return manifest, collections, nil
+}
+
+// readArchiveFile reads at most userDataArchiveMaxEntrySize bytes of the entry
+// and subtracts them from the remaining size of the archive. Sizes in the zip
+// headers can't be trusted, so the read bytes are counted too.
+func readArchiveFile(file *zip.File, remainingSize *int64, read func(io.Reader) error) error {
+	limit := int64(userDataArchiveMaxEntrySize)
+	if *remainingSize < limit {
+		limit = *remainingSize
+	}
+
+	if file.UncompressedSize64 > uint64(limit) {
+		return errUserDataArchiveTooLarge
+	}
+
+	fileReader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer fileReader.Close()
+
+	limitedReader := &io.LimitedReader{R: fileReader, N: limit + 1}
+	err = read(limitedReader)
+
+	readSize := limit + 1 - limitedReader.N
+	if readSize > limit {
+		return errUserDataArchiveTooLarge
+	}
+
+	*remainingSize -= readSize
+
+	return err
+}
@@ -87,12 +87,18 @@ func main() {
 
 	routes.SetupRoutes(router, jwtHandler, mongoDB)
 
+	go migrationTask(mongoDB)
+
 	var dailyScheduler *gocron.Scheduler
 	dailyScheduler = helpers.CreateDailySchedule(func() {
 		dailyTask(mongoDB)
 		scheduleLogger(dailyScheduler, "Daily")
 	}, "05:00")
 
+	helpers.CreateHourlySchedule(func() {
+		hourlyTask(mongoDB)
+	}, 1)
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -102,9 +108,50 @@ func main() {
 	router.Run(":" + port)
 }
 
+// migrationTask backfills data of users created before a feature and creates
+// indexes, every step is idempotent so it runs on every start.
+func migrationTask(mongoDB *db.MongoDB) {
+	userModel := models.NewUserModel(mongoDB)
+	userModel.VerifyExistingUsers()
+
+	categoryModel := models.NewCategoryModel(mongoDB)
+	categoryModel.CreateMissingDefaultCategories()
+
+	purchaseModel := models.NewPurchaseModel(mongoDB)
+	purchaseModel.CreatePurchaseIndexes()
+	purchaseModel.SetLegacyMembershipExpiry()
+}
+
+func hourlyTask(mongoDB *db.MongoDB) {
+	priceAlertModel := models.NewPriceAlertModel(mongoDB)
+	helpers.SendPriceAlerts(priceAlertModel.EvaluatePriceAlerts())
+
+	purchaseModel := models.NewPurchaseModel(mongoDB)
+	helpers.RenewExpiredPurchases(purchaseModel)
+	purchaseModel.ExpireLegacyMemberships()
+
+	sessionModel := models.NewSessionModel(mongoDB)
+	sessionModel.DeleteExpiredSessions()
+}
+
 func dailyTask(mongoDB *db.MongoDB) {
+	exchangeHistoryModel := models.NewExchangeHistoryModel(mongoDB)
+	go exchangeHistoryModel.CreateDailyExchangeSnapshot()
+
 	dasModel := models.NewDailyAssetStatsModel(mongoDB)
-	go dasModel.CalculateDailyAssetStats()
+	netWorthModel := models.NewNetWorthModel(mongoDB)
+	go func() {
+		dasModel.CalculateDailyAssetStats()
+		netWorthModel.CalculateDailyNetWorth()
+	}()
+
+	transactionModel := models.NewTransactionModel(mongoDB)
+	go transactionModel.MaterializeRecurringTransactions()
+
+	budgetModel := models.NewBudgetModel(mongoDB)
+	go func() {
+		helpers.SendBudgetAlerts(budgetModel.GetBudgetAlerts(nil))
+	}()
 
 	userModel := models.NewUserModel(mongoDB)
 	notificationSubs := userModel.GetSubscriptionNotifications()
@@ -0,0 +1,249 @@
+package models
+
+import (
+	"asset_backend/db"
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AllocationTargetModel struct {
+	Collection         *mongo.Collection
+	ExchangeCollection *mongo.Collection
+}
+
+func NewAllocationTargetModel(mongoDB *db.MongoDB) *AllocationTargetModel {
+	return &AllocationTargetModel{
+		Collection:         mongoDB.Database.Collection("allocation-targets"),
+		ExchangeCollection: mongoDB.Database.Collection("exchanges"),
+	}
+}
+
+/**
+* Every user has a single allocation document. Targets are either asset types
+* or to_asset symbols, depending on target_type, and add up to 100. Holdings
+* without a target are targeted at 0%.
+*
+* Drift tolerance is in percentage points, min trade size is in user's currency.
+**/
+type AllocationTarget struct {
+	ID             primitive.ObjectID     `bson:"_id,omitempty" json:"_id"`
+	UserID         string                 `bson:"user_id" json:"user_id"`
+	TargetType     string                 `bson:"target_type" json:"target_type"`
+	Targets        []AllocationTargetItem `bson:"targets" json:"targets"`
+	DriftTolerance float64                `bson:"drift_tolerance" json:"drift_tolerance"`
+	MinTradeSize   float64                `bson:"min_trade_size" json:"min_trade_size"`
+	UpdatedAt      time.Time              `bson:"updated_at" json:"-"`
+}
+
+type AllocationTargetItem struct {
+	Key        string  `bson:"key" json:"key"`
+	Percentage float64 `bson:"percentage" json:"percentage"`
+}
+
+const (
+	AllocationTargetAssetType = "asset_type"
+	AllocationTargetAsset     = "asset"
+
+	defaultAllocationDriftTolerance = 5
+)
+
+func (allocationModel *AllocationTargetModel) GetAllocationTargetByUserID(uid string) (AllocationTarget, error) {
+	result := allocationModel.Collection.FindOne(context.TODO(), bson.M{"user_id": uid})
+
+	var allocationTarget AllocationTarget
+	if err := result.Decode(&allocationTarget); err != nil {
+		if err != mongo.ErrNoDocuments {
+			logrus.WithFields(logrus.Fields{
+				"uid": uid,
+			}).Error("failed to find allocation target by user id: ", err)
+		}
+
+		return AllocationTarget{}, fmt.Errorf("Failed to find allocation targets.")
+	}
+
+	return allocationTarget, nil
+}
+
+func (allocationModel *AllocationTargetModel) UpdateAllocationTarget(uid string, data requests.AllocationTargetUpdate) (AllocationTarget, error) {
+	allocationTarget := AllocationTarget{
+		UserID:         uid,
+		TargetType:     data.TargetType,
+		Targets:        make([]AllocationTargetItem, len(data.Targets)),
+		DriftTolerance: defaultAllocationDriftTolerance,
+		UpdatedAt:      time.Now().UTC(),
+	}
+
+	if existing, err := allocationModel.GetAllocationTargetByUserID(uid); err == nil {
+		allocationTarget.ID = existing.ID
+		allocationTarget.DriftTolerance = existing.DriftTolerance
+		allocationTarget.MinTradeSize = existing.MinTradeSize
+	}
+
+	for i, target := range data.Targets {
+		allocationTarget.Targets[i] = AllocationTargetItem{
+			Key:        target.Key,
+			Percentage: target.Percentage,
+		}
+	}
+
+	if data.DriftTolerance != nil {
+		allocationTarget.DriftTolerance = *data.DriftTolerance
+	}
+
+	if data.MinTradeSize != nil {
+		allocationTarget.MinTradeSize = *data.MinTradeSize
+	}
+
+	result, err := allocationModel.Collection.UpdateOne(context.TODO(), bson.M{
+		"user_id": uid,
+	}, bson.M{"$set": bson.M{
+		"target_type":     allocationTarget.TargetType,
+		"targets":         allocationTarget.Targets,
+		"drift_tolerance": allocationTarget.DriftTolerance,
+		"min_trade_size":  allocationTarget.MinTradeSize,
+		"updated_at":      allocationTarget.UpdatedAt,
+	}}, options.Update().SetUpsert(true))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":  uid,
+			"data": data,
+		}).Error("failed to update allocation target: ", err)
+
+		return AllocationTarget{}, fmt.Errorf("Failed to update allocation targets.")
+	}
+
+	if result.UpsertedID != nil {
+		allocationTarget.ID = result.UpsertedID.(primitive.ObjectID)
+	}
+
+	return allocationTarget, nil
+}
+
+// GetAssetRebalance compares current values of assets, converted to currency,
+// with the targets and returns the trades needed to reach them. Positive trade
+// values are buys, negative ones are sells.
+func (allocationModel *AllocationTargetModel) GetAssetRebalance(
+	allocationTarget AllocationTarget, assets []responses.Asset, currency string,
+) (responses.AssetRebalance, error) {
+	rebalance := responses.AssetRebalance{
+		Currency:       currency,
+		TargetType:     allocationTarget.TargetType,
+		DriftTolerance: allocationTarget.DriftTolerance,
+		MinTradeSize:   allocationTarget.MinTradeSize,
+		IsBalanced:     true,
+		Items:          []responses.AssetRebalanceItem{},
+	}
+
+	var (
+		keys          []string
+		currentValues = make(map[string]float64)
+		amounts       = make(map[string]float64)
+		exchangeRates = make(map[string]float64)
+	)
+
+	for _, asset := range assets {
+		if asset.RemainingAmount <= 0 {
+			continue
+		}
+
+		exchangeRate, ok := exchangeRates[asset.FromAsset]
+		if !ok {
+			var err error
+			if exchangeRate, err = getExchangeRate(allocationModel.ExchangeCollection, asset.FromAsset, currency); err != nil {
+				return responses.AssetRebalance{}, err
+			}
+
+			exchangeRates[asset.FromAsset] = exchangeRate
+		}
+
+		key := asset.ToAsset
+		if allocationTarget.TargetType == AllocationTargetAssetType {
+			key = asset.AssetType
+		}
+
+		if _, ok := currentValues[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		currentValues[key] += asset.CurrentTotal * exchangeRate
+		amounts[key] += asset.RemainingAmount
+		rebalance.TotalValue += asset.CurrentTotal * exchangeRate
+	}
+
+	targetPercentages := make(map[string]float64)
+	for _, target := range allocationTarget.Targets {
+		if _, ok := currentValues[target.Key]; !ok {
+			if _, ok := targetPercentages[target.Key]; !ok {
+				keys = append(keys, target.Key)
+			}
+		}
+
+		targetPercentages[target.Key] += target.Percentage
+	}
+
+	for _, key := range keys {
+		item := responses.AssetRebalanceItem{
+			Key:              key,
+			CurrentValue:     currentValues[key],
+			TargetPercentage: targetPercentages[key],
+			Action:           "hold",
+		}
+
+		if rebalance.TotalValue > 0 {
+			item.CurrentPercentage = item.CurrentValue / rebalance.TotalValue * 100
+		}
+
+		item.Drift = item.CurrentPercentage - item.TargetPercentage
+
+		tradeValue := rebalance.TotalValue*item.TargetPercentage/100 - item.CurrentValue
+		if math.Abs(item.Drift) > allocationTarget.DriftTolerance && math.Abs(tradeValue) >= allocationTarget.MinTradeSize {
+			item.TradeValue = tradeValue
+
+			// Amounts can only be calculated for single assets that are already held.
+			if allocationTarget.TargetType == AllocationTargetAsset && amounts[key] > 0 {
+				item.TradeAmount = tradeValue / (item.CurrentValue / amounts[key])
+			}
+
+			if tradeValue > 0 {
+				item.Action = "buy"
+			} else {
+				item.Action = "sell"
+			}
+
+			rebalance.IsBalanced = false
+		}
+
+		rebalance.Items = append(rebalance.Items, item)
+	}
+
+	sort.SliceStable(rebalance.Items, func(i, j int) bool {
+		return math.Abs(rebalance.Items[i].Drift) > math.Abs(rebalance.Items[j].Drift)
+	})
+
+	return rebalance, nil
+}
+
+func (allocationModel *AllocationTargetModel) DeleteAllocationTargetByUserID(uid string) error {
+	if _, err := allocationModel.Collection.DeleteMany(context.TODO(), bson.M{
+		"user_id": uid,
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to delete allocation target by user id: ", err)
+
+		return fmt.Errorf("Failed to delete allocation targets.")
+	}
+
+	return nil
+}
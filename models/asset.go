@@ -6,6 +6,7 @@ import (
 	"asset_backend/responses"
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,15 +15,26 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AssetModel struct {
-	Collection *mongo.Collection
+	Collection                     *mongo.Collection
+	ExchangeCollection             *mongo.Collection
+	ExchangeHistoryCollection      *mongo.Collection
+	DailyAssetStatsCollection      *mongo.Collection
+	CustomAssetValuationCollection *mongo.Collection
+	CorporateActionCollection      *mongo.Collection
 }
 
 func NewAssetModel(mongoDB *db.MongoDB) *AssetModel {
 	return &AssetModel{
-		Collection: mongoDB.Database.Collection("assets"),
+		Collection:                     mongoDB.Database.Collection("assets"),
+		ExchangeCollection:             mongoDB.Database.Collection("exchanges"),
+		ExchangeHistoryCollection:      mongoDB.Database.Collection("exchange-history"),
+		DailyAssetStatsCollection:      mongoDB.Database.Collection("daily-asset-stats"),
+		CustomAssetValuationCollection: mongoDB.Database.Collection("custom-asset-valuations"),
+		CorporateActionCollection:      mongoDB.Database.Collection("corporate-actions"),
 	}
 }
 
@@ -37,12 +49,13 @@ type Asset struct {
 	AssetMarket   string             `bson:"asset_market" json:"asset_market"`
 	Type          string             `bson:"type" json:"type"`
 	CurrencyValue float64            `bson:"value" json:"value"`
+	SplitRatio    float64            `bson:"split_ratio,omitempty" json:"split_ratio,omitempty"`
 	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
 }
 
 const (
 	assetLogPaginationLimit = 15
-	assetPremiumLimit       = 10
+	longTermHoldingYears    = 1
 )
 
 func createAssetObject(
@@ -64,8 +77,30 @@ func createAssetObject(
 }
 
 func (assetModel *AssetModel) CreateAsset(uid string, data requests.AssetCreate) error {
+	var splitRatio float64
+	if data.Type == AssetLogSplit {
+		remainingAmount, err := assetModel.getRemainingAmountBefore(uid, data.ToAsset, strings.ToUpper(data.FromAsset), time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		splitRatio = data.Amount
+		data.Amount = remainingAmount * (splitRatio - 1)
+		data.Price = 0
+	}
+
 	currencyValue := data.Price * data.Amount
 
+	if data.AssetType == AssetTypeCustom {
+		data.AssetMarket = customAssetMarket
+
+		if err := createCustomAssetValuation(
+			assetModel.CustomAssetValuationCollection, uid, data.ToAsset, strings.ToUpper(data.FromAsset), data.Price,
+		); err != nil {
+			return err
+		}
+	}
+
 	asset := createAssetObject(
 		uid,
 		data.ToAsset,
@@ -77,6 +112,7 @@ func (assetModel *AssetModel) CreateAsset(uid string, data requests.AssetCreate)
 		data.Amount,
 		currencyValue,
 	)
+	asset.SplitRatio = splitRatio
 
 	if _, err := assetModel.Collection.InsertOne(context.TODO(), asset); err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -89,6 +125,57 @@ func (assetModel *AssetModel) CreateAsset(uid string, data requests.AssetCreate)
 	return nil
 }
 
+func (assetModel *AssetModel) CreateAssets(uid string, rows []requests.AssetImportRow) (int, error) {
+	if err := assetModel.setImportSplitAmounts(uid, rows); err != nil {
+		return 0, err
+	}
+
+	assets := make([]interface{}, len(rows))
+	for i, row := range rows {
+		if row.AssetType == AssetTypeCustom {
+			row.AssetMarket = customAssetMarket
+
+			if err := createCustomAssetValuation(
+				assetModel.CustomAssetValuationCollection, uid, row.ToAsset, strings.ToUpper(row.FromAsset), row.Price,
+			); err != nil {
+				return 0, err
+			}
+		}
+
+		asset := createAssetObject(
+			uid,
+			row.ToAsset,
+			strings.ToUpper(row.FromAsset),
+			row.AssetType,
+			row.AssetMarket,
+			row.Type,
+			row.Price,
+			row.Amount,
+			row.Price*row.Amount,
+		)
+		asset.CreatedAt = row.CreatedAt
+		asset.SplitRatio = row.SplitRatio
+
+		assets[i] = asset
+	}
+
+	result, err := assetModel.Collection.InsertMany(context.TODO(), assets, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":   uid,
+			"count": len(rows),
+		}).Error("failed to create assets: ", err)
+
+		if result != nil {
+			return len(result.InsertedIDs), fmt.Errorf("Failed to create some of the assets.")
+		}
+
+		return 0, fmt.Errorf("Failed to create assets.")
+	}
+
+	return len(result.InsertedIDs), nil
+}
+
 func (assetModel *AssetModel) GetAssetByID(assetID string) (Asset, error) {
 	objectAssetID, _ := primitive.ObjectIDFromHex(assetID)
 
@@ -137,7 +224,7 @@ func (assetModel *AssetModel) GetUserAssetCount(uid string) int64 {
 			"uid": uid,
 		}).Error("failed to aggregate assets while counting: ", err)
 
-		return assetPremiumLimit
+		return countErrorFallback
 	}
 
 	var dcArray []responses.AssetDocumentCount
@@ -146,7 +233,7 @@ func (assetModel *AssetModel) GetUserAssetCount(uid string) int64 {
 			"uid": uid,
 		}).Error("failed to decode assets while counting: ", err)
 
-		return assetPremiumLimit
+		return countErrorFallback
 	}
 
 	if len(dcArray) > 0 && len(dcArray[0].DocumentCount) > 0 {
@@ -156,7 +243,52 @@ func (assetModel *AssetModel) GetUserAssetCount(uid string) int64 {
 	return 0
 }
 
-func (assetModel *AssetModel) GetAssetsByUserID(uid string, data requests.AssetSortFilter) ([]responses.Asset, error) {
+func (assetModel *AssetModel) GetUserAssetPairs(uid string) (map[string]bool, error) {
+	cursor, err := assetModel.Collection.Aggregate(context.TODO(), bson.A{
+		bson.M{"$match": bson.M{
+			"user_id": uid,
+		}},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{
+				"to_asset":   "$to_asset",
+				"from_asset": "$from_asset",
+			},
+		}},
+		bson.M{"$project": bson.M{
+			"to_asset":   "$_id.to_asset",
+			"from_asset": "$_id.from_asset",
+		}},
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to aggregate asset pairs: ", err)
+
+		return nil, fmt.Errorf("Failed to aggregate asset pairs.")
+	}
+
+	var assetPairs []responses.AssetPair
+	if err = cursor.All(context.TODO(), &assetPairs); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode asset pairs: ", err)
+
+		return nil, fmt.Errorf("Failed to decode asset pairs.")
+	}
+
+	pairs := make(map[string]bool, len(assetPairs))
+	for _, assetPair := range assetPairs {
+		pairs[GetAssetPairKey(assetPair.ToAsset, assetPair.FromAsset)] = true
+	}
+
+	return pairs, nil
+}
+
+func GetAssetPairKey(toAsset, fromAsset string) string {
+	return toAsset + "/" + strings.ToUpper(fromAsset)
+}
+
+func (assetModel *AssetModel) GetAssetsByUserID(uid, costBasisMethod string, data requests.AssetSortFilter) ([]responses.Asset, error) {
 	var sort bson.M
 
 	switch data.Sort {
@@ -221,15 +353,9 @@ func (assetModel *AssetModel) GetAssetsByUserID(uid string, data requests.AssetS
 				},
 			},
 		},
-		"remaining_amount": bson.M{
-			"$sum": bson.M{
-				"$cond": bson.A{
-					bson.M{"$eq": bson.A{"$type", "buy"}},
-					"$amount",
-					bson.M{"$multiply": bson.A{"$amount", -1}},
-				},
-			},
-		},
+		"remaining_amount": getRemainingAmountSum(),
+		"total_income":     getAssetLogValueSum(assetLogIncomeTypes),
+		"total_fees":       getAssetLogValueSum(bson.A{AssetLogFee}),
 		"asset_type": bson.M{
 			"$first": "$asset_type",
 		},
@@ -237,28 +363,7 @@ func (assetModel *AssetModel) GetAssetsByUserID(uid string, data requests.AssetS
 			"$first": "$asset_market",
 		},
 	}}
-	lookup := bson.M{"$lookup": bson.M{
-		"from": "investings",
-		"let": bson.M{
-			"to_asset":   "$_id.to_asset",
-			"asset_type": "$asset_type",
-			"market":     "$asset_market",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$and": bson.A{
-							bson.M{"$eq": bson.A{"$_id.symbol", "$$to_asset"}},
-							bson.M{"$eq": bson.A{"$_id.type", "$$asset_type"}},
-							bson.M{"$eq": bson.A{"$_id.market", "$$market"}},
-						},
-					},
-				},
-			},
-		},
-		"as": "investing",
-	}}
+	lookup := getInvestingLookup(uid)
 	unwindInvesting := bson.M{"$unwind": bson.M{
 		"path":                       "$investing",
 		"includeArrayIndex":          "index",
@@ -329,16 +434,21 @@ func (assetModel *AssetModel) GetAssetsByUserID(uid string, data requests.AssetS
 		"asset_market":     true,
 		"total_bought":     true,
 		"total_sold":       true,
+		"total_income":     true,
+		"total_fees":       true,
 		"remaining_amount": true,
 		"current_total_value": bson.M{
 			"$multiply": bson.A{"$remaining_amount", "$investing_price"},
 		},
 		"p/l": bson.M{
 			"$subtract": bson.A{
-				"$total_bought",
+				bson.M{
+					"$sum": bson.A{"$total_bought", "$total_fees"},
+				},
 				bson.M{
 					"$sum": bson.A{
 						"$total_sold",
+						"$total_income",
 						bson.M{
 							"$multiply": bson.A{"$remaining_amount", "$investing_price"},
 						},
@@ -358,8 +468,10 @@ func (assetModel *AssetModel) GetAssetsByUserID(uid string, data requests.AssetS
 		},
 	}}
 
+	corporateActionLookup, addAdjustedFields := getCorporateActionStages()
+
 	cursor, err := assetModel.Collection.Aggregate(context.TODO(), bson.A{
-		match, group, lookup, unwindInvesting, exchangeLookup,
+		match, corporateActionLookup, addAdjustedFields, group, lookup, unwindInvesting, exchangeLookup,
 		unwindExchange, addInvestingField, project, addPercentageField, sort,
 	})
 	if err != nil {
@@ -383,38 +495,43 @@ func (assetModel *AssetModel) GetAssetsByUserID(uid string, data requests.AssetS
 		return nil, fmt.Errorf("Failed to decode assets.")
 	}
 
+	costBasisResults, err := assetModel.getCostBasisByUserID(uid, costBasisMethod, bson.M{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range assets {
+		if result, ok := costBasisResults[costBasisKey{ToAsset: assets[i].ToAsset, FromAsset: assets[i].FromAsset}]; ok {
+			assets[i].CostBasis = result.CostBasis
+			assets[i].RealizedPL = result.RealizedPL
+			assets[i].UnrealizedPL = assets[i].CurrentTotal - result.CostBasis
+		}
+	}
+
 	return assets, nil
 }
 
-func (assetModel *AssetModel) GetAssetStatsByAssetAndUserID(uid, toAsset, fromAsset, market string) (responses.AssetDetails, error) {
+func (assetModel *AssetModel) GetAssetStatsByAssetAndUserID(uid, costBasisMethod, toAsset, fromAsset, market string) (responses.AssetDetails, error) {
+	// Logs of the symbol and its former symbols are matched first, the pair is
+	// matched again after corporate actions rename them.
+	symbols, err := getFormerSymbols(assetModel.CorporateActionCollection, toAsset, market)
+	if err != nil {
+		return responses.AssetDetails{}, err
+	}
+
+	userMatch := bson.M{"$match": bson.M{
+		"user_id":      uid,
+		"to_asset":     bson.M{"$in": symbols},
+		"from_asset":   fromAsset,
+		"asset_market": market,
+	}}
+	corporateActionLookup, addAdjustedFields := getCorporateActionStages()
 	match := bson.M{"$match": bson.M{
 		"to_asset":     toAsset,
 		"from_asset":   fromAsset,
 		"asset_market": market,
-		"user_id":      uid,
-	}}
-	lookup := bson.M{"$lookup": bson.M{
-		"from": "investings",
-		"let": bson.M{
-			"to_asset":   "$_id.to_asset",
-			"asset_type": "$asset_type",
-			"market":     "$asset_market",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$and": bson.A{
-							bson.M{"$eq": bson.A{"$_id.symbol", "$$to_asset"}},
-							bson.M{"$eq": bson.A{"$_id.type", "$$asset_type"}},
-							bson.M{"$eq": bson.A{"$_id.market", "$$market"}},
-						},
-					},
-				},
-			},
-		},
-		"as": "investing",
 	}}
+	lookup := getInvestingLookup(uid)
 	unwindInvesting := bson.M{"$unwind": bson.M{
 		"path":                       "$investing",
 		"includeArrayIndex":          "index",
@@ -483,6 +600,8 @@ func (assetModel *AssetModel) GetAssetStatsByAssetAndUserID(uid, toAsset, fromAs
 		"name":             "$investing.name",
 		"total_bought":     true,
 		"total_sold":       true,
+		"total_income":     true,
+		"total_fees":       true,
 		"remaining_amount": true,
 		"asset_type":       true,
 		"asset_market":     true,
@@ -491,10 +610,13 @@ func (assetModel *AssetModel) GetAssetStatsByAssetAndUserID(uid, toAsset, fromAs
 		},
 		"p/l": bson.M{
 			"$subtract": bson.A{
-				"$total_bought",
+				bson.M{
+					"$sum": bson.A{"$total_bought", "$total_fees"},
+				},
 				bson.M{
 					"$sum": bson.A{
 						"$total_sold",
+						"$total_income",
 						bson.M{
 							"$multiply": bson.A{"$remaining_amount", "$investing_price"},
 						},
@@ -515,8 +637,8 @@ func (assetModel *AssetModel) GetAssetStatsByAssetAndUserID(uid, toAsset, fromAs
 	}}
 
 	cursor, err := assetModel.Collection.Aggregate(context.TODO(), bson.A{
-		match, groupAssetsByToAssetFromAsset(), lookup, unwindInvesting, exchangeLookup,
-		unwindExchange, addInvestingField, project, addPercentageField,
+		userMatch, corporateActionLookup, addAdjustedFields, match, groupAssetsByToAssetFromAsset(),
+		lookup, unwindInvesting, exchangeLookup, unwindExchange, addInvestingField, project, addPercentageField,
 	})
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -539,39 +661,36 @@ func (assetModel *AssetModel) GetAssetStatsByAssetAndUserID(uid, toAsset, fromAs
 		return responses.AssetDetails{}, fmt.Errorf("Failed to decode asset details.")
 	}
 
-	if len(assetDetails) > 0 {
-		return assetDetails[0], nil
+	if len(assetDetails) == 0 {
+		return responses.AssetDetails{}, nil
+	}
+
+	costBasisResults, err := assetModel.getCostBasisByUserID(uid, costBasisMethod, bson.M{
+		"to_asset":     toAsset,
+		"from_asset":   fromAsset,
+		"asset_market": market,
+	}, nil)
+	if err != nil {
+		return responses.AssetDetails{}, err
+	}
+
+	details := assetDetails[0]
+	details.CostBasisMethod = costBasisMethodOrDefault(costBasisMethod)
+
+	if result, ok := costBasisResults[costBasisKey{ToAsset: toAsset, FromAsset: fromAsset}]; ok {
+		details.CostBasis = result.CostBasis
+		details.RealizedPL = result.RealizedPL
+		details.UnrealizedPL = details.CurrentTotal - result.CostBasis
 	}
 
-	return responses.AssetDetails{}, nil
+	return details, nil
 }
 
 func (assetModel *AssetModel) GetAllAssetStats(uid string) (responses.AssetStats, error) {
 	match := bson.M{"$match": bson.M{
 		"user_id": uid,
 	}}
-	lookup := bson.M{"$lookup": bson.M{
-		"from": "investings",
-		"let": bson.M{
-			"to_asset":   "$_id.to_asset",
-			"asset_type": "$asset_type",
-			"market":     "$asset_market",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$and": bson.A{
-							bson.M{"$eq": bson.A{"$_id.symbol", "$$to_asset"}},
-							bson.M{"$eq": bson.A{"$_id.type", "$$asset_type"}},
-							bson.M{"$eq": bson.A{"$_id.market", "$$market"}},
-						},
-					},
-				},
-			},
-		},
-		"as": "investing",
-	}}
+	lookup := getInvestingLookup(uid)
 	unwindInvesting := bson.M{"$unwind": bson.M{
 		"path":                       "$investing",
 		"includeArrayIndex":          "index",
@@ -640,16 +759,21 @@ func (assetModel *AssetModel) GetAllAssetStats(uid string) (responses.AssetStats
 		},
 		"total_bought": true,
 		"total_sold":   true,
+		"total_income": true,
+		"total_fees":   true,
 		"asset_type":   true,
 		"current_total_value": bson.M{
 			"$multiply": bson.A{"$remaining_amount", "$investing_price"},
 		},
 		"p/l": bson.M{
 			"$subtract": bson.A{
-				"$total_bought",
+				bson.M{
+					"$sum": bson.A{"$total_bought", "$total_fees"},
+				},
 				bson.M{
 					"$sum": bson.A{
 						"$total_sold",
+						"$total_income",
 						bson.M{
 							"$multiply": bson.A{"$remaining_amount", "$investing_price"},
 						},
@@ -731,6 +855,22 @@ func (assetModel *AssetModel) GetAllAssetStats(uid string) (responses.AssetStats
 				"$total_sold",
 			},
 		},
+		"total_income": bson.M{
+			"$ifNull": bson.A{
+				bson.M{
+					"$multiply": bson.A{"$total_income", "$user_exchange_rate.exchange_rate"},
+				},
+				"$total_income",
+			},
+		},
+		"total_fees": bson.M{
+			"$ifNull": bson.A{
+				bson.M{
+					"$multiply": bson.A{"$total_fees", "$user_exchange_rate.exchange_rate"},
+				},
+				"$total_fees",
+			},
+		},
 	}}
 	assetGroup := bson.M{"$group": bson.M{
 		"_id": "$asset_type",
@@ -743,6 +883,12 @@ func (assetModel *AssetModel) GetAllAssetStats(uid string) (responses.AssetStats
 		"total_sold": bson.M{
 			"$sum": "$total_sold",
 		},
+		"total_income": bson.M{
+			"$sum": "$total_income",
+		},
+		"total_fees": bson.M{
+			"$sum": "$total_fees",
+		},
 		"total_assets": bson.M{
 			"$sum": "$total_assets",
 		},
@@ -764,6 +910,12 @@ func (assetModel *AssetModel) GetAllAssetStats(uid string) (responses.AssetStats
 		"total_sold": bson.M{
 			"$sum": "$total_sold",
 		},
+		"total_income": bson.M{
+			"$sum": "$total_income",
+		},
+		"total_fees": bson.M{
+			"$sum": "$total_fees",
+		},
 		"stock_assets": bson.M{
 			"$sum": bson.M{
 				"$cond": bson.A{
@@ -800,6 +952,15 @@ func (assetModel *AssetModel) GetAllAssetStats(uid string) (responses.AssetStats
 				},
 			},
 		},
+		"custom_assets": bson.M{
+			"$sum": bson.M{
+				"$cond": bson.A{
+					bson.M{"$eq": bson.A{"$_id", "custom"}},
+					"$total_assets",
+					0,
+				},
+			},
+		},
 		"total_assets": bson.M{
 			"$sum": "$total_assets",
 		},
@@ -839,6 +1000,15 @@ func (assetModel *AssetModel) GetAllAssetStats(uid string) (responses.AssetStats
 				},
 			},
 		},
+		"custom_p/l": bson.M{
+			"$sum": bson.M{
+				"$cond": bson.A{
+					bson.M{"$eq": bson.A{"$_id", "custom"}},
+					"$total_p/l",
+					0,
+				},
+			},
+		},
 		"total_p/l": bson.M{
 			"$sum": "$total_p/l",
 		},
@@ -912,10 +1082,27 @@ func (assetModel *AssetModel) GetAllAssetStats(uid string) (responses.AssetStats
 				100,
 			},
 		},
+		"custom_percentage": bson.M{
+			"$multiply": bson.A{
+				bson.M{
+					"$cond": bson.A{
+						bson.M{"$ne": bson.A{"$total_assets", 0}},
+						bson.M{"$divide": bson.A{
+							"$custom_assets", "$total_assets",
+						}},
+						0,
+					},
+				},
+				100,
+			},
+		},
 	}}
 
+	corporateActionLookup, addAdjustedFields := getCorporateActionStages()
+
 	cursor, err := assetModel.Collection.Aggregate(context.TODO(), bson.A{
-		match, groupAssetsByToAssetFromAsset(), lookup, unwindInvesting, exchangeLookup, unwindExchange,
+		match, corporateActionLookup, addAdjustedFields, groupAssetsByToAssetFromAsset(),
+		lookup, unwindInvesting, exchangeLookup, unwindExchange,
 		addInvestingField, project, userLookup, unwindUser, userCurrencyExchangeLookup,
 		unwindUserCurrency, userCurrencyProject, assetGroup, statsGroup, addPercentageFields,
 	})
@@ -943,6 +1130,92 @@ func (assetModel *AssetModel) GetAllAssetStats(uid string) (responses.AssetStats
 	return responses.AssetStats{}, nil
 }
 
+// GetTaxReportByUserID returns the disposals of the year, cost basis is
+// converted with the rates of the days lots were acquired and proceeds with
+// the rate of the day they were disposed.
+func (assetModel *AssetModel) GetTaxReportByUserID(uid, costBasisMethod, currency string, year int) (responses.AssetTaxReport, error) {
+	exchangeRates := make(map[string]float64)
+	exchangeRate := func(assetLog Asset) (float64, error) {
+		exchangeRateKey := assetLog.FromAsset + assetLog.CreatedAt.UTC().Format("2006-01-02")
+		if exchangeRate, ok := exchangeRates[exchangeRateKey]; ok {
+			return exchangeRate, nil
+		}
+
+		exchangeRate, err := getHistoricalExchangeRate(
+			assetModel.ExchangeHistoryCollection, assetModel.ExchangeCollection, assetLog.FromAsset, currency, assetLog.CreatedAt,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		exchangeRates[exchangeRateKey] = exchangeRate
+
+		return exchangeRate, nil
+	}
+
+	costBasisResults, err := assetModel.getCostBasisByUserID(uid, costBasisMethod, bson.M{
+		"created_at": bson.M{"$lt": time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}, exchangeRate)
+	if err != nil {
+		return responses.AssetTaxReport{}, err
+	}
+
+	taxReport := responses.AssetTaxReport{
+		Year:            year,
+		Currency:        currency,
+		CostBasisMethod: costBasisMethodOrDefault(costBasisMethod),
+		Disposals:       []responses.AssetTaxDisposal{},
+	}
+
+	for key, result := range costBasisResults {
+		for _, disposal := range result.Disposals {
+			if disposal.DisposedAt.UTC().Year() != year {
+				continue
+			}
+
+			holdingPeriod := "short"
+			if disposal.DisposedAt.After(disposal.AcquiredAt.AddDate(longTermHoldingYears, 0, 0)) {
+				holdingPeriod = "long"
+			}
+
+			gain := disposal.Proceeds - disposal.CostBasis
+			convertedGain := disposal.ConvertedProceeds - disposal.ConvertedCostBasis
+
+			taxReport.Disposals = append(taxReport.Disposals, responses.AssetTaxDisposal{
+				ToAsset:               key.ToAsset,
+				FromAsset:             key.FromAsset,
+				AssetType:             result.AssetType,
+				AssetMarket:           result.AssetMarket,
+				Amount:                disposal.Amount,
+				AcquiredAt:            disposal.AcquiredAt,
+				DisposedAt:            disposal.DisposedAt,
+				Proceeds:              disposal.Proceeds,
+				CostBasis:             disposal.CostBasis,
+				Gain:                  gain,
+				HoldingPeriod:         holdingPeriod,
+				ProceedsExchangeRate:  disposal.ProceedsExchangeRate,
+				CostBasisExchangeRate: disposal.CostBasisExchangeRate,
+			})
+
+			taxReport.TotalProceeds += disposal.ConvertedProceeds
+			taxReport.TotalCostBasis += disposal.ConvertedCostBasis
+			taxReport.TotalGain += convertedGain
+
+			if holdingPeriod == "long" {
+				taxReport.LongTermGain += convertedGain
+			} else {
+				taxReport.ShortTermGain += convertedGain
+			}
+		}
+	}
+
+	sort.Slice(taxReport.Disposals, func(i, j int) bool {
+		return taxReport.Disposals[i].DisposedAt.Before(taxReport.Disposals[j].DisposedAt)
+	})
+
+	return taxReport, nil
+}
+
 func (assetModel *AssetModel) GetAssetLogsByUserID(uid string, data requests.AssetLog) ([]Asset, pagination.PaginationData, error) {
 	match := bson.M{
 		"to_asset":     data.ToAsset,
@@ -1055,9 +1328,130 @@ func (assetModel *AssetModel) DeleteAssetLogsByUserID(uid string, data requests.
 		return fmt.Errorf("Failed to delete asset logs by user.")
 	}
 
+	if data.AssetMarket == customAssetMarket {
+		if _, err := assetModel.CustomAssetValuationCollection.DeleteOne(context.TODO(), bson.M{
+			"to_asset":   data.ToAsset,
+			"from_asset": data.FromAsset,
+			"user_id":    uid,
+		}); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"uid":        uid,
+				"to_asset":   data.ToAsset,
+				"from_asset": data.FromAsset,
+			}).Error("failed to delete custom asset valuation: ", err)
+		}
+	}
+
+	return nil
+}
+
+// getRemainingAmountBefore sums the amounts of the pair's logs created before date.
+func (assetModel *AssetModel) getRemainingAmountBefore(uid, toAsset, fromAsset string, date time.Time) (float64, error) {
+	cursor, err := assetModel.Collection.Aggregate(context.TODO(), bson.A{
+		bson.M{"$match": bson.M{
+			"user_id":    uid,
+			"to_asset":   toAsset,
+			"from_asset": fromAsset,
+			"created_at": bson.M{"$lt": date},
+		}},
+		bson.M{"$group": bson.M{
+			"_id":              nil,
+			"remaining_amount": getRemainingAmountSum(),
+		}},
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"to_asset":   toAsset,
+			"from_asset": fromAsset,
+		}).Error("failed to aggregate remaining amount: ", err)
+
+		return 0, fmt.Errorf("Failed to calculate remaining amount.")
+	}
+
+	var remainingAmounts []struct {
+		RemainingAmount float64 `bson:"remaining_amount"`
+	}
+	if err = cursor.All(context.TODO(), &remainingAmounts); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"to_asset":   toAsset,
+			"from_asset": fromAsset,
+		}).Error("failed to decode remaining amount: ", err)
+
+		return 0, fmt.Errorf("Failed to calculate remaining amount.")
+	}
+
+	if len(remainingAmounts) == 0 || remainingAmounts[0].RemainingAmount < 0 {
+		return 0, nil
+	}
+
+	return remainingAmounts[0].RemainingAmount, nil
+}
+
+// setImportSplitAmounts replaces the ratios of imported splits with the added
+// amounts, including the imported rows that are before the split.
+func (assetModel *AssetModel) setImportSplitAmounts(uid string, rows []requests.AssetImportRow) error {
+	var splitIndexes []int
+	for i := range rows {
+		if rows[i].Type == AssetLogSplit {
+			splitIndexes = append(splitIndexes, i)
+		}
+	}
+
+	sort.SliceStable(splitIndexes, func(i, j int) bool {
+		return rows[splitIndexes[i]].CreatedAt.Before(rows[splitIndexes[j]].CreatedAt)
+	})
+
+	for _, splitIndex := range splitIndexes {
+		split := &rows[splitIndex]
+		fromAsset := strings.ToUpper(split.FromAsset)
+
+		remainingAmount, err := assetModel.getRemainingAmountBefore(uid, split.ToAsset, fromAsset, split.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		for i, row := range rows {
+			if i != splitIndex && row.ToAsset == split.ToAsset && strings.ToUpper(row.FromAsset) == fromAsset &&
+				row.CreatedAt.Before(split.CreatedAt) {
+				remainingAmount += getSignedAssetAmount(Asset{Type: row.Type, Amount: row.Amount})
+			}
+		}
+
+		if remainingAmount < 0 {
+			remainingAmount = 0
+		}
+
+		split.SplitRatio = split.Amount
+		split.Amount = remainingAmount * (split.SplitRatio - 1)
+		split.Price = 0
+	}
+
 	return nil
 }
 
+// HasCustomAsset checks if the user has logs of the custom asset.
+func (assetModel *AssetModel) HasCustomAsset(uid, toAsset, fromAsset string) bool {
+	count, err := assetModel.Collection.CountDocuments(context.TODO(), bson.M{
+		"user_id":    uid,
+		"to_asset":   toAsset,
+		"from_asset": fromAsset,
+		"asset_type": AssetTypeCustom,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"to_asset":   toAsset,
+			"from_asset": fromAsset,
+		}).Error("failed to count custom asset logs: ", err)
+
+		return false
+	}
+
+	return count > 0
+}
+
 func (assetModel *AssetModel) DeleteAllAssetsByUserID(uid string) error {
 	if _, err := assetModel.Collection.DeleteMany(context.TODO(), bson.M{
 		"user_id": uid,
@@ -1096,15 +1490,9 @@ func groupAssetsByToAssetFromAsset() bson.M {
 				},
 			},
 		},
-		"remaining_amount": bson.M{
-			"$sum": bson.M{
-				"$cond": bson.A{
-					bson.M{"$eq": bson.A{"$type", "buy"}},
-					"$amount",
-					bson.M{"$multiply": bson.A{"$amount", -1}},
-				},
-			},
-		},
+		"remaining_amount": getRemainingAmountSum(),
+		"total_income":     getAssetLogValueSum(assetLogIncomeTypes),
+		"total_fees":       getAssetLogValueSum(bson.A{AssetLogFee}),
 		"asset_type": bson.M{
 			"$first": "$asset_type",
 		},
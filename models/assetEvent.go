@@ -0,0 +1,99 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson"
+
+/**
+* Asset log types besides buy and sell. Dividends and fees only have a value,
+* staking rewards and airdrops add amount without a cost, and splits store the
+* added amount with the ratio, so summing the amounts of the logs still gives
+* the remaining amount.
+**/
+const (
+	AssetLogBuy      = "buy"
+	AssetLogSell     = "sell"
+	AssetLogDividend = "dividend"
+	AssetLogStaking  = "staking"
+	AssetLogAirdrop  = "airdrop"
+	AssetLogSplit    = "split"
+	AssetLogFee      = "fee"
+)
+
+// Only dividends are income in p/l, staking rewards and airdrops are already
+// counted in the value of the remaining amount.
+var assetLogIncomeTypes = bson.A{AssetLogDividend}
+
+// getRemainingAmountSum is the $sum accumulator of the remaining amount of grouped logs.
+func getRemainingAmountSum() bson.M {
+	return bson.M{
+		"$sum": bson.M{
+			"$switch": bson.M{
+				"branches": bson.A{
+					bson.M{
+						"case": bson.M{"$in": bson.A{"$type", bson.A{AssetLogBuy, AssetLogStaking, AssetLogAirdrop, AssetLogSplit}}},
+						"then": "$amount",
+					},
+					bson.M{
+						"case": bson.M{"$eq": bson.A{"$type", AssetLogSell}},
+						"then": bson.M{"$multiply": bson.A{"$amount", -1}},
+					},
+				},
+				"default": 0,
+			},
+		},
+	}
+}
+
+// getAssetLogValueSum is the $sum accumulator of the values of logs with the given types.
+func getAssetLogValueSum(types bson.A) bson.M {
+	return bson.M{
+		"$sum": bson.M{
+			"$cond": bson.A{
+				bson.M{"$in": bson.A{"$type", types}},
+				"$value",
+				0,
+			},
+		},
+	}
+}
+
+// getSignedAssetAmount returns the change of remaining amount by the log.
+func getSignedAssetAmount(assetLog Asset) float64 {
+	switch assetLog.Type {
+	case AssetLogSell:
+		return -assetLog.Amount
+	case AssetLogDividend, AssetLogFee:
+		return 0
+	default:
+		return assetLog.Amount
+	}
+}
+
+// getAssetLogPrice returns the unit price after the log. Dividends and fees
+// don't have a unit price and splits divide the previous one.
+func getAssetLogPrice(price float64, assetLog Asset) float64 {
+	switch assetLog.Type {
+	case AssetLogDividend, AssetLogFee:
+		return price
+	case AssetLogSplit:
+		if assetLog.SplitRatio > 0 {
+			return price / assetLog.SplitRatio
+		}
+
+		return price
+	default:
+		return assetLog.Price
+	}
+}
+
+// getAssetCashFlow returns the value invested into the holding by the log,
+// negative when it's taken out. Rewards and splits aren't cash flows.
+func getAssetCashFlow(assetLog Asset, exchangeRate float64) float64 {
+	switch assetLog.Type {
+	case AssetLogBuy, AssetLogFee:
+		return assetLog.CurrencyValue * exchangeRate
+	case AssetLogSell, AssetLogDividend:
+		return -assetLog.CurrencyValue * exchangeRate
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,533 @@
+package models
+
+import (
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/**
+* Holdings are valued with the price of their latest log, since there is no
+* price history per holding, and with the current price at the end of a range
+* that ends today. The portfolio uses daily asset stats as valuations when
+* there are enough of them in the range.
+*
+* TWR is the chained return between cash flows and isn't annualized for
+* ranges shorter than a year. XIRR is annualized and nil when it can't be solved.
+* Buys are cash flows into the holding and sells are cash flows out of it.
+**/
+type performanceHolding struct {
+	key          costBasisKey
+	assetType    string
+	logs         []Asset
+	currentPrice float64
+}
+
+type performanceCashFlow struct {
+	date   time.Time
+	amount float64
+}
+
+type performanceCalculator struct {
+	assetModel    *AssetModel
+	currency      string
+	exchangeRates map[string]float64
+}
+
+const (
+	performanceDaysInYear     = 365
+	performanceMaxIterations  = 100
+	performanceRateTolerance  = 1e-7
+	performanceMinDailyPoints = 2
+)
+
+func (assetModel *AssetModel) GetAssetPerformanceByUserID(uid, currency string, data requests.AssetPerformance) (responses.AssetPerformance, error) {
+	cursor, err := aggregateAdjustedAssetLogs(assetModel.Collection, uid, bson.M{})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find asset logs for performance: ", err)
+
+		return responses.AssetPerformance{}, fmt.Errorf("Failed to find asset logs for performance.")
+	}
+
+	var assetLogs []Asset
+	if err = cursor.All(context.TODO(), &assetLogs); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode asset logs for performance: ", err)
+
+		return responses.AssetPerformance{}, fmt.Errorf("Failed to decode asset logs for performance.")
+	}
+
+	now := time.Now().UTC()
+	performance := responses.AssetPerformance{
+		Currency:   currency,
+		StartDate:  now,
+		EndDate:    now,
+		AssetTypes: []responses.AssetPerformanceItem{},
+		Holdings:   []responses.AssetPerformanceItem{},
+	}
+
+	if len(assetLogs) == 0 {
+		return performance, nil
+	}
+
+	startDate := assetLogs[0].CreatedAt
+	if data.StartDate != nil {
+		startDate = *data.StartDate
+	}
+
+	endDate, isEndNow := now, true
+	if data.EndDate != nil && data.EndDate.AddDate(0, 0, 1).Before(now) {
+		endDate, isEndNow = data.EndDate.AddDate(0, 0, 1).Add(-time.Second), false
+	}
+
+	performance.StartDate = startDate
+	performance.EndDate = endDate
+
+	assets, err := assetModel.GetAssetsByUserID(uid, "", requests.AssetSortFilter{Sort: "name", SortType: 1})
+	if err != nil {
+		return responses.AssetPerformance{}, err
+	}
+
+	currentPrices := make(map[costBasisKey]float64)
+	for _, asset := range assets {
+		if asset.RemainingAmount > 0 {
+			currentPrices[costBasisKey{ToAsset: asset.ToAsset, FromAsset: asset.FromAsset}] = asset.CurrentTotal / asset.RemainingAmount
+		}
+	}
+
+	var (
+		holdings      []*performanceHolding
+		holdingByKey  = make(map[costBasisKey]*performanceHolding)
+		assetTypeList []string
+		assetTypes    = make(map[string][]*performanceHolding)
+	)
+
+	for _, assetLog := range assetLogs {
+		key := costBasisKey{ToAsset: assetLog.ToAsset, FromAsset: assetLog.FromAsset}
+
+		holding, ok := holdingByKey[key]
+		if !ok {
+			holding = &performanceHolding{
+				key:          key,
+				assetType:    assetLog.AssetType,
+				currentPrice: currentPrices[key],
+			}
+			holdingByKey[key] = holding
+			holdings = append(holdings, holding)
+
+			if _, ok := assetTypes[holding.assetType]; !ok {
+				assetTypeList = append(assetTypeList, holding.assetType)
+			}
+
+			assetTypes[holding.assetType] = append(assetTypes[holding.assetType], holding)
+		}
+
+		holding.logs = append(holding.logs, assetLog)
+	}
+
+	calculator := &performanceCalculator{
+		assetModel:    assetModel,
+		currency:      currency,
+		exchangeRates: make(map[string]float64),
+	}
+
+	for _, holding := range holdings {
+		item, err := calculator.calculate([]*performanceHolding{holding}, startDate, endDate, isEndNow)
+		if err != nil {
+			return responses.AssetPerformance{}, err
+		}
+
+		item.ToAsset = holding.key.ToAsset
+		item.FromAsset = holding.key.FromAsset
+		item.AssetType = holding.assetType
+
+		performance.Holdings = append(performance.Holdings, item)
+	}
+
+	for _, assetType := range assetTypeList {
+		item, err := calculator.calculate(assetTypes[assetType], startDate, endDate, isEndNow)
+		if err != nil {
+			return responses.AssetPerformance{}, err
+		}
+
+		item.AssetType = assetType
+
+		performance.AssetTypes = append(performance.AssetTypes, item)
+	}
+
+	if performance.Portfolio, err = calculator.calculatePortfolio(uid, holdings, startDate, endDate, isEndNow); err != nil {
+		return responses.AssetPerformance{}, err
+	}
+
+	return performance, nil
+}
+
+func (calculator *performanceCalculator) getExchangeRate(fromCurrency string, date time.Time) (float64, error) {
+	key := fromCurrency + date.UTC().Format("2006-01-02")
+
+	if exchangeRate, ok := calculator.exchangeRates[key]; ok {
+		return exchangeRate, nil
+	}
+
+	exchangeRate, err := getHistoricalExchangeRate(
+		calculator.assetModel.ExchangeHistoryCollection, calculator.assetModel.ExchangeCollection,
+		fromCurrency, calculator.currency, date,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	calculator.exchangeRates[key] = exchangeRate
+
+	return exchangeRate, nil
+}
+
+// calculate replays the logs of holdings, values them at every log in the
+// range and chains the returns between them.
+func (calculator *performanceCalculator) calculate(
+	holdings []*performanceHolding, startDate, endDate time.Time, isEndNow bool,
+) (responses.AssetPerformanceItem, error) {
+	var (
+		item      responses.AssetPerformanceItem
+		events    []Asset
+		amounts   = make(map[costBasisKey]float64)
+		prices    = make(map[costBasisKey]float64)
+		cashFlows []performanceCashFlow
+		growth    = 1.0
+	)
+
+	for _, holding := range holdings {
+		for _, assetLog := range holding.logs {
+			if assetLog.CreatedAt.Before(startDate) {
+				amounts[holding.key] += getSignedAssetAmount(assetLog)
+				prices[holding.key] = getAssetLogPrice(prices[holding.key], assetLog)
+			} else if !assetLog.CreatedAt.After(endDate) {
+				events = append(events, assetLog)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+
+	startValue, err := calculator.getHoldingsValue(holdings, amounts, prices, startDate)
+	if err != nil {
+		return responses.AssetPerformanceItem{}, err
+	}
+
+	item.StartValue = startValue
+	if startValue > 0 {
+		cashFlows = append(cashFlows, performanceCashFlow{date: startDate, amount: -startValue})
+	}
+
+	previousValue := startValue
+
+	for _, event := range events {
+		key := costBasisKey{ToAsset: event.ToAsset, FromAsset: event.FromAsset}
+		prices[key] = getAssetLogPrice(prices[key], event)
+
+		valueBefore, err := calculator.getHoldingsValue(holdings, amounts, prices, event.CreatedAt)
+		if err != nil {
+			return responses.AssetPerformanceItem{}, err
+		}
+
+		if previousValue > 0 {
+			growth *= valueBefore / previousValue
+		}
+
+		exchangeRate, err := calculator.getExchangeRate(event.FromAsset, event.CreatedAt)
+		if err != nil {
+			return responses.AssetPerformanceItem{}, err
+		}
+
+		cashFlow := getAssetCashFlow(event, exchangeRate)
+
+		item.NetCashFlow += cashFlow
+		cashFlows = append(cashFlows, performanceCashFlow{date: event.CreatedAt, amount: -cashFlow})
+
+		amounts[key] += getSignedAssetAmount(event)
+
+		if previousValue, err = calculator.getHoldingsValue(holdings, amounts, prices, event.CreatedAt); err != nil {
+			return responses.AssetPerformanceItem{}, err
+		}
+	}
+
+	if isEndNow {
+		for _, holding := range holdings {
+			if holding.currentPrice > 0 {
+				prices[holding.key] = holding.currentPrice
+			}
+		}
+	}
+
+	if item.EndValue, err = calculator.getHoldingsValue(holdings, amounts, prices, endDate); err != nil {
+		return responses.AssetPerformanceItem{}, err
+	}
+
+	if previousValue > 0 {
+		growth *= item.EndValue / previousValue
+	}
+
+	cashFlows = append(cashFlows, performanceCashFlow{date: endDate, amount: item.EndValue})
+
+	setPerformanceReturns(&item, growth, cashFlows, startDate, endDate)
+
+	return item, nil
+}
+
+// calculatePortfolio uses daily asset stats as valuations and chains daily
+// Modified Dietz returns. Falls back to holdings when there aren't enough stats.
+func (calculator *performanceCalculator) calculatePortfolio(
+	uid string, holdings []*performanceHolding, startDate, endDate time.Time, isEndNow bool,
+) (responses.AssetPerformanceItem, error) {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	cursor, err := calculator.assetModel.DailyAssetStatsCollection.Find(context.TODO(), bson.M{
+		"user_id": objectUID,
+		"created_at": bson.M{
+			"$gte": startDate,
+			"$lte": endDate,
+		},
+	}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find daily asset stats for performance: ", err)
+
+		return responses.AssetPerformanceItem{}, fmt.Errorf("Failed to find daily asset stats for performance.")
+	}
+
+	var dailyAssetStats []responses.DailyAssetStatsCalculation
+	if err = cursor.All(context.TODO(), &dailyAssetStats); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode daily asset stats for performance: ", err)
+
+		return responses.AssetPerformanceItem{}, fmt.Errorf("Failed to decode daily asset stats for performance.")
+	}
+
+	var valuations []performanceCashFlow
+	for _, dailyAssetStat := range dailyAssetStats {
+		if dailyAssetStat.Currency == "" {
+			dailyAssetStat.Currency = calculator.currency
+		}
+
+		exchangeRate, err := calculator.getExchangeRate(dailyAssetStat.Currency, dailyAssetStat.CreatedAt)
+		if err != nil {
+			return responses.AssetPerformanceItem{}, err
+		}
+
+		valuations = append(valuations, performanceCashFlow{
+			date:   dailyAssetStat.CreatedAt,
+			amount: dailyAssetStat.TotalAssets * exchangeRate,
+		})
+	}
+
+	holdingsItem, err := calculator.calculate(holdings, startDate, endDate, isEndNow)
+	if err != nil {
+		return responses.AssetPerformanceItem{}, err
+	}
+
+	if isEndNow && len(valuations) > 0 {
+		valuations = append(valuations, performanceCashFlow{date: endDate, amount: holdingsItem.EndValue})
+	}
+
+	if len(valuations) < performanceMinDailyPoints {
+		return holdingsItem, nil
+	}
+
+	var periodFlows []performanceCashFlow
+	for _, holding := range holdings {
+		for _, assetLog := range holding.logs {
+			if assetLog.CreatedAt.After(valuations[0].date) && !assetLog.CreatedAt.After(endDate) {
+				exchangeRate, err := calculator.getExchangeRate(assetLog.FromAsset, assetLog.CreatedAt)
+				if err != nil {
+					return responses.AssetPerformanceItem{}, err
+				}
+
+				periodFlows = append(periodFlows, performanceCashFlow{
+					date:   assetLog.CreatedAt,
+					amount: getAssetCashFlow(assetLog, exchangeRate),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(periodFlows, func(i, j int) bool {
+		return periodFlows[i].date.Before(periodFlows[j].date)
+	})
+
+	var (
+		item      responses.AssetPerformanceItem
+		growth    = 1.0
+		flowIndex = 0
+		cashFlows = []performanceCashFlow{{date: valuations[0].date, amount: -valuations[0].amount}}
+	)
+
+	item.StartValue = valuations[0].amount
+	item.EndValue = valuations[len(valuations)-1].amount
+
+	for i := 1; i < len(valuations); i++ {
+		var periodFlow float64
+		for flowIndex < len(periodFlows) && !periodFlows[flowIndex].date.After(valuations[i].date) {
+			periodFlow += periodFlows[flowIndex].amount
+			cashFlows = append(cashFlows, performanceCashFlow{
+				date:   periodFlows[flowIndex].date,
+				amount: -periodFlows[flowIndex].amount,
+			})
+
+			flowIndex++
+		}
+
+		item.NetCashFlow += periodFlow
+
+		if denominator := valuations[i-1].amount + periodFlow/2; denominator > 0 {
+			growth *= 1 + (valuations[i].amount-valuations[i-1].amount-periodFlow)/denominator
+		}
+	}
+
+	cashFlows = append(cashFlows, performanceCashFlow{date: valuations[len(valuations)-1].date, amount: item.EndValue})
+
+	setPerformanceReturns(&item, growth, cashFlows, valuations[0].date, valuations[len(valuations)-1].date)
+
+	return item, nil
+}
+
+func (calculator *performanceCalculator) getHoldingsValue(
+	holdings []*performanceHolding, amounts, prices map[costBasisKey]float64, date time.Time,
+) (float64, error) {
+	var value float64
+
+	for _, holding := range holdings {
+		amount := amounts[holding.key]
+		if amount <= 0 {
+			continue
+		}
+
+		exchangeRate, err := calculator.getExchangeRate(holding.key.FromAsset, date)
+		if err != nil {
+			return 0, err
+		}
+
+		value += amount * prices[holding.key] * exchangeRate
+	}
+
+	return value, nil
+}
+
+func setPerformanceReturns(
+	item *responses.AssetPerformanceItem, growth float64, cashFlows []performanceCashFlow, startDate, endDate time.Time,
+) {
+	item.TWR = (growth - 1) * 100
+	item.AnnualizedTWR = item.TWR
+
+	if days := endDate.Sub(startDate).Hours() / 24; days >= performanceDaysInYear && growth > 0 {
+		item.AnnualizedTWR = (math.Pow(growth, performanceDaysInYear/days) - 1) * 100
+	}
+
+	if xirr, ok := calculateXIRR(cashFlows); ok {
+		xirrPercentage := xirr * 100
+		item.XIRR = &xirrPercentage
+	}
+}
+
+// calculateXIRR finds the annual rate that makes the net present value of the
+// cash flows zero, with Newton's method and bisection when it doesn't converge.
+func calculateXIRR(cashFlows []performanceCashFlow) (float64, bool) {
+	var hasInflow, hasOutflow bool
+	for _, cashFlow := range cashFlows {
+		hasInflow = hasInflow || cashFlow.amount > 0
+		hasOutflow = hasOutflow || cashFlow.amount < 0
+	}
+
+	if !hasInflow || !hasOutflow {
+		return 0, false
+	}
+
+	firstDate := cashFlows[0].date
+	for _, cashFlow := range cashFlows {
+		if cashFlow.date.Before(firstDate) {
+			firstDate = cashFlow.date
+		}
+	}
+
+	netPresentValue := func(rate float64) (float64, float64) {
+		var value, derivative float64
+
+		for _, cashFlow := range cashFlows {
+			years := cashFlow.date.Sub(firstDate).Hours() / 24 / performanceDaysInYear
+			discount := math.Pow(1+rate, years)
+
+			value += cashFlow.amount / discount
+			derivative -= years * cashFlow.amount / (discount * (1 + rate))
+		}
+
+		return value, derivative
+	}
+
+	rate := 0.1
+	for i := 0; i < performanceMaxIterations; i++ {
+		value, derivative := netPresentValue(rate)
+		if math.Abs(value) < performanceRateTolerance {
+			return rate, true
+		}
+
+		if derivative == 0 {
+			break
+		}
+
+		nextRate := rate - value/derivative
+		if nextRate <= -1 || math.IsNaN(nextRate) || math.IsInf(nextRate, 0) {
+			break
+		}
+
+		if math.Abs(nextRate-rate) < performanceRateTolerance {
+			return nextRate, true
+		}
+
+		rate = nextRate
+	}
+
+	low, high := -0.9999, 1.0
+	lowValue, _ := netPresentValue(low)
+	highValue, _ := netPresentValue(high)
+
+	for i := 0; i < performanceMaxIterations && lowValue*highValue > 0; i++ {
+		high *= 2
+		highValue, _ = netPresentValue(high)
+	}
+
+	if lowValue*highValue > 0 {
+		return 0, false
+	}
+
+	for i := 0; i < performanceMaxIterations; i++ {
+		middle := (low + high) / 2
+		middleValue, _ := netPresentValue(middle)
+
+		if math.Abs(middleValue) < performanceRateTolerance || (high-low)/2 < performanceRateTolerance {
+			return middle, true
+		}
+
+		if middleValue*lowValue < 0 {
+			high = middle
+		} else {
+			low, lowValue = middle, middleValue
+		}
+	}
+
+	return (low + high) / 2, true
+}
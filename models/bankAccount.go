@@ -31,24 +31,25 @@ type BankAccount struct {
 	Iban          string             `bson:"iban" json:"iban"`
 	AccountHolder string             `bson:"account_holder" json:"account_holder"`
 	Currency      string             `bson:"currency" json:"currency"`
-	CreatedAt     time.Time          `bson:"created_at" json:"-"`
+	// Balance before the first transaction, the balance is calculated from it.
+	OpeningBalance float64   `bson:"opening_balance" json:"opening_balance"`
+	CreatedAt      time.Time `bson:"created_at" json:"-"`
 }
 
-const bankAccPremiumLimit = 2
-
-func createBankAccount(uid, name, iban, accoutHolder, currency string) *BankAccount {
+func createBankAccount(uid, name, iban, accoutHolder, currency string, openingBalance float64) *BankAccount {
 	return &BankAccount{
-		UserID:        uid,
-		Name:          name,
-		Iban:          iban,
-		AccountHolder: accoutHolder,
-		Currency:      currency,
-		CreatedAt:     time.Now().UTC(),
+		UserID:         uid,
+		Name:           name,
+		Iban:           iban,
+		AccountHolder:  accoutHolder,
+		Currency:       currency,
+		OpeningBalance: openingBalance,
+		CreatedAt:      time.Now().UTC(),
 	}
 }
 
 func (bankAccModel *BankAccountModel) CreateBankAccount(uid string, data requests.BankAccountCreate) (BankAccount, error) {
-	bankAccount := createBankAccount(uid, data.Name, data.Iban, data.AccountHolder, data.Currency)
+	bankAccount := createBankAccount(uid, data.Name, data.Iban, data.AccountHolder, data.Currency, data.OpeningBalance)
 
 	var (
 		insertedID *mongo.InsertOneResult
@@ -76,7 +77,7 @@ func (bankAccModel *BankAccountModel) GetUserBankAccountCount(uid string) int64
 			"uid": uid,
 		}).Error("failed to count bank accounts: ", err)
 
-		return bankAccPremiumLimit
+		return countErrorFallback
 	}
 
 	return count
@@ -148,6 +149,10 @@ func (bankAccModel *BankAccountModel) UpdateBankAccount(data requests.BankAccoun
 		bankAccount.Iban = *data.Iban
 	}
 
+	if data.OpeningBalance != nil {
+		bankAccount.OpeningBalance = *data.OpeningBalance
+	}
+
 	if _, err := bankAccModel.Collection.UpdateOne(context.TODO(), bson.M{
 		"_id": objectBankAccountID,
 	}, bson.M{"$set": bankAccount}); err != nil {
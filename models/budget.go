@@ -0,0 +1,460 @@
+package models
+
+import (
+	"asset_backend/db"
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BudgetModel struct {
+	Collection                *mongo.Collection
+	TransactionCollection     *mongo.Collection
+	ExchangeCollection        *mongo.Collection
+	ExchangeHistoryCollection *mongo.Collection
+	UserCollection            *mongo.Collection
+}
+
+func NewBudgetModel(mongoDB *db.MongoDB) *BudgetModel {
+	return &BudgetModel{
+		Collection:                mongoDB.Database.Collection("budgets"),
+		TransactionCollection:     mongoDB.Database.Collection("transactions"),
+		ExchangeCollection:        mongoDB.Database.Collection("exchanges"),
+		ExchangeHistoryCollection: mongoDB.Database.Collection("exchange-history"),
+		UserCollection:            mongoDB.Database.Collection("users"),
+	}
+}
+
+type Budget struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	UserID         string             `bson:"user_id" json:"user_id"`
+	Category       int64              `bson:"category" json:"category"`
+	Limit          float64            `bson:"limit" json:"limit"`
+	AlertMonth     string             `bson:"alert_month" json:"-"`
+	AlertThreshold int                `bson:"alert_threshold" json:"-"`
+	CreatedAt      time.Time          `bson:"created_at" json:"-"`
+}
+
+type BudgetAlert struct {
+	FCMToken  string
+	BudgetID  string
+	Category  int64
+	Threshold int
+	Spent     float64
+	Limit     float64
+	Currency  string
+}
+
+const budgetMonthLayout = "2006-01"
+
+// Percentages of the monthly limit that trigger an alert, in ascending order.
+var budgetAlertThresholds = []int{80, 100}
+
+func createBudget(uid string, category int64, limit float64) *Budget {
+	return &Budget{
+		UserID:    uid,
+		Category:  category,
+		Limit:     limit,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (budgetModel *BudgetModel) CreateBudget(uid string, data requests.BudgetCreate) (Budget, error) {
+	budget := createBudget(uid, *data.Category, data.Limit)
+
+	insertedID, err := budgetModel.Collection.InsertOne(context.TODO(), budget)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":  uid,
+			"data": data,
+		}).Error("failed to create new budget: ", err)
+
+		return Budget{}, fmt.Errorf("Failed to create new budget.")
+	}
+
+	budget.ID = insertedID.InsertedID.(primitive.ObjectID)
+
+	return *budget, nil
+}
+
+func (budgetModel *BudgetModel) GetBudgetByID(budgetID string) (Budget, error) {
+	objectBudgetID, _ := primitive.ObjectIDFromHex(budgetID)
+
+	result := budgetModel.Collection.FindOne(context.TODO(), bson.M{"_id": objectBudgetID})
+
+	var budget Budget
+	if err := result.Decode(&budget); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"budget_id": budgetID,
+		}).Error("failed to find budget by budget id: ", err)
+
+		return Budget{}, fmt.Errorf("Failed to find budget by budget id.")
+	}
+
+	return budget, nil
+}
+
+func (budgetModel *BudgetModel) GetBudgetsByUserID(uid string) ([]Budget, error) {
+	cursor, err := budgetModel.Collection.Find(context.TODO(), bson.M{
+		"user_id": uid,
+	}, options.Find().SetSort(bson.M{"category": 1}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find budgets: ", err)
+
+		return nil, fmt.Errorf("Failed to find budgets.")
+	}
+
+	var budgets []Budget
+	if err = cursor.All(context.TODO(), &budgets); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode budgets: ", err)
+
+		return nil, fmt.Errorf("Failed to decode budgets.")
+	}
+
+	return budgets, nil
+}
+
+func (budgetModel *BudgetModel) GetUserBudgetCount(uid string) int64 {
+	count, err := budgetModel.Collection.CountDocuments(context.TODO(), bson.M{"user_id": uid})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to count user budgets: ", err)
+
+		return countErrorFallback
+	}
+
+	return count
+}
+
+func (budgetModel *BudgetModel) HasBudgetForCategory(uid string, category int64) bool {
+	count, err := budgetModel.Collection.CountDocuments(context.TODO(), bson.M{
+		"user_id":  uid,
+		"category": category,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":      uid,
+			"category": category,
+		}).Error("failed to count budgets by category: ", err)
+
+		return true
+	}
+
+	return count > 0
+}
+
+func (budgetModel *BudgetModel) UpdateBudget(data requests.BudgetUpdate, budget Budget) (Budget, error) {
+	if data.Limit != nil {
+		budget.Limit = *data.Limit
+	}
+
+	// Alerts are re-evaluated against the new limit.
+	budget.AlertMonth = ""
+	budget.AlertThreshold = 0
+
+	if _, err := budgetModel.Collection.UpdateOne(context.TODO(), bson.M{
+		"_id": budget.ID,
+	}, bson.M{"$set": budget}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"budget_id": data.ID,
+			"data":      data,
+		}).Error("failed to update budget: ", err)
+
+		return Budget{}, fmt.Errorf("Failed to update budget.")
+	}
+
+	return budget, nil
+}
+
+// GetBudgetStatus returns spent, remaining and projected amounts of the month
+// in user's currency. Projection assumes the spending continues at the same daily pace.
+func (budgetModel *BudgetModel) GetBudgetStatus(uid, currency string, date time.Time) (responses.BudgetStatus, error) {
+	budgets, err := budgetModel.GetBudgetsByUserID(uid)
+	if err != nil {
+		return responses.BudgetStatus{}, err
+	}
+
+	return budgetModel.getBudgetStatus(uid, currency, date, budgets)
+}
+
+func (budgetModel *BudgetModel) getBudgetStatus(
+	uid, currency string, date time.Time, budgets []Budget,
+) (responses.BudgetStatus, error) {
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	budgetStatus := responses.BudgetStatus{
+		Currency: currency,
+		Month:    monthStart.Format(budgetMonthLayout),
+		Budgets:  []responses.BudgetCategoryStatus{},
+	}
+
+	if len(budgets) == 0 {
+		return budgetStatus, nil
+	}
+
+	spending, err := budgetModel.getCategorySpending(uid, currency, monthStart, monthEnd)
+	if err != nil {
+		return responses.BudgetStatus{}, err
+	}
+
+	daysInMonth := monthEnd.Sub(monthStart).Hours() / 24
+	elapsedDays := math.Min(math.Ceil(date.Sub(monthStart).Hours()/24), daysInMonth)
+
+	if elapsedDays < 1 {
+		elapsedDays = 1
+	}
+
+	for _, budget := range budgets {
+		spent := spending[budget.Category]
+
+		budgetStatus.Budgets = append(budgetStatus.Budgets, responses.BudgetCategoryStatus{
+			ID:         budget.ID.Hex(),
+			Category:   budget.Category,
+			Limit:      budget.Limit,
+			Spent:      spent,
+			Remaining:  budget.Limit - spent,
+			Projected:  spent / elapsedDays * daysInMonth,
+			Percentage: spent / budget.Limit * 100,
+		})
+	}
+
+	return budgetStatus, nil
+}
+
+func (budgetModel *BudgetModel) getCategorySpending(uid, currency string, startDate, endDate time.Time) (map[int64]float64, error) {
+	match := bson.M{"$match": bson.M{
+		"user_id":  uid,
+		"category": bson.M{"$ne": Income},
+		"transaction_date": bson.M{
+			"$gte": startDate,
+			"$lt":  endDate,
+		},
+	}}
+	group := bson.M{"$group": bson.M{
+		"_id": bson.M{
+			"category": "$category",
+			"currency": "$currency",
+			"date": bson.M{
+				"$dateTrunc": bson.M{
+					"date": "$transaction_date",
+					"unit": "day",
+				},
+			},
+		},
+		"total": bson.M{
+			"$sum": "$price",
+		},
+	}}
+
+	cursor, err := budgetModel.TransactionCollection.Aggregate(context.TODO(), bson.A{match, group})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to aggregate budget spending: ", err)
+
+		return nil, fmt.Errorf("Failed to aggregate budget spending.")
+	}
+
+	var categoryTotals []struct {
+		ID struct {
+			Category int64     `bson:"category"`
+			Currency string    `bson:"currency"`
+			Date     time.Time `bson:"date"`
+		} `bson:"_id"`
+		Total float64 `bson:"total"`
+	}
+
+	if err = cursor.All(context.TODO(), &categoryTotals); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode budget spending: ", err)
+
+		return nil, fmt.Errorf("Failed to decode budget spending.")
+	}
+
+	var (
+		spending      = make(map[int64]float64)
+		exchangeRates = make(map[string]float64)
+	)
+
+	// Totals are per day, so they're converted at the rate of their date.
+	for _, categoryTotal := range categoryTotals {
+		key := categoryTotal.ID.Currency + "/" + categoryTotal.ID.Date.Format("2006-01-02")
+
+		exchangeRate, ok := exchangeRates[key]
+		if !ok {
+			var err error
+			if exchangeRate, err = getHistoricalExchangeRate(
+				budgetModel.ExchangeHistoryCollection, budgetModel.ExchangeCollection,
+				categoryTotal.ID.Currency, currency, categoryTotal.ID.Date,
+			); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"uid":      uid,
+					"from":     categoryTotal.ID.Currency,
+					"currency": currency,
+					"date":     categoryTotal.ID.Date,
+				}).Error("failed to find exchange rate for budget spending: ", err)
+
+				return nil, err
+			}
+
+			exchangeRates[key] = exchangeRate
+		}
+
+		spending[categoryTotal.ID.Category] += categoryTotal.Total * exchangeRate
+	}
+
+	return spending, nil
+}
+
+// GetBudgetAlerts returns an alert for every budget that crossed a threshold it
+// hasn't been alerted for in the current month. If uid is nil every user with
+// app notifications enabled is checked.
+func (budgetModel *BudgetModel) GetBudgetAlerts(uid *string) []BudgetAlert {
+	match := bson.M{}
+	if uid != nil {
+		match["user_id"] = *uid
+	}
+
+	userIDs, err := budgetModel.Collection.Distinct(context.TODO(), "user_id", match)
+	if err != nil {
+		logrus.Error("failed to find budget users: ", err)
+		return nil
+	}
+
+	var alerts []BudgetAlert
+
+	for _, userID := range userIDs {
+		budgetUID, ok := userID.(string)
+		if !ok {
+			continue
+		}
+
+		alerts = append(alerts, budgetModel.getUserBudgetAlerts(budgetUID)...)
+	}
+
+	return alerts
+}
+
+func (budgetModel *BudgetModel) getUserBudgetAlerts(uid string) []BudgetAlert {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	var user User
+	if err := budgetModel.UserCollection.FindOne(context.TODO(), bson.M{
+		"_id": objectUID,
+	}).Decode(&user); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find user for budget alerts: ", err)
+
+		return nil
+	}
+
+	if !user.AppNotification || user.FCMToken == "" {
+		return nil
+	}
+
+	budgets, err := budgetModel.GetBudgetsByUserID(uid)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	month := now.Format(budgetMonthLayout)
+
+	budgetStatus, err := budgetModel.getBudgetStatus(uid, user.Currency, now, budgets)
+	if err != nil {
+		return nil
+	}
+
+	var alerts []BudgetAlert
+
+	for i, status := range budgetStatus.Budgets {
+		budget := budgets[i]
+
+		var threshold int
+		for _, alertThreshold := range budgetAlertThresholds {
+			if status.Percentage >= float64(alertThreshold) {
+				threshold = alertThreshold
+			}
+		}
+
+		if threshold == 0 || (budget.AlertMonth == month && budget.AlertThreshold >= threshold) {
+			continue
+		}
+
+		if _, err := budgetModel.Collection.UpdateOne(context.TODO(), bson.M{
+			"_id": budget.ID,
+		}, bson.M{"$set": bson.M{
+			"alert_month":     month,
+			"alert_threshold": threshold,
+		}}); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"budget_id": budget.ID,
+				"threshold": threshold,
+			}).Error("failed to update budget alert: ", err)
+
+			continue
+		}
+
+		alerts = append(alerts, BudgetAlert{
+			FCMToken:  user.FCMToken,
+			BudgetID:  budget.ID.Hex(),
+			Category:  budget.Category,
+			Threshold: threshold,
+			Spent:     status.Spent,
+			Limit:     status.Limit,
+			Currency:  user.Currency,
+		})
+	}
+
+	return alerts
+}
+
+func (budgetModel *BudgetModel) DeleteBudgetByBudgetID(uid, budgetID string) (bool, error) {
+	objectBudgetID, _ := primitive.ObjectIDFromHex(budgetID)
+
+	count, err := budgetModel.Collection.DeleteOne(context.TODO(), bson.M{
+		"_id":     objectBudgetID,
+		"user_id": uid,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":       uid,
+			"budget_id": budgetID,
+		}).Error("failed to delete budget by budget id: ", err)
+
+		return false, fmt.Errorf("Failed to delete budget by budget id.")
+	}
+
+	return count.DeletedCount > 0, nil
+}
+
+func (budgetModel *BudgetModel) DeleteAllBudgetsByUserID(uid string) error {
+	if _, err := budgetModel.Collection.DeleteMany(context.TODO(), bson.M{
+		"user_id": uid,
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to delete all budgets by user id: ", err)
+
+		return fmt.Errorf("Failed to delete all budgets by user id.")
+	}
+
+	return nil
+}
@@ -33,26 +33,27 @@ type Card struct {
 	Color      string             `bson:"color" json:"color"`
 	CardType   string             `bson:"type" json:"type"`
 	Currency   string             `bson:"currency" json:"currency"`
-	CreatedAt  time.Time          `bson:"created_at" json:"-"`
+	// Outstanding balance before the first transaction.
+	OpeningBalance float64   `bson:"opening_balance" json:"opening_balance"`
+	CreatedAt      time.Time `bson:"created_at" json:"-"`
 }
 
-const cardPremiumLimit = 3
-
-func createCardObject(uid, name, last4Digit, cardHolder, color, cardType, currency string) *Card {
+func createCardObject(uid, name, last4Digit, cardHolder, color, cardType, currency string, openingBalance float64) *Card {
 	return &Card{
-		UserID:     uid,
-		Name:       name,
-		Last4Digit: last4Digit,
-		CardHolder: cardHolder,
-		Color:      color,
-		CardType:   cardType,
-		Currency:   currency,
-		CreatedAt:  time.Now().UTC(),
+		UserID:         uid,
+		Name:           name,
+		Last4Digit:     last4Digit,
+		CardHolder:     cardHolder,
+		Color:          color,
+		CardType:       cardType,
+		Currency:       currency,
+		OpeningBalance: openingBalance,
+		CreatedAt:      time.Now().UTC(),
 	}
 }
 
 func (cardModel *CardModel) CreateCard(uid string, data requests.Card) (Card, error) {
-	card := createCardObject(uid, data.Name, data.Last4Digit, data.CardHolder, data.Color, data.CardType, data.Currency)
+	card := createCardObject(uid, data.Name, data.Last4Digit, data.CardHolder, data.Color, data.CardType, data.Currency, data.OpeningBalance)
 
 	var (
 		insertedID *mongo.InsertOneResult
@@ -79,7 +80,7 @@ func (cardModel *CardModel) GetUserCardCount(uid string) int64 {
 			"uid": uid,
 		}).Error("failed to count user cards: ", err)
 
-		return cardPremiumLimit
+		return countErrorFallback
 	}
 
 	return count
@@ -159,6 +160,10 @@ func (cardModel *CardModel) UpdateCard(data requests.CardUpdate, card Card) (Car
 		card.Currency = *data.Currency
 	}
 
+	if data.OpeningBalance != nil {
+		card.OpeningBalance = *data.OpeningBalance
+	}
+
 	if _, err := cardModel.Collection.UpdateOne(context.TODO(), bson.M{
 		"_id": objectCardID,
 	}, bson.M{"$set": card}); err != nil {
@@ -0,0 +1,397 @@
+package models
+
+import (
+	"asset_backend/db"
+	"asset_backend/requests"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryModel struct {
+	Collection                     *mongo.Collection
+	TransactionCollection          *mongo.Collection
+	RecurringTransactionCollection *mongo.Collection
+	UserCollection                 *mongo.Collection
+}
+
+func NewCategoryModel(mongoDB *db.MongoDB) *CategoryModel {
+	return &CategoryModel{
+		Collection:                     mongoDB.Database.Collection("categories"),
+		TransactionCollection:          mongoDB.Database.Collection("transactions"),
+		RecurringTransactionCollection: mongoDB.Database.Collection("recurring-transactions"),
+		UserCollection:                 mongoDB.Database.Collection("users"),
+	}
+}
+
+/**
+* Built-in categories are seeded per user with built_in set to the legacy
+* category constant. Transactions keep the built-in category of the root
+* category in `category`, so income/expense calculations keep working, and
+* reference the user category with `category_id` when one is selected.
+**/
+type Category struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	UserID     string             `bson:"user_id" json:"user_id"`
+	Name       string             `bson:"name" json:"name"`
+	Color      string             `bson:"color" json:"color"`
+	ParentID   *string            `bson:"parent_id" json:"parent_id"`
+	BuiltIn    *int64             `bson:"built_in" json:"built_in"`
+	IsArchived bool               `bson:"is_archived" json:"is_archived"`
+	CreatedAt  time.Time          `bson:"created_at" json:"-"`
+}
+
+var defaultCategories = []struct {
+	builtIn int64
+	name    string
+	color   string
+}{
+	{Food, "Food", "#FF9800"},
+	{Shopping, "Shopping", "#E91E63"},
+	{Transportation, "Transportation", "#2196F3"},
+	{Entertainment, "Entertainment", "#9C27B0"},
+	{Software, "Software", "#607D8B"},
+	{Health, "Health", "#F44336"},
+	{Income, "Income", "#4CAF50"},
+	{Others, "Others", "#795548"},
+}
+
+func createCategory(uid, name, color string, parentID *string, builtIn *int64) *Category {
+	return &Category{
+		UserID:     uid,
+		Name:       name,
+		Color:      color,
+		ParentID:   parentID,
+		BuiltIn:    builtIn,
+		IsArchived: false,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+// CreateDefaultCategories seeds the built-in categories the user doesn't have
+// yet. They're upserted by built_in, so calling it again doesn't duplicate them.
+func (categoryModel *CategoryModel) CreateDefaultCategories(uid string) error {
+	return createDefaultCategories(categoryModel.Collection, uid)
+}
+
+// CreateMissingDefaultCategories seeds the built-in categories of users
+// registered before categories existed.
+func (categoryModel *CategoryModel) CreateMissingDefaultCategories() {
+	seededUserIDs, err := categoryModel.Collection.Distinct(context.TODO(), "user_id", bson.M{
+		"built_in": bson.M{"$ne": nil},
+	})
+	if err != nil {
+		logrus.Error("failed to find users with default categories: ", err)
+		return
+	}
+
+	isSeeded := make(map[string]bool, len(seededUserIDs))
+	for _, seededUserID := range seededUserIDs {
+		if uid, ok := seededUserID.(string); ok {
+			isSeeded[uid] = true
+		}
+	}
+
+	cursor, err := categoryModel.UserCollection.Find(
+		context.TODO(), bson.M{}, options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		logrus.Error("failed to find users for default categories: ", err)
+		return
+	}
+
+	var users []User
+	if err = cursor.All(context.TODO(), &users); err != nil {
+		logrus.Error("failed to decode users for default categories: ", err)
+		return
+	}
+
+	for _, user := range users {
+		if uid := user.ID.Hex(); !isSeeded[uid] {
+			createDefaultCategories(categoryModel.Collection, uid)
+		}
+	}
+}
+
+func createDefaultCategories(collection *mongo.Collection, uid string) error {
+	writeModels := make([]mongo.WriteModel, len(defaultCategories))
+	for i, defaultCategory := range defaultCategories {
+		builtIn := defaultCategory.builtIn
+
+		writeModels[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"user_id":  uid,
+				"built_in": builtIn,
+			}).
+			SetUpdate(bson.M{
+				"$setOnInsert": createCategory(uid, defaultCategory.name, defaultCategory.color, nil, &builtIn),
+			}).
+			SetUpsert(true)
+	}
+
+	if _, err := collection.BulkWrite(context.TODO(), writeModels, options.BulkWrite().SetOrdered(false)); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to create default categories: ", err)
+
+		return fmt.Errorf("Failed to create default categories.")
+	}
+
+	return nil
+}
+
+func (categoryModel *CategoryModel) CreateCategory(uid string, data requests.CategoryCreate) (Category, error) {
+	category := createCategory(uid, data.Name, data.Color, data.ParentID, nil)
+
+	insertedID, err := categoryModel.Collection.InsertOne(context.TODO(), category)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":  uid,
+			"data": data,
+		}).Error("failed to create new category: ", err)
+
+		return Category{}, fmt.Errorf("Failed to create new category.")
+	}
+
+	category.ID = insertedID.InsertedID.(primitive.ObjectID)
+
+	return *category, nil
+}
+
+func (categoryModel *CategoryModel) GetCategoriesByUserID(uid string, includeArchived bool) ([]Category, error) {
+	match := bson.M{"user_id": uid}
+	if !includeArchived {
+		match["is_archived"] = false
+	}
+
+	cursor, err := categoryModel.Collection.Find(context.TODO(), match, options.Find().SetSort(bson.D{
+		{Key: "created_at", Value: 1},
+		{Key: "_id", Value: 1},
+	}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find categories: ", err)
+
+		return nil, fmt.Errorf("Failed to find categories.")
+	}
+
+	var categories []Category
+	if err = cursor.All(context.TODO(), &categories); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode categories: ", err)
+
+		return nil, fmt.Errorf("Failed to decode categories.")
+	}
+
+	return categories, nil
+}
+
+func (categoryModel *CategoryModel) GetCategoryByID(categoryID string) (Category, error) {
+	objectCategoryID, _ := primitive.ObjectIDFromHex(categoryID)
+
+	result := categoryModel.Collection.FindOne(context.TODO(), bson.M{"_id": objectCategoryID})
+
+	var category Category
+	if err := result.Decode(&category); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"category_id": categoryID,
+		}).Error("failed to find category by category id: ", err)
+
+		return Category{}, fmt.Errorf("Failed to find category by category id.")
+	}
+
+	return category, nil
+}
+
+func (categoryModel *CategoryModel) HasSubCategories(categoryID string) bool {
+	count, err := categoryModel.Collection.CountDocuments(context.TODO(), bson.M{"parent_id": categoryID})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"category_id": categoryID,
+		}).Error("failed to count sub categories: ", err)
+
+		return true
+	}
+
+	return count > 0
+}
+
+// GetCategoryWithSubCategories returns the category and its sub categories, used to filter
+// transactions of a parent category.
+func (categoryModel *CategoryModel) GetCategoryWithSubCategories(category Category) ([]Category, error) {
+	cursor, err := categoryModel.Collection.Find(context.TODO(), bson.M{
+		"user_id":   category.UserID,
+		"parent_id": category.ID.Hex(),
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"category_id": category.ID,
+		}).Error("failed to find sub categories: ", err)
+
+		return nil, fmt.Errorf("Failed to find sub categories.")
+	}
+
+	var subCategories []Category
+	if err = cursor.All(context.TODO(), &subCategories); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"category_id": category.ID,
+		}).Error("failed to decode sub categories: ", err)
+
+		return nil, fmt.Errorf("Failed to decode sub categories.")
+	}
+
+	return append([]Category{category}, subCategories...), nil
+}
+
+// GetBuiltInCategoryIDs returns the IDs of user's built-in category and its
+// sub categories, it's empty when the user doesn't have categories.
+func (categoryModel *CategoryModel) GetBuiltInCategoryIDs(uid string, builtIn int64) ([]string, error) {
+	var category Category
+	if err := categoryModel.Collection.FindOne(context.TODO(), bson.M{
+		"user_id":  uid,
+		"built_in": builtIn,
+	}).Decode(&category); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return []string{}, nil
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"uid":      uid,
+			"built_in": builtIn,
+		}).Error("failed to find built-in category: ", err)
+
+		return nil, fmt.Errorf("Failed to find built-in category.")
+	}
+
+	categories, err := categoryModel.GetCategoryWithSubCategories(category)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs := make([]string, len(categories))
+	for i, category := range categories {
+		categoryIDs[i] = category.ID.Hex()
+	}
+
+	return categoryIDs, nil
+}
+
+// GetRootBuiltIn returns the built-in category transactions of this category
+// are counted as. Custom root categories are counted as Others.
+func (categoryModel *CategoryModel) GetRootBuiltIn(category Category) int64 {
+	if category.ParentID != nil {
+		if parent, err := categoryModel.GetCategoryByID(*category.ParentID); err == nil {
+			category = parent
+		}
+	}
+
+	if category.BuiltIn != nil {
+		return *category.BuiltIn
+	}
+
+	return Others
+}
+
+func (categoryModel *CategoryModel) UpdateCategory(data requests.CategoryUpdate, category Category) (Category, error) {
+	if data.Name != nil {
+		category.Name = *data.Name
+	}
+
+	if data.Color != nil {
+		category.Color = *data.Color
+	}
+
+	isParentChanged := false
+
+	if data.ParentID != nil {
+		category.ParentID = data.ParentID
+		isParentChanged = true
+	}
+
+	if data.ShouldDeleteParent != nil && *data.ShouldDeleteParent {
+		category.ParentID = nil
+		isParentChanged = true
+	}
+
+	if _, err := categoryModel.Collection.UpdateOne(context.TODO(), bson.M{
+		"_id": category.ID,
+	}, bson.M{"$set": category}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"category_id": data.ID,
+			"data":        data,
+		}).Error("failed to update category: ", err)
+
+		return Category{}, fmt.Errorf("Failed to update category.")
+	}
+
+	if isParentChanged {
+		categoryModel.updateTransactionBuiltIn(category)
+	}
+
+	return category, nil
+}
+
+// updateTransactionBuiltIn keeps `category` of transactions in sync after the
+// category is moved under another parent.
+func (categoryModel *CategoryModel) updateTransactionBuiltIn(category Category) {
+	match := bson.M{
+		"user_id":     category.UserID,
+		"category_id": category.ID.Hex(),
+	}
+	update := bson.M{"$set": bson.M{
+		"category": categoryModel.GetRootBuiltIn(category),
+	}}
+
+	if _, err := categoryModel.TransactionCollection.UpdateMany(context.TODO(), match, update); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"category_id": category.ID,
+		}).Error("failed to update transaction categories: ", err)
+	}
+
+	if _, err := categoryModel.RecurringTransactionCollection.UpdateMany(context.TODO(), match, update); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"category_id": category.ID,
+		}).Error("failed to update recurring transaction categories: ", err)
+	}
+}
+
+func (categoryModel *CategoryModel) ArchiveCategory(category Category, isArchived bool) (Category, error) {
+	category.IsArchived = isArchived
+
+	if _, err := categoryModel.Collection.UpdateOne(context.TODO(), bson.M{
+		"_id": category.ID,
+	}, bson.M{"$set": bson.M{
+		"is_archived": isArchived,
+	}}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"category_id": category.ID,
+			"is_archived": isArchived,
+		}).Error("failed to archive category: ", err)
+
+		return Category{}, fmt.Errorf("Failed to archive category.")
+	}
+
+	return category, nil
+}
+
+func (categoryModel *CategoryModel) DeleteAllCategoriesByUserID(uid string) error {
+	if _, err := categoryModel.Collection.DeleteMany(context.TODO(), bson.M{
+		"user_id": uid,
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to delete all categories by user id: ", err)
+
+		return fmt.Errorf("Failed to delete all categories by user id.")
+	}
+
+	return nil
+}
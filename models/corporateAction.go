@@ -0,0 +1,403 @@
+package models
+
+import (
+	"asset_backend/db"
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CorporateActionModel struct {
+	Collection      *mongo.Collection
+	AssetCollection *mongo.Collection
+}
+
+func NewCorporateActionModel(mongoDB *db.MongoDB) *CorporateActionModel {
+	return &CorporateActionModel{
+		Collection:      mongoDB.Database.Collection("corporate-actions"),
+		AssetCollection: mongoDB.Database.Collection("assets"),
+	}
+}
+
+/**
+* Corporate actions adjust the asset logs created before their effective date
+* while aggregating, the logs themselves aren't changed. Splits multiply the
+* amount and divide the price by the ratio, symbol changes replace to_asset.
+*
+* Symbols holds the symbol with the symbols that were renamed into it, so logs
+* of the old symbols are adjusted too. Renames are chained, the new symbol of
+* an earlier rename is updated when its new symbol is renamed again.
+**/
+type CorporateAction struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	ActionType    string             `bson:"action_type" json:"action_type"`
+	Symbol        string             `bson:"symbol" json:"symbol"`
+	NewSymbol     string             `bson:"new_symbol,omitempty" json:"new_symbol,omitempty"`
+	Symbols       []string           `bson:"symbols" json:"symbols"`
+	AssetType     string             `bson:"asset_type" json:"asset_type"`
+	AssetMarket   string             `bson:"asset_market" json:"asset_market"`
+	SplitRatio    float64            `bson:"split_ratio,omitempty" json:"split_ratio,omitempty"`
+	EffectiveDate time.Time          `bson:"effective_date" json:"effective_date"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
+
+const (
+	CorporateActionSplit        = "split"
+	CorporateActionSymbolChange = "symbol_change"
+)
+
+// User split logs this close to a split action of the same asset record the
+// same split, they're ignored so the split isn't applied twice.
+const corporateActionSplitLogWindow = 7 * 24 * time.Hour
+
+func (caModel *CorporateActionModel) CreateCorporateAction(data requests.CorporateActionCreate) (CorporateAction, error) {
+	corporateAction := CorporateAction{
+		ActionType:    data.ActionType,
+		Symbol:        data.Symbol,
+		AssetType:     data.AssetType,
+		AssetMarket:   data.AssetMarket,
+		EffectiveDate: data.EffectiveDate.UTC(),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	symbols, err := caModel.getCorporateActionSymbols(data)
+	if err != nil {
+		return CorporateAction{}, err
+	}
+
+	corporateAction.Symbols = symbols
+
+	if data.ActionType == CorporateActionSplit {
+		corporateAction.SplitRatio = data.SplitRatio
+	} else {
+		corporateAction.NewSymbol = data.NewSymbol
+
+		if _, err := caModel.Collection.UpdateMany(context.TODO(), bson.M{
+			"action_type":  CorporateActionSymbolChange,
+			"asset_type":   data.AssetType,
+			"asset_market": data.AssetMarket,
+			"new_symbol":   data.Symbol,
+		}, bson.M{"$set": bson.M{
+			"new_symbol": data.NewSymbol,
+		}}); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"data": data,
+			}).Error("failed to chain symbol changes: ", err)
+
+			return CorporateAction{}, fmt.Errorf("Failed to chain symbol changes.")
+		}
+
+		if _, err := caModel.Collection.UpdateMany(context.TODO(), bson.M{
+			"action_type":    CorporateActionSplit,
+			"asset_type":     data.AssetType,
+			"asset_market":   data.AssetMarket,
+			"symbol":         data.NewSymbol,
+			"effective_date": bson.M{"$gte": corporateAction.EffectiveDate},
+		}, bson.M{"$addToSet": bson.M{
+			"symbols": bson.M{"$each": symbols},
+		}}); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"data": data,
+			}).Error("failed to add symbols to splits: ", err)
+
+			return CorporateAction{}, fmt.Errorf("Failed to add symbols to splits.")
+		}
+	}
+
+	result, err := caModel.Collection.InsertOne(context.TODO(), corporateAction)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"data": data,
+		}).Error("failed to create new corporate action: ", err)
+
+		return CorporateAction{}, fmt.Errorf("Failed to create new corporate action.")
+	}
+
+	corporateAction.ID = result.InsertedID.(primitive.ObjectID)
+
+	return corporateAction, nil
+}
+
+func (caModel *CorporateActionModel) GetCorporateActions() ([]CorporateAction, error) {
+	cursor, err := caModel.Collection.Find(
+		context.TODO(),
+		bson.M{},
+		options.Find().SetSort(bson.M{"effective_date": -1}),
+	)
+	if err != nil {
+		logrus.Error("failed to find corporate actions: ", err)
+
+		return nil, fmt.Errorf("Failed to find corporate actions.")
+	}
+
+	var corporateActions []CorporateAction
+	if err = cursor.All(context.TODO(), &corporateActions); err != nil {
+		logrus.Error("failed to decode corporate actions: ", err)
+
+		return nil, fmt.Errorf("Failed to decode corporate actions.")
+	}
+
+	return corporateActions, nil
+}
+
+// GetCorporateActionPreview returns the users whose logs would be adjusted by
+// the action, amounts are calculated from the logs as they were recorded.
+func (caModel *CorporateActionModel) GetCorporateActionPreview(data requests.CorporateActionCreate) (responses.CorporateActionPreview, error) {
+	symbols, err := caModel.getCorporateActionSymbols(data)
+	if err != nil {
+		return responses.CorporateActionPreview{}, err
+	}
+
+	splitRatio := 1.0
+	if data.ActionType == CorporateActionSplit {
+		splitRatio = data.SplitRatio
+	}
+
+	match := bson.M{"$match": bson.M{
+		"to_asset":     bson.M{"$in": symbols},
+		"asset_type":   data.AssetType,
+		"asset_market": data.AssetMarket,
+		"created_at":   bson.M{"$lt": data.EffectiveDate.UTC()},
+	}}
+	group := bson.M{"$group": bson.M{
+		"_id": "$user_id",
+		"log_count": bson.M{
+			"$sum": 1,
+		},
+		"remaining_amount": getRemainingAmountSum(),
+	}}
+	addAdjustedField := bson.M{"$addFields": bson.M{
+		"adjusted_amount": bson.M{
+			"$multiply": bson.A{"$remaining_amount", splitRatio},
+		},
+	}}
+	sort := bson.M{"$sort": bson.M{
+		"log_count": -1,
+	}}
+
+	cursor, err := caModel.AssetCollection.Aggregate(context.TODO(), bson.A{
+		match, group, addAdjustedField, sort,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"data": data,
+		}).Error("failed to aggregate corporate action preview: ", err)
+
+		return responses.CorporateActionPreview{}, fmt.Errorf("Failed to aggregate corporate action preview.")
+	}
+
+	var users []responses.CorporateActionPreviewUser
+	if err = cursor.All(context.TODO(), &users); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"data": data,
+		}).Error("failed to decode corporate action preview: ", err)
+
+		return responses.CorporateActionPreview{}, fmt.Errorf("Failed to decode corporate action preview.")
+	}
+
+	preview := responses.CorporateActionPreview{
+		UserCount: len(users),
+		Users:     users,
+	}
+
+	for _, user := range users {
+		preview.LogCount += user.LogCount
+	}
+
+	return preview, nil
+}
+
+func (caModel *CorporateActionModel) DeleteCorporateActionByID(id string) (bool, error) {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+
+	result, err := caModel.Collection.DeleteOne(context.TODO(), bson.M{
+		"_id": objectID,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"id": id,
+		}).Error("failed to delete corporate action: ", err)
+
+		return false, fmt.Errorf("Failed to delete corporate action.")
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// getFormerSymbols returns the symbols that were renamed into symbol, so logs
+// of a holding can be matched before corporate actions are applied.
+func getFormerSymbols(collection *mongo.Collection, symbol, market string) ([]string, error) {
+	formerSymbols, err := collection.Distinct(context.TODO(), "symbols", bson.M{
+		"action_type":  CorporateActionSymbolChange,
+		"asset_market": market,
+		"new_symbol":   symbol,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"symbol": symbol,
+			"market": market,
+		}).Error("failed to find former symbols: ", err)
+
+		return nil, fmt.Errorf("Failed to find former symbols.")
+	}
+
+	symbols := []string{symbol}
+	for _, formerSymbol := range formerSymbols {
+		if formerSymbol, ok := formerSymbol.(string); ok && formerSymbol != symbol {
+			symbols = append(symbols, formerSymbol)
+		}
+	}
+
+	return symbols, nil
+}
+
+// getCorporateActionSymbols returns the symbol with the symbols renamed into
+// it until the effective date.
+func (caModel *CorporateActionModel) getCorporateActionSymbols(data requests.CorporateActionCreate) ([]string, error) {
+	formerSymbols, err := caModel.Collection.Distinct(context.TODO(), "symbol", bson.M{
+		"action_type":    CorporateActionSymbolChange,
+		"asset_type":     data.AssetType,
+		"asset_market":   data.AssetMarket,
+		"new_symbol":     data.Symbol,
+		"effective_date": bson.M{"$lte": data.EffectiveDate.UTC()},
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"data": data,
+		}).Error("failed to find former symbols: ", err)
+
+		return nil, fmt.Errorf("Failed to find former symbols.")
+	}
+
+	symbols := []string{data.Symbol}
+	for _, formerSymbol := range formerSymbols {
+		if symbol, ok := formerSymbol.(string); ok && symbol != data.Symbol {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	return symbols, nil
+}
+
+// getCorporateActionStages returns the stages that apply corporate actions to
+// asset logs, they have to come before grouping. Split logs of users that are
+// covered by a split action get zero amount.
+func getCorporateActionStages() (bson.M, bson.M) {
+	splitLogWindow := corporateActionSplitLogWindow.Milliseconds()
+
+	lookup := bson.M{"$lookup": bson.M{
+		"from": "corporate-actions",
+		"let": bson.M{
+			"to_asset":   "$to_asset",
+			"asset_type": "$asset_type",
+			"market":     "$asset_market",
+			"type":       "$type",
+			"created_at": "$created_at",
+		},
+		"pipeline": bson.A{
+			bson.M{
+				"$match": bson.M{
+					"$expr": bson.M{
+						"$and": bson.A{
+							bson.M{"$eq": bson.A{"$asset_type", "$$asset_type"}},
+							bson.M{"$eq": bson.A{"$asset_market", "$$market"}},
+							bson.M{"$in": bson.A{"$$to_asset", "$symbols"}},
+							bson.M{"$or": bson.A{
+								bson.M{"$gt": bson.A{"$effective_date", "$$created_at"}},
+								bson.M{"$and": bson.A{
+									bson.M{"$eq": bson.A{"$$type", AssetLogSplit}},
+									bson.M{"$eq": bson.A{"$action_type", CorporateActionSplit}},
+								}},
+							}},
+						},
+					},
+				},
+			},
+			bson.M{"$sort": bson.M{
+				"effective_date": 1,
+			}},
+		},
+		"as": "corporate_actions",
+	}}
+
+	adjustingActions := bson.M{"$filter": bson.M{
+		"input": "$corporate_actions",
+		"cond":  bson.M{"$gt": bson.A{"$$this.effective_date", "$created_at"}},
+	}}
+	splitRatio := bson.M{
+		"$reduce": bson.M{
+			"input": bson.M{"$filter": bson.M{
+				"input": adjustingActions,
+				"cond":  bson.M{"$eq": bson.A{"$$this.action_type", CorporateActionSplit}},
+			}},
+			"initialValue": 1,
+			"in":           bson.M{"$multiply": bson.A{"$$value", "$$this.split_ratio"}},
+		},
+	}
+	newSymbols := bson.M{"$map": bson.M{
+		"input": bson.M{"$filter": bson.M{
+			"input": adjustingActions,
+			"cond":  bson.M{"$eq": bson.A{"$$this.action_type", CorporateActionSymbolChange}},
+		}},
+		"in": "$$this.new_symbol",
+	}}
+	isCoveredSplitLog := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$type", AssetLogSplit}},
+		bson.M{"$gt": bson.A{
+			bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": "$corporate_actions",
+				"cond": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$$this.action_type", CorporateActionSplit}},
+					bson.M{"$lte": bson.A{
+						bson.M{"$abs": bson.M{"$subtract": bson.A{"$$this.effective_date", "$created_at"}}},
+						splitLogWindow,
+					}},
+				}},
+			}}},
+			0,
+		}},
+	}}
+
+	addAdjustedFields := bson.M{"$addFields": bson.M{
+		"to_asset": bson.M{
+			"$ifNull": bson.A{
+				bson.M{"$arrayElemAt": bson.A{newSymbols, -1}},
+				"$to_asset",
+			},
+		},
+		"amount": bson.M{
+			"$cond": bson.A{
+				isCoveredSplitLog,
+				0,
+				bson.M{"$multiply": bson.A{"$amount", splitRatio}},
+			},
+		},
+		"price": bson.M{
+			"$divide": bson.A{"$price", splitRatio},
+		},
+	}}
+
+	return lookup, addAdjustedFields
+}
+
+// aggregateAdjustedAssetLogs finds the user's asset logs with corporate actions
+// applied, sorted by created_at.
+func aggregateAdjustedAssetLogs(collection *mongo.Collection, uid string, filter bson.M) (*mongo.Cursor, error) {
+	corporateActionLookup, addAdjustedFields := getCorporateActionStages()
+
+	return collection.Aggregate(context.TODO(), bson.A{
+		bson.M{"$match": bson.M{"user_id": uid}},
+		corporateActionLookup,
+		addAdjustedFields,
+		bson.M{"$match": filter},
+		bson.M{"$sort": bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+}
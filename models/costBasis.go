@@ -0,0 +1,274 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Cost Basis Methods
+const (
+	CostBasisFIFO    = "fifo"
+	CostBasisLIFO    = "lifo"
+	CostBasisAverage = "average"
+)
+
+type costBasisKey struct {
+	ToAsset   string
+	FromAsset string
+}
+
+// Converted costs are in the currency of costBasisExchangeRate, they're equal
+// to the costs when there is no exchange rate.
+type costBasisLot struct {
+	Amount            float64
+	UnitCost          float64
+	ConvertedUnitCost float64
+	ExchangeRate      float64
+	AcquiredAt        time.Time
+}
+
+type costBasisDisposal struct {
+	Amount                float64
+	Proceeds              float64
+	CostBasis             float64
+	ConvertedProceeds     float64
+	ConvertedCostBasis    float64
+	ProceedsExchangeRate  float64
+	CostBasisExchangeRate float64
+	AcquiredAt            time.Time
+	DisposedAt            time.Time
+}
+
+// costBasisExchangeRate returns the exchange rate of the log's currency on the
+// day of the log.
+type costBasisExchangeRate func(assetLog Asset) (float64, error)
+
+type costBasisResult struct {
+	AssetType       string
+	AssetMarket     string
+	RemainingAmount float64
+	CostBasis       float64
+	RealizedPL      float64
+	Income          float64
+	Fees            float64
+	Disposals       []costBasisDisposal
+}
+
+// getCostBasisByUserID replays the user's asset logs in chronological order
+// and returns the lot accounting result of every to_asset/from_asset pair.
+// Costs and proceeds are also converted when exchangeRate isn't nil.
+func (assetModel *AssetModel) getCostBasisByUserID(uid, method string, filter bson.M, exchangeRate costBasisExchangeRate) (map[costBasisKey]costBasisResult, error) {
+	filter["user_id"] = uid
+	method = costBasisMethodOrDefault(method)
+
+	cursor, err := aggregateAdjustedAssetLogs(assetModel.Collection, uid, filter)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":    uid,
+			"method": method,
+		}).Error("failed to find asset logs for cost basis: ", err)
+
+		return nil, fmt.Errorf("Failed to find asset logs for cost basis.")
+	}
+
+	var assetLogs []Asset
+	if err = cursor.All(context.TODO(), &assetLogs); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":    uid,
+			"method": method,
+		}).Error("failed to decode asset logs for cost basis: ", err)
+
+		return nil, fmt.Errorf("Failed to decode asset logs for cost basis.")
+	}
+
+	groupedLogs := make(map[costBasisKey][]Asset)
+	for _, assetLog := range assetLogs {
+		key := costBasisKey{ToAsset: assetLog.ToAsset, FromAsset: assetLog.FromAsset}
+		groupedLogs[key] = append(groupedLogs[key], assetLog)
+	}
+
+	results := make(map[costBasisKey]costBasisResult, len(groupedLogs))
+	for key, logs := range groupedLogs {
+		result, err := calculateCostBasis(logs, method, exchangeRate)
+		if err != nil {
+			return nil, err
+		}
+
+		result.AssetType = logs[0].AssetType
+		result.AssetMarket = logs[0].AssetMarket
+
+		results[key] = result
+	}
+
+	return results, nil
+}
+
+// calculateCostBasis expects logs sorted by created_at. Average cost keeps the
+// lots for their acquisition dates but prices every open lot at the running average.
+func calculateCostBasis(logs []Asset, method string, exchangeRate costBasisExchangeRate) (costBasisResult, error) {
+	var (
+		lots   []costBasisLot
+		result costBasisResult
+	)
+
+	for _, assetLog := range logs {
+		rate := 1.0
+		if exchangeRate != nil && assetLog.Type != AssetLogDividend && assetLog.Type != AssetLogSplit {
+			var err error
+			if rate, err = exchangeRate(assetLog); err != nil {
+				return costBasisResult{}, err
+			}
+		}
+
+		switch assetLog.Type {
+		case AssetLogBuy, AssetLogStaking, AssetLogAirdrop:
+			// Rewards are income when they're received, so their value is the cost basis.
+			lots = append(lots, costBasisLot{
+				Amount:            assetLog.Amount,
+				UnitCost:          assetLog.Price,
+				ConvertedUnitCost: assetLog.Price * rate,
+				ExchangeRate:      rate,
+				AcquiredAt:        assetLog.CreatedAt,
+			})
+
+			if assetLog.Type != AssetLogBuy {
+				result.Income += assetLog.CurrencyValue
+			}
+
+			if method == CostBasisAverage {
+				averageLotCost(lots)
+			}
+		case AssetLogDividend:
+			result.Income += assetLog.CurrencyValue
+		case AssetLogSplit:
+			if assetLog.SplitRatio <= 0 {
+				continue
+			}
+
+			for i := range lots {
+				lots[i].Amount *= assetLog.SplitRatio
+				lots[i].UnitCost /= assetLog.SplitRatio
+				lots[i].ConvertedUnitCost /= assetLog.SplitRatio
+			}
+		case AssetLogFee:
+			result.Fees += assetLog.CurrencyValue
+			addFeeToLots(lots, assetLog.CurrencyValue, rate, &result)
+		case AssetLogSell:
+			var disposals []costBasisDisposal
+			lots, disposals = consumeLots(lots, assetLog, method, rate)
+
+			for _, disposal := range disposals {
+				result.RealizedPL += disposal.Proceeds - disposal.CostBasis
+			}
+
+			result.Disposals = append(result.Disposals, disposals...)
+		}
+	}
+
+	for _, lot := range lots {
+		result.RemainingAmount += lot.Amount
+		result.CostBasis += lot.Amount * lot.UnitCost
+	}
+
+	return result, nil
+}
+
+// consumeLots removes the sold amount from open lots. Sold amount exceeding the
+// open lots has no known cost basis and is ignored, same as remaining_amount.
+// Proceeds are converted at the rate of the sale and cost basis at the rates of
+// the lot.
+func consumeLots(lots []costBasisLot, sell Asset, method string, rate float64) ([]costBasisLot, []costBasisDisposal) {
+	var (
+		disposals []costBasisDisposal
+		remaining = sell.Amount
+	)
+
+	for remaining > 0 && len(lots) > 0 {
+		index := 0
+		if method == CostBasisLIFO {
+			index = len(lots) - 1
+		}
+
+		lot := &lots[index]
+
+		consumed := remaining
+		if lot.Amount < consumed {
+			consumed = lot.Amount
+		}
+
+		costBasisRate := lot.ExchangeRate
+		if lot.UnitCost != 0 {
+			costBasisRate = lot.ConvertedUnitCost / lot.UnitCost
+		}
+
+		disposals = append(disposals, costBasisDisposal{
+			Amount:                consumed,
+			Proceeds:              consumed * sell.Price,
+			CostBasis:             consumed * lot.UnitCost,
+			ConvertedProceeds:     consumed * sell.Price * rate,
+			ConvertedCostBasis:    consumed * lot.ConvertedUnitCost,
+			ProceedsExchangeRate:  rate,
+			CostBasisExchangeRate: costBasisRate,
+			AcquiredAt:            lot.AcquiredAt,
+			DisposedAt:            sell.CreatedAt,
+		})
+
+		lot.Amount -= consumed
+		remaining -= consumed
+
+		if lot.Amount <= 0 {
+			lots = append(lots[:index], lots[index+1:]...)
+		}
+	}
+
+	return lots, disposals
+}
+
+// addFeeToLots adds the fee to the cost of open lots by their amounts, fees
+// without open lots reduce the realized p/l.
+func addFeeToLots(lots []costBasisLot, fee, rate float64, result *costBasisResult) {
+	var totalAmount float64
+	for _, lot := range lots {
+		totalAmount += lot.Amount
+	}
+
+	if totalAmount <= 0 {
+		result.RealizedPL -= fee
+		return
+	}
+
+	for i := range lots {
+		lots[i].UnitCost += fee / totalAmount
+		lots[i].ConvertedUnitCost += fee * rate / totalAmount
+	}
+}
+
+func averageLotCost(lots []costBasisLot) {
+	var totalAmount, totalCost, totalConvertedCost float64
+	for _, lot := range lots {
+		totalAmount += lot.Amount
+		totalCost += lot.Amount * lot.UnitCost
+		totalConvertedCost += lot.Amount * lot.ConvertedUnitCost
+	}
+
+	if totalAmount == 0 {
+		return
+	}
+
+	for i := range lots {
+		lots[i].UnitCost = totalCost / totalAmount
+		lots[i].ConvertedUnitCost = totalConvertedCost / totalAmount
+	}
+}
+
+func costBasisMethodOrDefault(method string) string {
+	if method == CostBasisLIFO || method == CostBasisAverage {
+		return method
+	}
+
+	return CostBasisFIFO
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"asset_backend/db"
+	"asset_backend/requests"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CustomAssetValuationModel struct {
+	Collection *mongo.Collection
+}
+
+func NewCustomAssetValuationModel(mongoDB *db.MongoDB) *CustomAssetValuationModel {
+	return &CustomAssetValuationModel{
+		Collection: mongoDB.Database.Collection("custom-asset-valuations"),
+	}
+}
+
+/**
+* Custom assets aren't in the investings catalog, e.g. real estate or a savings
+* deposit. Users supply the unit price of every custom holding themselves and
+* it's used like the investing price. The valuation is created with the price
+* of the first priced log and only changes when the user updates it.
+**/
+type CustomAssetValuation struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	ToAsset   string             `bson:"to_asset" json:"to_asset"`
+	FromAsset string             `bson:"from_asset" json:"from_asset"`
+	Name      string             `bson:"name" json:"name"`
+	Price     float64            `bson:"price" json:"price"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+const (
+	AssetTypeCustom   = "custom"
+	customAssetMarket = "custom"
+)
+
+func (customModel *CustomAssetValuationModel) GetCustomAssetValuationsByUserID(uid string) ([]CustomAssetValuation, error) {
+	cursor, err := customModel.Collection.Find(context.TODO(), bson.M{
+		"user_id": uid,
+	}, options.Find().SetSort(bson.M{"to_asset": 1}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find custom asset valuations: ", err)
+
+		return nil, fmt.Errorf("Failed to find custom asset valuations.")
+	}
+
+	var valuations []CustomAssetValuation
+	if err = cursor.All(context.TODO(), &valuations); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode custom asset valuations: ", err)
+
+		return nil, fmt.Errorf("Failed to decode custom asset valuations.")
+	}
+
+	return valuations, nil
+}
+
+func (customModel *CustomAssetValuationModel) UpdateCustomAssetValuation(uid string, data requests.CustomAssetValuationUpdate) (CustomAssetValuation, error) {
+	valuation := CustomAssetValuation{
+		UserID:    uid,
+		ToAsset:   data.ToAsset,
+		FromAsset: strings.ToUpper(data.FromAsset),
+		Name:      data.ToAsset,
+		Price:     data.Price,
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	set := bson.M{
+		"price":      valuation.Price,
+		"updated_at": valuation.UpdatedAt,
+	}
+
+	if data.Name != nil {
+		valuation.Name = *data.Name
+		set["name"] = valuation.Name
+	}
+
+	update := bson.M{"$set": set}
+	if data.Name == nil {
+		update["$setOnInsert"] = bson.M{"name": valuation.Name}
+	}
+
+	if err := customModel.Collection.FindOneAndUpdate(context.TODO(), bson.M{
+		"user_id":    uid,
+		"to_asset":   valuation.ToAsset,
+		"from_asset": valuation.FromAsset,
+	}, update, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&valuation); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":  uid,
+			"data": data,
+		}).Error("failed to update custom asset valuation: ", err)
+
+		return CustomAssetValuation{}, fmt.Errorf("Failed to update custom asset valuation.")
+	}
+
+	return valuation, nil
+}
+
+func (customModel *CustomAssetValuationModel) DeleteCustomAssetValuationsByUserID(uid string) error {
+	if _, err := customModel.Collection.DeleteMany(context.TODO(), bson.M{
+		"user_id": uid,
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to delete all custom asset valuations by user id: ", err)
+
+		return fmt.Errorf("Failed to delete all custom asset valuations by user id.")
+	}
+
+	return nil
+}
+
+// createCustomAssetValuation sets the first priced log's price as valuation,
+// existing valuations aren't changed. Logs without a price, e.g. splits,
+// don't create one.
+func createCustomAssetValuation(collection *mongo.Collection, uid, toAsset, fromAsset string, price float64) error {
+	if price <= 0 {
+		return nil
+	}
+
+	fromAsset = strings.ToUpper(fromAsset)
+
+	if _, err := collection.UpdateOne(context.TODO(), bson.M{
+		"user_id":    uid,
+		"to_asset":   toAsset,
+		"from_asset": fromAsset,
+	}, bson.M{"$setOnInsert": bson.M{
+		"name":       toAsset,
+		"price":      price,
+		"updated_at": time.Now().UTC(),
+	}}, options.Update().SetUpsert(true)); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"to_asset":   toAsset,
+			"from_asset": fromAsset,
+		}).Error("failed to create custom asset valuation: ", err)
+
+		return fmt.Errorf("Failed to create custom asset valuation.")
+	}
+
+	return nil
+}
+
+// getInvestingLookup finds the investing of the grouped asset, custom assets
+// use the user's valuation instead. userID is an aggregation expression.
+func getInvestingLookup(userID interface{}) bson.M {
+	return bson.M{"$lookup": bson.M{
+		"from": "investings",
+		"let": bson.M{
+			"user_id":    userID,
+			"to_asset":   "$_id.to_asset",
+			"from_asset": "$_id.from_asset",
+			"asset_type": "$asset_type",
+			"market":     "$asset_market",
+		},
+		"pipeline": bson.A{
+			bson.M{
+				"$match": bson.M{
+					"$expr": bson.M{
+						"$and": bson.A{
+							bson.M{"$eq": bson.A{"$_id.symbol", "$$to_asset"}},
+							bson.M{"$eq": bson.A{"$_id.type", "$$asset_type"}},
+							bson.M{"$eq": bson.A{"$_id.market", "$$market"}},
+						},
+					},
+				},
+			},
+			bson.M{"$unionWith": bson.M{
+				"coll": "custom-asset-valuations",
+				"pipeline": bson.A{
+					bson.M{
+						"$match": bson.M{
+							"$expr": bson.M{
+								"$and": bson.A{
+									bson.M{"$eq": bson.A{"$$asset_type", AssetTypeCustom}},
+									bson.M{"$eq": bson.A{"$user_id", "$$user_id"}},
+									bson.M{"$eq": bson.A{"$to_asset", "$$to_asset"}},
+									bson.M{"$eq": bson.A{"$from_asset", "$$from_asset"}},
+								},
+							},
+						},
+					},
+				},
+			}},
+			bson.M{"$limit": 1},
+		},
+		"as": "investing",
+	}}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"asset_backend/db"
+	"asset_backend/requests"
 	"asset_backend/responses"
 	"context"
 	"fmt"
@@ -15,47 +16,55 @@ import (
 )
 
 type DailyAssetStatsModel struct {
-	Collection      *mongo.Collection
-	AssetCollection *mongo.Collection
+	Collection                *mongo.Collection
+	AssetCollection           *mongo.Collection
+	HoldingCollection         *mongo.Collection
+	AssetTypeCollection       *mongo.Collection
+	UserCollection            *mongo.Collection
+	ExchangeCollection        *mongo.Collection
+	ExchangeHistoryCollection *mongo.Collection
+	CorporateActionCollection *mongo.Collection
 }
 
 func NewDailyAssetStatsModel(mongoDB *db.MongoDB) *DailyAssetStatsModel {
 	return &DailyAssetStatsModel{
-		Collection:      mongoDB.Database.Collection("daily-asset-stats"),
-		AssetCollection: mongoDB.Database.Collection("assets"),
+		Collection:                mongoDB.Database.Collection("daily-asset-stats"),
+		AssetCollection:           mongoDB.Database.Collection("assets"),
+		HoldingCollection:         mongoDB.Database.Collection("daily-asset-holding-stats"),
+		AssetTypeCollection:       mongoDB.Database.Collection("daily-asset-type-stats"),
+		UserCollection:            mongoDB.Database.Collection("users"),
+		ExchangeCollection:        mongoDB.Database.Collection("exchanges"),
+		ExchangeHistoryCollection: mongoDB.Database.Collection("exchange-history"),
+		CorporateActionCollection: mongoDB.Database.Collection("corporate-actions"),
 	}
 }
 
-func (dasModel *DailyAssetStatsModel) GetAssetStatsByUserID(uid string, interval string) (responses.DailyAssetStats, error) {
+// Custom ranges longer than these are downsampled to weekly and monthly buckets.
+const (
+	dailyStatsDailyBucketDays  = 90
+	dailyStatsWeeklyBucketDays = 730
+)
+
+var dailyStatsBucketUnits = map[string]string{
+	"daily":   "day",
+	"weekly":  "week",
+	"monthly": "month",
+}
+
+func (dasModel *DailyAssetStatsModel) GetAssetStatsByUserID(uid string, data requests.DailyAssetStatsInterval) (responses.DailyAssetStats, error) {
 	objectUID, _ := primitive.ObjectIDFromHex(uid)
 
-	var intervalDate time.Time
+	var (
+		interval = data.Interval
+		bucket   = getDailyStatsBucket(data)
+	)
 
-	switch interval {
-	case "weekly":
-		intervalDate = time.Now().AddDate(0, 0, -7)
-	case "monthly":
-		intervalDate = time.Now().AddDate(0, -1, 0)
-	}
+	match := bson.M{"$match": bson.M{
+		"user_id": objectUID,
+	}}
 
-	var match bson.M
-	if interval == "yearly" {
-		match = bson.M{"$match": bson.M{
-			"user_id": objectUID,
-			"$expr": bson.M{
-				"$eq": bson.A{
-					bson.M{"$year": "$created_at"},
-					time.Now().Year(),
-				},
-			},
-		}}
-	} else {
-		match = bson.M{"$match": bson.M{
-			"user_id": objectUID,
-			"created_at": bson.M{
-				"$gte": intervalDate,
-			},
-		}}
+	if dateFilter := getDailyStatsDateFilter(interval, data.StartDate, data.EndDate); dateFilter != nil {
+		match["$match"].(bson.M)["created_at"] = dateFilter
 	}
 
 	userLookup := bson.M{"$lookup": bson.M{
@@ -69,27 +78,7 @@ func (dasModel *DailyAssetStatsModel) GetAssetStatsByUserID(uid string, interval
 		"includeArrayIndex":          "index",
 		"preserveNullAndEmptyArrays": true,
 	}}
-	exchangeLookup := bson.M{"$lookup": bson.M{
-		"from": "exchanges",
-		"let": bson.M{
-			"user_currency": "$user.currency",
-			"stat_currency": "$currency",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$and": bson.A{
-							bson.M{"$ne": bson.A{"$$stat_currency", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$to_exchange", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$from_exchange", "$$stat_currency"}},
-						},
-					},
-				},
-			},
-		},
-		"as": "user_exchange_rate",
-	}}
+	exchangeLookup := getExchangeRateLookup("$currency", "$user.currency", "$created_at", "user_exchange_rate")
 	unwindExchange := bson.M{"$unwind": bson.M{
 		"path":                       "$user_exchange_rate",
 		"includeArrayIndex":          "index",
@@ -101,7 +90,7 @@ func (dasModel *DailyAssetStatsModel) GetAssetStatsByUserID(uid string, interval
 		yearlyGroup bson.M
 	)
 
-	if interval == "yearly" {
+	if interval == "yearly" && bucket == "" {
 		project = bson.M{"$project": bson.M{
 			"currency": "$user.currency",
 			"total_assets": bson.M{
@@ -185,7 +174,50 @@ func (dasModel *DailyAssetStatsModel) GetAssetStatsByUserID(uid string, interval
 	}}
 
 	var aggregationList bson.A
-	if interval == "yearly" {
+	if bucket != "" {
+		bucketGroup := bson.M{"$group": bson.M{
+			"_id": bson.M{
+				"$dateTrunc": bson.M{
+					"date":        "$created_at",
+					"unit":        dailyStatsBucketUnits[bucket],
+					"startOfWeek": "monday",
+				},
+			},
+			"currency": bson.M{
+				"$last": "$currency",
+			},
+			"open": bson.M{
+				"$first": "$total_assets",
+			},
+			"close": bson.M{
+				"$last": "$total_assets",
+			},
+			"min": bson.M{
+				"$min": "$total_assets",
+			},
+			"max": bson.M{
+				"$max": "$total_assets",
+			},
+			"total_assets": bson.M{
+				"$last": "$total_assets",
+			},
+			"total_p/l": bson.M{
+				"$last": "$total_p/l",
+			},
+		}}
+		bucketProject := bson.M{"$addFields": bson.M{
+			"created_at": "$_id",
+		}}
+
+		for _, field := range []string{"open", "close", "min", "max"} {
+			arrayGroup["$group"].(bson.M)[field] = bson.M{"$push": "$" + field}
+		}
+
+		aggregationList = bson.A{
+			match, userLookup, unwindUser, exchangeLookup, unwindExchange, project, sort,
+			bucketGroup, bucketProject, sort, arrayGroup,
+		}
+	} else if interval == "yearly" {
 		aggregationList = bson.A{
 			match, userLookup, unwindUser, exchangeLookup, unwindExchange, project, yearlyGroup, sort, arrayGroup,
 		}
@@ -247,15 +279,9 @@ func (dasModel *DailyAssetStatsModel) CalculateDailyAssetStats() {
 				},
 			},
 		},
-		"remaining_amount": bson.M{
-			"$sum": bson.M{
-				"$cond": bson.A{
-					bson.M{"$eq": bson.A{"$type", "buy"}},
-					"$amount",
-					bson.M{"$multiply": bson.A{"$amount", -1}},
-				},
-			},
-		},
+		"remaining_amount": getRemainingAmountSum(),
+		"total_income":     getAssetLogValueSum(assetLogIncomeTypes),
+		"total_fees":       getAssetLogValueSum(bson.A{AssetLogFee}),
 		"asset_type": bson.M{
 			"$first": "$asset_type",
 		},
@@ -266,28 +292,7 @@ func (dasModel *DailyAssetStatsModel) CalculateDailyAssetStats() {
 			"$first": "$user_id",
 		},
 	}}
-	lookup := bson.M{"$lookup": bson.M{
-		"from": "investings",
-		"let": bson.M{
-			"to_asset":   "$_id.to_asset",
-			"asset_type": "$asset_type",
-			"market":     "$asset_market",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$and": bson.A{
-							bson.M{"$eq": bson.A{"$_id.symbol", "$$to_asset"}},
-							bson.M{"$eq": bson.A{"$_id.type", "$$asset_type"}},
-							bson.M{"$eq": bson.A{"$_id.market", "$$market"}},
-						},
-					},
-				},
-			},
-		},
-		"as": "investing",
-	}}
+	lookup := getInvestingLookup("$user_id")
 	unwindInvesting := bson.M{"$unwind": bson.M{
 		"path":                       "$investing",
 		"includeArrayIndex":          "index",
@@ -354,18 +359,27 @@ func (dasModel *DailyAssetStatsModel) CalculateDailyAssetStats() {
 		"user_id": bson.M{
 			"$toObjectId": "$user_id",
 		},
-		"total_bought": true,
-		"total_sold":   true,
-		"asset_type":   true,
+		"to_asset":         "$_id.to_asset",
+		"from_asset":       "$_id.from_asset",
+		"remaining_amount": true,
+		"investing_price":  true,
+		"total_bought":     true,
+		"total_sold":       true,
+		"total_income":     true,
+		"total_fees":       true,
+		"asset_type":       true,
 		"current_total_value": bson.M{
 			"$multiply": bson.A{"$remaining_amount", "$investing_price"},
 		},
 		"p/l": bson.M{
 			"$subtract": bson.A{
-				"$total_bought",
+				bson.M{
+					"$sum": bson.A{"$total_bought", "$total_fees"},
+				},
 				bson.M{
 					"$sum": bson.A{
 						"$total_sold",
+						"$total_income",
 						bson.M{
 							"$multiply": bson.A{"$remaining_amount", "$investing_price"},
 						},
@@ -416,8 +430,19 @@ func (dasModel *DailyAssetStatsModel) CalculateDailyAssetStats() {
 			"$toString": "$user_id",
 		},
 		"asset_type": true,
+		"to_asset":   true,
+		"from_asset": true,
+		"amount":     "$remaining_amount",
 		"created_at": time.Now().UTC(),
 		"currency":   "$user.currency",
+		"price": bson.M{
+			"$ifNull": bson.A{
+				bson.M{
+					"$multiply": bson.A{"$investing_price", "$user_exchange_rate.exchange_rate"},
+				},
+				"$investing_price",
+			},
+		},
 		"total_assets": bson.M{
 			"$ifNull": bson.A{
 				bson.M{
@@ -475,8 +500,18 @@ func (dasModel *DailyAssetStatsModel) CalculateDailyAssetStats() {
 		},
 	}}
 
+	corporateActionLookup, addAdjustedFields := getCorporateActionStages()
+
+	holdingStages := bson.A{
+		corporateActionLookup, addAdjustedFields, group, lookup, unwindInvesting, exchangeLookup, unwindExchange,
+		addInvestingField, project, userLookup, unwindUser, userCurrencyExchangeLookup,
+		unwindUserCurrency, userCurrencyProject,
+	}
+
+	dasModel.calculateDailyHoldingStats(holdingStages)
+
 	cursor, err := dasModel.AssetCollection.Aggregate(context.TODO(), bson.A{
-		group, lookup, unwindInvesting, exchangeLookup, unwindExchange,
+		corporateActionLookup, addAdjustedFields, group, lookup, unwindInvesting, exchangeLookup, unwindExchange,
 		addInvestingField, project, userLookup, unwindUser, userCurrencyExchangeLookup,
 		unwindUserCurrency, userCurrencyProject, assetGroup, statsGroup,
 	})
@@ -494,20 +529,205 @@ func (dasModel *DailyAssetStatsModel) CalculateDailyAssetStats() {
 		return
 	}
 
-	insertDASList := make([]interface{}, len(dailyAssetStats))
-	for i, dailyAssetStat := range dailyAssetStats {
-		insertDASList[i] = dailyAssetStat
+	dasModel.upsertDailyAssetStats(dailyAssetStats)
+}
+
+func (dasModel *DailyAssetStatsModel) GetHoldingStatsByUserID(uid string, data requests.DailyAssetHoldingStats) (responses.DailyAssetHoldingStats, error) {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	match := bson.M{
+		"user_id":    objectUID,
+		"to_asset":   data.ToAsset,
+		"from_asset": data.FromAsset,
 	}
 
-	if _, err := dasModel.Collection.InsertMany(
+	if dateFilter := getDailyStatsDateFilter(data.Interval, data.StartDate, data.EndDate); dateFilter != nil {
+		match["created_at"] = dateFilter
+	}
+
+	cursor, err := dasModel.HoldingCollection.Aggregate(
+		context.TODO(),
+		getDailyStatsSeriesPipeline(
+			match, getSeriesStatsBucket(data.Interval, data.StartDate, data.EndDate),
+			[]string{"price", "total_assets", "total_p/l"}, []string{"amount"},
+		),
+	)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"to_asset":   data.ToAsset,
+			"from_asset": data.FromAsset,
+			"interval":   data.Interval,
+		}).Error("failed to aggregate daily holding stats: ", err)
+
+		return responses.DailyAssetHoldingStats{}, fmt.Errorf("Failed to aggregate daily holding stats.")
+	}
+
+	var dailyHoldingStats []responses.DailyAssetHoldingStats
+	if err = cursor.All(context.TODO(), &dailyHoldingStats); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"to_asset":   data.ToAsset,
+			"from_asset": data.FromAsset,
+			"interval":   data.Interval,
+		}).Error("failed to decode daily holding stats: ", err)
+
+		return responses.DailyAssetHoldingStats{}, fmt.Errorf("Failed to decode daily holding stats.")
+	}
+
+	if len(dailyHoldingStats) > 0 {
+		return dailyHoldingStats[0], nil
+	}
+
+	return responses.DailyAssetHoldingStats{}, nil
+}
+
+func (dasModel *DailyAssetStatsModel) GetAssetTypeStatsByUserID(uid string, data requests.DailyAssetTypeStats) (responses.DailyAssetTypeStats, error) {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	match := bson.M{
+		"user_id":    objectUID,
+		"asset_type": data.AssetType,
+	}
+
+	if dateFilter := getDailyStatsDateFilter(data.Interval, data.StartDate, data.EndDate); dateFilter != nil {
+		match["created_at"] = dateFilter
+	}
+
+	cursor, err := dasModel.AssetTypeCollection.Aggregate(
 		context.TODO(),
-		insertDASList,
-		options.InsertMany().SetOrdered(false),
-	); err != nil {
+		getDailyStatsSeriesPipeline(
+			match, getSeriesStatsBucket(data.Interval, data.StartDate, data.EndDate),
+			[]string{"total_assets", "total_p/l"}, nil,
+		),
+	)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"asset_type": data.AssetType,
+			"interval":   data.Interval,
+		}).Error("failed to aggregate daily asset type stats: ", err)
+
+		return responses.DailyAssetTypeStats{}, fmt.Errorf("Failed to aggregate daily asset type stats.")
+	}
+
+	var dailyAssetTypeStats []responses.DailyAssetTypeStats
+	if err = cursor.All(context.TODO(), &dailyAssetTypeStats); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"asset_type": data.AssetType,
+			"interval":   data.Interval,
+		}).Error("failed to decode daily asset type stats: ", err)
+
+		return responses.DailyAssetTypeStats{}, fmt.Errorf("Failed to decode daily asset type stats.")
+	}
+
+	if len(dailyAssetTypeStats) > 0 {
+		return dailyAssetTypeStats[0], nil
+	}
+
+	return responses.DailyAssetTypeStats{}, nil
+}
+
+// calculateDailyHoldingStats stores the per holding rows of the daily
+// calculation and their per asset type totals.
+func (dasModel *DailyAssetStatsModel) calculateDailyHoldingStats(holdingStages bson.A) {
+	cursor, err := dasModel.AssetCollection.Aggregate(context.TODO(), holdingStages)
+	if err != nil {
+		logrus.Error("failed to aggregate daily holding stats calculation: ", err)
+		return
+	}
+
+	var dailyHoldingStats []responses.DailyAssetHoldingStatsCalculation
+	if err = cursor.All(context.TODO(), &dailyHoldingStats); err != nil {
+		logrus.Error("failed to decode daily holding stats calculation: ", err)
+		return
+	}
+
+	if len(dailyHoldingStats) < 1 {
+		return
+	}
+
+	dasModel.upsertDailyHoldingStats(dailyHoldingStats)
+}
+
+// upsertDailyAssetStats replaces the stats of the same user and day, so
+// calculating a day again doesn't create duplicates.
+func (dasModel *DailyAssetStatsModel) upsertDailyAssetStats(dailyAssetStats []responses.DailyAssetStatsCalculation) {
+	writeModels := make([]mongo.WriteModel, len(dailyAssetStats))
+	for i, dailyAssetStat := range dailyAssetStats {
+		writeModels[i] = getDailyStatsWriteModel(bson.M{
+			"user_id": dailyAssetStat.UserID,
+		}, dailyAssetStat.CreatedAt, dailyAssetStat)
+	}
+
+	if err := bulkWriteDailyStats(dasModel.Collection, writeModels); err != nil {
 		logrus.Error("failed to create daily asset stats calculation list: ", err)
 	}
 }
 
+// upsertDailyHoldingStats replaces the holding stats of the same day and
+// their per asset type totals.
+func (dasModel *DailyAssetStatsModel) upsertDailyHoldingStats(dailyHoldingStats []responses.DailyAssetHoldingStatsCalculation) {
+	type assetTypeKey struct {
+		userID    primitive.ObjectID
+		assetType string
+		date      time.Time
+	}
+
+	var (
+		holdingWriteModels  = make([]mongo.WriteModel, len(dailyHoldingStats))
+		assetTypeKeys       []assetTypeKey
+		dailyAssetTypeStats = make(map[assetTypeKey]*responses.DailyAssetTypeStatsCalculation)
+	)
+
+	for i, dailyHoldingStat := range dailyHoldingStats {
+		holdingWriteModels[i] = getDailyStatsWriteModel(bson.M{
+			"user_id":    dailyHoldingStat.UserID,
+			"to_asset":   dailyHoldingStat.ToAsset,
+			"from_asset": dailyHoldingStat.FromAsset,
+		}, dailyHoldingStat.CreatedAt, dailyHoldingStat)
+
+		key := assetTypeKey{
+			userID:    dailyHoldingStat.UserID,
+			assetType: dailyHoldingStat.AssetType,
+			date:      getDayStart(dailyHoldingStat.CreatedAt.UTC()),
+		}
+
+		dailyAssetTypeStat, ok := dailyAssetTypeStats[key]
+		if !ok {
+			dailyAssetTypeStat = &responses.DailyAssetTypeStatsCalculation{
+				Currency:  dailyHoldingStat.Currency,
+				UserID:    dailyHoldingStat.UserID,
+				AssetType: dailyHoldingStat.AssetType,
+				CreatedAt: dailyHoldingStat.CreatedAt,
+			}
+			dailyAssetTypeStats[key] = dailyAssetTypeStat
+			assetTypeKeys = append(assetTypeKeys, key)
+		}
+
+		dailyAssetTypeStat.TotalAssets += dailyHoldingStat.TotalAssets
+		dailyAssetTypeStat.TotalPL += dailyHoldingStat.TotalPL
+	}
+
+	if err := bulkWriteDailyStats(dasModel.HoldingCollection, holdingWriteModels); err != nil {
+		logrus.Error("failed to create daily holding stats calculation list: ", err)
+	}
+
+	assetTypeWriteModels := make([]mongo.WriteModel, len(assetTypeKeys))
+	for i, key := range assetTypeKeys {
+		dailyAssetTypeStat := dailyAssetTypeStats[key]
+		assetTypeWriteModels[i] = getDailyStatsWriteModel(bson.M{
+			"user_id":    dailyAssetTypeStat.UserID,
+			"asset_type": dailyAssetTypeStat.AssetType,
+		}, dailyAssetTypeStat.CreatedAt, dailyAssetTypeStat)
+	}
+
+	if err := bulkWriteDailyStats(dasModel.AssetTypeCollection, assetTypeWriteModels); err != nil {
+		logrus.Error("failed to create daily asset type stats calculation list: ", err)
+	}
+}
+
 func (dasModel *DailyAssetStatsModel) DeleteAllAssetStatsByUserID(uid string) error {
 	if _, err := dasModel.Collection.DeleteMany(context.TODO(), bson.M{
 		"user_id": uid,
@@ -519,5 +739,190 @@ func (dasModel *DailyAssetStatsModel) DeleteAllAssetStatsByUserID(uid string) er
 		return fmt.Errorf("Failed to delete all asset stats by user id.")
 	}
 
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	for _, collection := range []*mongo.Collection{dasModel.HoldingCollection, dasModel.AssetTypeCollection} {
+		if _, err := collection.DeleteMany(context.TODO(), bson.M{
+			"user_id": objectUID,
+		}); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"uid":        uid,
+				"collection": collection.Name(),
+			}).Error("failed to delete all asset stats by user id: ", err)
+
+			return fmt.Errorf("Failed to delete all asset stats by user id.")
+		}
+	}
+
 	return nil
 }
+
+// getDailyStatsBucket returns the requested bucket, custom and all ranges are
+// downsampled by their length when no bucket is given.
+func getDailyStatsBucket(data requests.DailyAssetStatsInterval) string {
+	if data.Bucket != "" || (data.Interval != "custom" && data.Interval != "all") {
+		return data.Bucket
+	}
+
+	if data.Interval == "all" {
+		return "weekly"
+	}
+
+	switch days := data.EndDate.Sub(*data.StartDate).Hours() / 24; {
+	case days > dailyStatsWeeklyBucketDays:
+		return "monthly"
+	case days > dailyStatsDailyBucketDays:
+		return "weekly"
+	default:
+		return ""
+	}
+}
+
+// getSeriesStatsBucket returns the bucket of holding and asset type stats,
+// yearly stats are reduced to months like the other ranges without a bucket
+// choice.
+func getSeriesStatsBucket(interval string, startDate, endDate *time.Time) string {
+	if interval == "yearly" {
+		return "monthly"
+	}
+
+	return getDailyStatsBucket(requests.DailyAssetStatsInterval{
+		Interval:  interval,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+}
+
+// getDailyStatsWriteModel matches the stat of the same day in UTC, created_at
+// of the replacement is kept as it is.
+func getDailyStatsWriteModel(filter bson.M, createdAt time.Time, document interface{}) mongo.WriteModel {
+	dayStart := getDayStart(createdAt.UTC())
+	filter["created_at"] = bson.M{
+		"$gte": dayStart,
+		"$lt":  dayStart.AddDate(0, 0, 1),
+	}
+
+	return mongo.NewReplaceOneModel().
+		SetFilter(filter).
+		SetReplacement(document).
+		SetUpsert(true)
+}
+
+func bulkWriteDailyStats(collection *mongo.Collection, writeModels []mongo.WriteModel) error {
+	if len(writeModels) == 0 {
+		return nil
+	}
+
+	_, err := collection.BulkWrite(context.TODO(), writeModels, options.BulkWrite().SetOrdered(false))
+
+	return err
+}
+
+func getDailyStatsDateFilter(interval string, startDate, endDate *time.Time) bson.M {
+	now := time.Now().UTC()
+
+	switch interval {
+	case "all":
+		return nil
+	case "monthly":
+		return bson.M{"$gte": now.AddDate(0, -1, 0)}
+	case "yearly":
+		return bson.M{"$gte": time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)}
+	case "custom":
+		return bson.M{
+			"$gte": *startDate,
+			"$lt":  endDate.AddDate(0, 0, 1),
+		}
+	default:
+		return bson.M{"$gte": now.AddDate(0, 0, -7)}
+	}
+}
+
+// getDailyStatsSeriesPipeline converts convertedFields to user's current currency
+// with the rate of the stat's date and returns every field as an array next to
+// dates. Stats are reduced to the last stat of every bucket when a bucket is
+// given.
+func getDailyStatsSeriesPipeline(match bson.M, bucket string, convertedFields, fields []string) bson.A {
+	userLookup := bson.M{"$lookup": bson.M{
+		"from":         "users",
+		"localField":   "user_id",
+		"foreignField": "_id",
+		"as":           "user",
+	}}
+	unwindUser := bson.M{"$unwind": bson.M{
+		"path":                       "$user",
+		"includeArrayIndex":          "index",
+		"preserveNullAndEmptyArrays": true,
+	}}
+	exchangeLookup := getExchangeRateLookup("$currency", "$user.currency", "$created_at", "user_exchange_rate")
+	unwindExchange := bson.M{"$unwind": bson.M{
+		"path":                       "$user_exchange_rate",
+		"includeArrayIndex":          "index",
+		"preserveNullAndEmptyArrays": true,
+	}}
+
+	projectFields := bson.M{
+		"currency":   "$user.currency",
+		"created_at": true,
+	}
+	arrayGroupFields := bson.M{
+		"_id": nil,
+		"currency": bson.M{
+			"$first": "$currency",
+		},
+		"dates": bson.M{
+			"$push": "$created_at",
+		},
+	}
+	bucketDate := bson.M{
+		"$dateTrunc": bson.M{
+			"date":        "$created_at",
+			"unit":        dailyStatsBucketUnits[bucket],
+			"startOfWeek": "monday",
+		},
+	}
+	bucketGroupFields := bson.M{
+		"_id": bucketDate,
+		"currency": bson.M{
+			"$last": "$currency",
+		},
+		"created_at": bson.M{
+			"$first": bucketDate,
+		},
+	}
+
+	for _, field := range convertedFields {
+		projectFields[field] = bson.M{
+			"$ifNull": bson.A{
+				bson.M{
+					"$multiply": bson.A{"$" + field, "$user_exchange_rate.exchange_rate"},
+				},
+				"$" + field,
+			},
+		}
+	}
+
+	for _, field := range fields {
+		projectFields[field] = true
+	}
+
+	for _, field := range append(append([]string{}, convertedFields...), fields...) {
+		arrayGroupFields[field] = bson.M{"$push": "$" + field}
+		bucketGroupFields[field] = bson.M{"$last": "$" + field}
+	}
+
+	sort := bson.M{"$sort": bson.M{
+		"created_at": 1,
+	}}
+
+	pipeline := bson.A{
+		bson.M{"$match": match}, userLookup, unwindUser, exchangeLookup, unwindExchange,
+		bson.M{"$project": projectFields}, sort,
+	}
+
+	if bucket != "" {
+		pipeline = append(pipeline, bson.M{"$group": bucketGroupFields}, sort)
+	}
+
+	return append(pipeline, bson.M{"$group": arrayGroupFields})
+}
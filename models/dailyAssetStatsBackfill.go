@@ -0,0 +1,425 @@
+package models
+
+import (
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/**
+* Backfill only reconstructs the days that have no daily asset stats, existing
+* snapshots are never overwritten. There is no price history for investings, so
+* holdings are valued with the latest price known on that day, either from the
+* asset logs or from an earlier daily holding stat, whichever is newer.
+*
+* Every value is converted to user's current currency with the rate of that day.
+* Corporate actions are applied to the holdings on their effective date, the
+* same way the aggregations apply them to the logs created before it.
+**/
+type backfillHolding struct {
+	assetType     string
+	assetMarket   string
+	symbols       map[string]bool
+	remaining     float64
+	totalBought   float64
+	totalSold     float64
+	totalIncome   float64
+	totalFees     float64
+	price         float64
+	priceCurrency string
+	priceDate     time.Time
+}
+
+type backfillCalculator struct {
+	dasModel      *DailyAssetStatsModel
+	currency      string
+	exchangeRates map[string]float64
+}
+
+// BackfillDailyAssetStats reconstructs the missing days between start and end
+// date, today is left to the daily calculation since it uses current prices.
+func (dasModel *DailyAssetStatsModel) BackfillDailyAssetStats(data requests.DailyAssetStatsBackfill) (responses.DailyAssetStatsBackfill, error) {
+	today := getDayStart(time.Now().UTC())
+
+	backfill := responses.DailyAssetStatsBackfill{
+		StartDate: getDayStart(data.StartDate.UTC()),
+		EndDate:   today.AddDate(0, 0, -1),
+	}
+
+	if data.EndDate != nil && data.EndDate.Before(backfill.EndDate) {
+		backfill.EndDate = getDayStart(data.EndDate.UTC())
+	}
+
+	if backfill.EndDate.Before(backfill.StartDate) {
+		return backfill, nil
+	}
+
+	var userIDs []interface{}
+	if data.UserID != "" {
+		userIDs = []interface{}{data.UserID}
+	} else {
+		var err error
+		if userIDs, err = dasModel.AssetCollection.Distinct(context.TODO(), "user_id", bson.M{
+			"created_at": bson.M{"$lt": backfill.EndDate.AddDate(0, 0, 1)},
+		}); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"start_date": backfill.StartDate,
+				"end_date":   backfill.EndDate,
+			}).Error("failed to find users for daily asset stats backfill: ", err)
+
+			return responses.DailyAssetStatsBackfill{}, fmt.Errorf("Failed to find users for backfill.")
+		}
+	}
+
+	for _, userID := range userIDs {
+		uid, ok := userID.(string)
+		if !ok {
+			continue
+		}
+
+		dayCount, err := dasModel.backfillUserDailyAssetStats(uid, backfill.StartDate, backfill.EndDate)
+		if err != nil {
+			return responses.DailyAssetStatsBackfill{}, err
+		}
+
+		if dayCount > 0 {
+			backfill.UserCount++
+			backfill.DayCount += dayCount
+		}
+	}
+
+	return backfill, nil
+}
+
+func (dasModel *DailyAssetStatsModel) backfillUserDailyAssetStats(uid string, startDate, endDate time.Time) (int, error) {
+	objectUID, err := primitive.ObjectIDFromHex(uid)
+	if err != nil {
+		return 0, nil
+	}
+
+	var user User
+	if err := dasModel.UserCollection.FindOne(context.TODO(), bson.M{"_id": objectUID}).Decode(&user); err != nil {
+		if err != mongo.ErrNoDocuments {
+			logrus.WithFields(logrus.Fields{
+				"uid": uid,
+			}).Error("failed to find user for daily asset stats backfill: ", err)
+
+			return 0, fmt.Errorf("Failed to find user for backfill.")
+		}
+
+		return 0, nil
+	}
+
+	rangeEnd := endDate.AddDate(0, 0, 1)
+	sortOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	var assetLogs []Asset
+	if err := dasModel.findBackfillDocuments(dasModel.AssetCollection, bson.M{
+		"user_id":    uid,
+		"created_at": bson.M{"$lt": rangeEnd},
+	}, sortOptions, &assetLogs); err != nil {
+		return 0, err
+	}
+
+	var dailyHoldingStats []responses.DailyAssetHoldingStatsCalculation
+	if err := dasModel.findBackfillDocuments(dasModel.HoldingCollection, bson.M{
+		"user_id":    objectUID,
+		"created_at": bson.M{"$lt": rangeEnd},
+	}, sortOptions, &dailyHoldingStats); err != nil {
+		return 0, err
+	}
+
+	var corporateActions []CorporateAction
+	if err := dasModel.findBackfillDocuments(dasModel.CorporateActionCollection, bson.M{
+		"effective_date": bson.M{"$lt": rangeEnd},
+	}, options.Find().SetSort(bson.D{{Key: "effective_date", Value: 1}, {Key: "_id", Value: 1}}), &corporateActions); err != nil {
+		return 0, err
+	}
+
+	var existingStats []responses.DailyAssetStatsCalculation
+	if err := dasModel.findBackfillDocuments(dasModel.Collection, bson.M{
+		"user_id":    objectUID,
+		"created_at": bson.M{"$gte": startDate, "$lt": rangeEnd},
+	}, sortOptions, &existingStats); err != nil {
+		return 0, err
+	}
+
+	existingDays := make(map[time.Time]bool)
+	for _, existingStat := range existingStats {
+		existingDays[getDayStart(existingStat.CreatedAt.UTC())] = true
+	}
+
+	calculator := &backfillCalculator{
+		dasModel:      dasModel,
+		currency:      user.Currency,
+		exchangeRates: make(map[string]float64),
+	}
+
+	var (
+		keys                             []costBasisKey
+		holdings                         = make(map[costBasisKey]*backfillHolding)
+		logIndex, statIndex, actionIndex int
+		backfilledStats                  []responses.DailyAssetStatsCalculation
+		backfilledHoldings               []responses.DailyAssetHoldingStatsCalculation
+	)
+
+	for day := startDate; !day.After(endDate); day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+
+		for ; logIndex < len(assetLogs) && assetLogs[logIndex].CreatedAt.Before(dayEnd); logIndex++ {
+			assetLog := assetLogs[logIndex]
+			key := costBasisKey{ToAsset: assetLog.ToAsset, FromAsset: assetLog.FromAsset}
+
+			for ; actionIndex < len(corporateActions) && !corporateActions[actionIndex].EffectiveDate.After(assetLog.CreatedAt); actionIndex++ {
+				keys = applyBackfillCorporateAction(corporateActions[actionIndex], keys, holdings)
+			}
+
+			holding, ok := holdings[key]
+			if !ok {
+				holding = &backfillHolding{
+					assetType:   assetLog.AssetType,
+					assetMarket: assetLog.AssetMarket,
+					symbols:     map[string]bool{assetLog.ToAsset: true},
+				}
+				holdings[key] = holding
+				keys = append(keys, key)
+			}
+
+			holding.remaining += getSignedAssetAmount(assetLog)
+			switch assetLog.Type {
+			case AssetLogBuy:
+				holding.totalBought += assetLog.CurrencyValue
+			case AssetLogSell:
+				holding.totalSold += assetLog.CurrencyValue
+			case AssetLogFee:
+				holding.totalFees += assetLog.CurrencyValue
+			case AssetLogDividend:
+				holding.totalIncome += assetLog.CurrencyValue
+			}
+
+			// Splits keep the date and currency of the price they divide.
+			if assetLog.Type == AssetLogSplit {
+				holding.price = getAssetLogPrice(holding.price, assetLog)
+			} else if assetLog.Type != AssetLogDividend && assetLog.Type != AssetLogFee &&
+				!assetLog.CreatedAt.Before(holding.priceDate) {
+				holding.price = assetLog.Price
+				holding.priceCurrency = assetLog.FromAsset
+				holding.priceDate = assetLog.CreatedAt
+			}
+		}
+
+		for ; actionIndex < len(corporateActions) && !corporateActions[actionIndex].EffectiveDate.After(day); actionIndex++ {
+			keys = applyBackfillCorporateAction(corporateActions[actionIndex], keys, holdings)
+		}
+
+		for ; statIndex < len(dailyHoldingStats) && dailyHoldingStats[statIndex].CreatedAt.Before(dayEnd); statIndex++ {
+			dailyHoldingStat := dailyHoldingStats[statIndex]
+			key := costBasisKey{ToAsset: dailyHoldingStat.ToAsset, FromAsset: dailyHoldingStat.FromAsset}
+
+			if holding, ok := holdings[key]; ok && !dailyHoldingStat.CreatedAt.Before(holding.priceDate) {
+				holding.price = dailyHoldingStat.Price
+				holding.priceCurrency = dailyHoldingStat.Currency
+				holding.priceDate = dailyHoldingStat.CreatedAt
+			}
+		}
+
+		if existingDays[day] || len(keys) == 0 {
+			continue
+		}
+
+		dailyAssetStat := responses.DailyAssetStatsCalculation{
+			Currency:  user.Currency,
+			UserID:    objectUID,
+			CreatedAt: day,
+		}
+
+		for _, key := range keys {
+			dailyHoldingStat, err := calculator.getHoldingStat(key, holdings[key], day)
+			if err != nil {
+				return 0, err
+			}
+
+			dailyHoldingStat.UserID = objectUID
+			backfilledHoldings = append(backfilledHoldings, dailyHoldingStat)
+
+			dailyAssetStat.TotalAssets += dailyHoldingStat.TotalAssets
+			dailyAssetStat.TotalPL += dailyHoldingStat.TotalPL
+		}
+
+		backfilledStats = append(backfilledStats, dailyAssetStat)
+	}
+
+	if len(backfilledStats) > 0 {
+		dasModel.upsertDailyHoldingStats(backfilledHoldings)
+		dasModel.upsertDailyAssetStats(backfilledStats)
+	}
+
+	return len(backfilledStats), nil
+}
+
+// getHoldingStat calculates the holding stat of the day the same way as the
+// daily calculation, p/l is total bought and fees minus total sold, income
+// and current value.
+func (calculator *backfillCalculator) getHoldingStat(
+	key costBasisKey, holding *backfillHolding, day time.Time,
+) (responses.DailyAssetHoldingStatsCalculation, error) {
+	valueRate, err := calculator.getExchangeRate(key.FromAsset, day)
+	if err != nil {
+		return responses.DailyAssetHoldingStatsCalculation{}, err
+	}
+
+	priceRate, err := calculator.getExchangeRate(holding.priceCurrency, day)
+	if err != nil {
+		return responses.DailyAssetHoldingStatsCalculation{}, err
+	}
+
+	remaining := holding.remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	price := holding.price * priceRate
+	totalAssets := remaining * price
+
+	return responses.DailyAssetHoldingStatsCalculation{
+		Currency:    calculator.currency,
+		ToAsset:     key.ToAsset,
+		FromAsset:   key.FromAsset,
+		AssetType:   holding.assetType,
+		Amount:      remaining,
+		Price:       price,
+		CreatedAt:   day,
+		TotalAssets: totalAssets,
+		TotalPL: (holding.totalBought+holding.totalFees)*valueRate -
+			((holding.totalSold+holding.totalIncome)*valueRate + totalAssets),
+	}, nil
+}
+
+// applyBackfillCorporateAction splits or renames the holdings of the action's
+// symbols and returns the keys, renamed holdings are merged into existing ones.
+func applyBackfillCorporateAction(
+	corporateAction CorporateAction, keys []costBasisKey, holdings map[costBasisKey]*backfillHolding,
+) []costBasisKey {
+	adjustedKeys := make([]costBasisKey, 0, len(keys))
+
+	for _, key := range keys {
+		holding := holdings[key]
+		if !isBackfillHoldingAffected(corporateAction, holding) {
+			adjustedKeys = append(adjustedKeys, key)
+			continue
+		}
+
+		if corporateAction.ActionType == CorporateActionSplit {
+			holding.remaining *= corporateAction.SplitRatio
+			holding.price /= corporateAction.SplitRatio
+			adjustedKeys = append(adjustedKeys, key)
+
+			continue
+		}
+
+		newKey := costBasisKey{ToAsset: corporateAction.NewSymbol, FromAsset: key.FromAsset}
+		holding.symbols[corporateAction.NewSymbol] = true
+
+		if newKey == key {
+			adjustedKeys = append(adjustedKeys, key)
+			continue
+		}
+
+		delete(holdings, key)
+
+		if existingHolding, ok := holdings[newKey]; ok {
+			mergeBackfillHoldings(existingHolding, holding)
+			continue
+		}
+
+		holdings[newKey] = holding
+		adjustedKeys = append(adjustedKeys, newKey)
+	}
+
+	return adjustedKeys
+}
+
+func isBackfillHoldingAffected(corporateAction CorporateAction, holding *backfillHolding) bool {
+	if holding.assetType != corporateAction.AssetType || holding.assetMarket != corporateAction.AssetMarket {
+		return false
+	}
+
+	for _, symbol := range corporateAction.Symbols {
+		if holding.symbols[symbol] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mergeBackfillHoldings(holding, renamedHolding *backfillHolding) {
+	holding.remaining += renamedHolding.remaining
+	holding.totalBought += renamedHolding.totalBought
+	holding.totalSold += renamedHolding.totalSold
+	holding.totalIncome += renamedHolding.totalIncome
+	holding.totalFees += renamedHolding.totalFees
+
+	if renamedHolding.priceDate.After(holding.priceDate) {
+		holding.price = renamedHolding.price
+		holding.priceCurrency = renamedHolding.priceCurrency
+		holding.priceDate = renamedHolding.priceDate
+	}
+
+	for symbol := range renamedHolding.symbols {
+		holding.symbols[symbol] = true
+	}
+}
+
+func (calculator *backfillCalculator) getExchangeRate(fromCurrency string, date time.Time) (float64, error) {
+	key := fromCurrency + date.Format("2006-01-02")
+
+	if exchangeRate, ok := calculator.exchangeRates[key]; ok {
+		return exchangeRate, nil
+	}
+
+	exchangeRate, err := getHistoricalExchangeRate(
+		calculator.dasModel.ExchangeHistoryCollection, calculator.dasModel.ExchangeCollection,
+		fromCurrency, calculator.currency, date,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	calculator.exchangeRates[key] = exchangeRate
+
+	return exchangeRate, nil
+}
+
+func (dasModel *DailyAssetStatsModel) findBackfillDocuments(
+	collection *mongo.Collection, filter bson.M, findOptions *options.FindOptions, results interface{},
+) error {
+	cursor, err := collection.Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"collection": collection.Name(),
+			"filter":     filter,
+		}).Error("failed to find documents for daily asset stats backfill: ", err)
+
+		return fmt.Errorf("Failed to find documents for backfill.")
+	}
+
+	if err = cursor.All(context.TODO(), results); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"collection": collection.Name(),
+			"filter":     filter,
+		}).Error("failed to decode documents for daily asset stats backfill: ", err)
+
+		return fmt.Errorf("Failed to decode documents for backfill.")
+	}
+
+	return nil
+}
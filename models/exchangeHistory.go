@@ -0,0 +1,298 @@
+package models
+
+import (
+	"asset_backend/db"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ExchangeHistoryModel struct {
+	Collection         *mongo.Collection
+	ExchangeCollection *mongo.Collection
+}
+
+func NewExchangeHistoryModel(mongoDB *db.MongoDB) *ExchangeHistoryModel {
+	return &ExchangeHistoryModel{
+		Collection:         mongoDB.Database.Collection("exchange-history"),
+		ExchangeCollection: mongoDB.Database.Collection("exchanges"),
+	}
+}
+
+type ExchangeHistory struct {
+	FromExchange string    `bson:"from_exchange" json:"from_exchange"`
+	ToExchange   string    `bson:"to_exchange" json:"to_exchange"`
+	ExchangeRate float64   `bson:"exchange_rate" json:"exchange_rate"`
+	Date         time.Time `bson:"date" json:"date"`
+}
+
+// Current rates are only used when there is no snapshot, so they're sorted
+// after every snapshot regardless of the date.
+const currentExchangeRateDistance = 1e15
+
+// Pairs without a direct or reverse rate are converted over this currency.
+const exchangeRatePivotCurrency = "USD"
+
+// CreateDailyExchangeSnapshot copies the current exchange rates into the
+// history with today's date. Running it again on the same day updates the rates.
+func (exchangeHistoryModel *ExchangeHistoryModel) CreateDailyExchangeSnapshot() {
+	cursor, err := exchangeHistoryModel.ExchangeCollection.Find(context.TODO(), bson.M{})
+	if err != nil {
+		logrus.Error("failed to find exchanges for snapshot: ", err)
+		return
+	}
+
+	var exchanges []Exchange
+	if err = cursor.All(context.TODO(), &exchanges); err != nil {
+		logrus.Error("failed to decode exchanges for snapshot: ", err)
+		return
+	}
+
+	if len(exchanges) == 0 {
+		return
+	}
+
+	today := getDayStart(time.Now().UTC())
+
+	writeModels := make([]mongo.WriteModel, len(exchanges))
+	for i, exchange := range exchanges {
+		writeModels[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"from_exchange": exchange.FromExchange,
+				"to_exchange":   exchange.ToExchange,
+				"date":          today,
+			}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"exchange_rate": exchange.ExchangeRate,
+			}}).
+			SetUpsert(true)
+	}
+
+	if _, err = exchangeHistoryModel.Collection.BulkWrite(
+		context.TODO(), writeModels, options.BulkWrite().SetOrdered(false),
+	); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"date": today,
+		}).Error("failed to create exchange snapshot: ", err)
+	}
+}
+
+// getHistoricalExchangeRate returns the rate of the snapshot closest to date.
+// Reverse pairs are used when the direct pair is missing, then the cross rate
+// over exchangeRatePivotCurrency, and the current rate when there are no
+// snapshots at all.
+func getHistoricalExchangeRate(
+	historyCollection, exchangeCollection *mongo.Collection, fromCurrency, toCurrency string, date time.Time,
+) (float64, error) {
+	if fromCurrency == toCurrency {
+		return 1, nil
+	}
+
+	if exchangeRate, ok := findHistoricalPairRate(historyCollection, fromCurrency, toCurrency, date); ok {
+		return exchangeRate, nil
+	}
+
+	if fromCurrency != exchangeRatePivotCurrency && toCurrency != exchangeRatePivotCurrency {
+		if fromRate, ok := findHistoricalPairRate(historyCollection, fromCurrency, exchangeRatePivotCurrency, date); ok {
+			if toRate, ok := findHistoricalPairRate(historyCollection, exchangeRatePivotCurrency, toCurrency, date); ok {
+				return fromRate * toRate, nil
+			}
+		}
+	}
+
+	return getExchangeRate(exchangeCollection, fromCurrency, toCurrency)
+}
+
+func findHistoricalPairRate(historyCollection *mongo.Collection, fromCurrency, toCurrency string, date time.Time) (float64, bool) {
+	if exchangeHistory, ok := findNearestExchangeHistory(historyCollection, fromCurrency, toCurrency, date); ok {
+		return exchangeHistory.ExchangeRate, true
+	}
+
+	if exchangeHistory, ok := findNearestExchangeHistory(historyCollection, toCurrency, fromCurrency, date); ok {
+		return 1 / exchangeHistory.ExchangeRate, true
+	}
+
+	return 0, false
+}
+
+func findNearestExchangeHistory(historyCollection *mongo.Collection, fromCurrency, toCurrency string, date time.Time) (ExchangeHistory, bool) {
+	var (
+		nearest ExchangeHistory
+		found   bool
+	)
+
+	date = getDayStart(date.UTC())
+
+	for _, condition := range []struct {
+		operator string
+		order    int
+	}{{"$lte", -1}, {"$gt", 1}} {
+		var exchangeHistory ExchangeHistory
+		if err := historyCollection.FindOne(context.TODO(), bson.M{
+			"from_exchange": fromCurrency,
+			"to_exchange":   toCurrency,
+			"date":          bson.M{condition.operator: date},
+			"exchange_rate": bson.M{"$ne": 0},
+		}, options.FindOne().SetSort(bson.M{"date": condition.order})).Decode(&exchangeHistory); err != nil {
+			if err != mongo.ErrNoDocuments {
+				logrus.WithFields(logrus.Fields{
+					"from": fromCurrency,
+					"to":   toCurrency,
+					"date": date,
+				}).Error("failed to find exchange history: ", err)
+			}
+
+			continue
+		}
+
+		if !found || absDuration(exchangeHistory.Date.Sub(date)) < absDuration(nearest.Date.Sub(date)) {
+			nearest = exchangeHistory
+			found = true
+		}
+	}
+
+	return nearest, found
+}
+
+// getExchangeRateLookup returns a $lookup stage that finds the rate of the
+// snapshot closest to date, falling back to the current rate. Reverse pairs and
+// cross rates over exchangeRatePivotCurrency are used when the direct pair is
+// missing. from, to and date are aggregation expressions, the result is an
+// array with at most one document with exchange_rate.
+func getExchangeRateLookup(from, to, date interface{}, as string) bson.M {
+	pairMatch := bson.M{"$match": bson.M{
+		"$expr": bson.M{
+			"$and": bson.A{
+				bson.M{"$ne": bson.A{"$$from_currency", "$$to_currency"}},
+				getExchangePairCondition("", "$$from_currency", "$$to_currency"),
+			},
+		},
+	}}
+	pairRate := getExchangePairRate("", "$$from_currency")
+	dateDistance := bson.M{
+		"$abs": bson.M{
+			"$subtract": bson.A{"$date", "$$date"},
+		},
+	}
+
+	crossRateStages := bson.A{
+		bson.M{"$match": bson.M{
+			"$expr": bson.M{
+				"$and": bson.A{
+					bson.M{"$ne": bson.A{"$$from_currency", "$$to_currency"}},
+					bson.M{"$ne": bson.A{"$$from_currency", exchangeRatePivotCurrency}},
+					bson.M{"$ne": bson.A{"$$to_currency", exchangeRatePivotCurrency}},
+					getExchangePairCondition("", "$$from_currency", exchangeRatePivotCurrency),
+				},
+			},
+		}},
+		bson.M{"$lookup": bson.M{
+			"from": "exchange-history",
+			"let": bson.M{
+				"leg_date":     "$date",
+				"leg_currency": "$$to_currency",
+			},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{
+					"$expr": bson.M{
+						"$and": bson.A{
+							bson.M{"$eq": bson.A{"$date", "$$leg_date"}},
+							getExchangePairCondition("", exchangeRatePivotCurrency, "$$leg_currency"),
+						},
+					},
+				}},
+				bson.M{"$limit": 1},
+			},
+			"as": "pivot_exchange",
+		}},
+		bson.M{"$unwind": "$pivot_exchange"},
+		bson.M{"$addFields": bson.M{
+			"exchange_rate": bson.M{
+				"$multiply": bson.A{pairRate, getExchangePairRate("pivot_exchange.", exchangeRatePivotCurrency)},
+			},
+			"distance": dateDistance,
+			"is_cross": true,
+		}},
+	}
+
+	return bson.M{"$lookup": bson.M{
+		"from": "exchange-history",
+		"let": bson.M{
+			"from_currency": from,
+			"to_currency":   to,
+			"date":          date,
+		},
+		"pipeline": bson.A{
+			pairMatch,
+			bson.M{"$addFields": bson.M{
+				"exchange_rate": pairRate,
+				"distance":      dateDistance,
+			}},
+			bson.M{"$unionWith": bson.M{
+				"coll":     "exchange-history",
+				"pipeline": crossRateStages,
+			}},
+			bson.M{"$unionWith": bson.M{
+				"coll": "exchanges",
+				"pipeline": bson.A{
+					pairMatch,
+					bson.M{"$addFields": bson.M{
+						"exchange_rate": pairRate,
+						"distance":      currentExchangeRateDistance,
+					}},
+				},
+			}},
+			bson.M{"$sort": bson.D{{Key: "distance", Value: 1}, {Key: "is_cross", Value: 1}}},
+			bson.M{"$limit": 1},
+		},
+		"as": as,
+	}}
+}
+
+// getExchangePairCondition matches the exchange document of the pair or its
+// reverse with a usable rate, prefix is the path of the document.
+func getExchangePairCondition(prefix string, from, to interface{}) bson.M {
+	return bson.M{
+		"$and": bson.A{
+			bson.M{"$ne": bson.A{"$" + prefix + "exchange_rate", 0}},
+			bson.M{"$or": bson.A{
+				bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$" + prefix + "from_exchange", from}},
+					bson.M{"$eq": bson.A{"$" + prefix + "to_exchange", to}},
+				}},
+				bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$" + prefix + "from_exchange", to}},
+					bson.M{"$eq": bson.A{"$" + prefix + "to_exchange", from}},
+				}},
+			}},
+		},
+	}
+}
+
+// getExchangePairRate returns the rate of the matched exchange document from
+// the from currency, reversing it when the document is the reverse pair.
+func getExchangePairRate(prefix string, from interface{}) bson.M {
+	return bson.M{
+		"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$" + prefix + "from_exchange", from}},
+			"$" + prefix + "exchange_rate",
+			bson.M{"$divide": bson.A{1, "$" + prefix + "exchange_rate"}},
+		},
+	}
+}
+
+func getDayStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
+
+func absDuration(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return -duration
+	}
+
+	return duration
+}
@@ -97,7 +97,7 @@ func (favInvestingModel *FavouriteInvestingModel) GetFavouriteInvestingsCount(ui
 			"uid": uid,
 		}).Error("failed to count user favourite investings: ", err)
 
-		return subscriptionPremiumLimit
+		return countErrorFallback
 	}
 
 	return count
@@ -23,6 +23,56 @@ func NewInvestingModel(mongoDB *db.MongoDB) *InvestingModel {
 	}
 }
 
+type InvestingID struct {
+	Symbol string `bson:"symbol" json:"symbol"`
+	Type   string `bson:"type" json:"type"`
+	Market string `bson:"market" json:"market"`
+}
+
+func (investingModel *InvestingModel) GetExistingInvestingIDs(investingIDs []InvestingID) (map[InvestingID]bool, error) {
+	existingIDs := make(map[InvestingID]bool)
+	if len(investingIDs) == 0 {
+		return existingIDs, nil
+	}
+
+	idList := bson.A{}
+	for _, investingID := range investingIDs {
+		idList = append(idList, bson.M{
+			"_id.symbol": investingID.Symbol,
+			"_id.type":   investingID.Type,
+			"_id.market": investingID.Market,
+		})
+	}
+
+	cursor, err := investingModel.Collection.Aggregate(context.TODO(), bson.A{
+		bson.M{"$match": bson.M{"$or": idList}},
+		bson.M{"$project": bson.M{
+			"_id":    false,
+			"symbol": "$_id.symbol",
+			"type":   "$_id.type",
+			"market": "$_id.market",
+		}},
+	})
+	if err != nil {
+		logrus.Error("failed to aggregate existing investings: ", err)
+
+		return nil, fmt.Errorf("Failed to fetch investings.")
+	}
+
+	var foundIDs []InvestingID
+	if err = cursor.All(context.TODO(), &foundIDs); err != nil {
+		logrus.Error("failed to decode existing investings: ", err)
+
+		return nil, fmt.Errorf("Failed to decode investings.")
+	}
+
+	for _, foundID := range foundIDs {
+		existingIDs[foundID] = true
+	}
+
+	return existingIDs, nil
+}
+
 func (investingModel *InvestingModel) GetInvestingsByTypeAndMarket(tType, market string) ([]responses.InvestingResponse, error) {
 	match := bson.M{"$match": bson.M{
 		"_id.type":   tType,
@@ -111,3 +161,39 @@ func (investingModel *InvestingModel) GetInvestingPriceTableByTypeAndMarket(tTyp
 
 	return investings, nil
 }
+
+type Exchange struct {
+	FromExchange string  `bson:"from_exchange" json:"from_exchange"`
+	ToExchange   string  `bson:"to_exchange" json:"to_exchange"`
+	ExchangeRate float64 `bson:"exchange_rate" json:"exchange_rate"`
+}
+
+// getExchangeRate returns the rate to multiply a fromCurrency value with to get
+// its toCurrency value. Reverse pairs are used when the direct pair is missing.
+func getExchangeRate(exchangeCollection *mongo.Collection, fromCurrency, toCurrency string) (float64, error) {
+	if fromCurrency == toCurrency {
+		return 1, nil
+	}
+
+	var exchange Exchange
+	if err := exchangeCollection.FindOne(context.TODO(), bson.M{
+		"from_exchange": fromCurrency,
+		"to_exchange":   toCurrency,
+	}).Decode(&exchange); err == nil && exchange.ExchangeRate != 0 {
+		return exchange.ExchangeRate, nil
+	}
+
+	if err := exchangeCollection.FindOne(context.TODO(), bson.M{
+		"from_exchange": toCurrency,
+		"to_exchange":   fromCurrency,
+	}).Decode(&exchange); err != nil || exchange.ExchangeRate == 0 {
+		logrus.WithFields(logrus.Fields{
+			"from": fromCurrency,
+			"to":   toCurrency,
+		}).Error("failed to find exchange rate: ", err)
+
+		return 0, fmt.Errorf("Failed to find exchange rate.")
+	}
+
+	return 1 / exchange.ExchangeRate, nil
+}
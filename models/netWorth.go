@@ -0,0 +1,371 @@
+package models
+
+import (
+	"asset_backend/db"
+	"asset_backend/responses"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NetWorthModel struct {
+	Collection                *mongo.Collection
+	BankAccountCollection     *mongo.Collection
+	CardCollection            *mongo.Collection
+	TransactionCollection     *mongo.Collection
+	UserCollection            *mongo.Collection
+	ExchangeCollection        *mongo.Collection
+	DailyAssetStatsCollection *mongo.Collection
+}
+
+func NewNetWorthModel(mongoDB *db.MongoDB) *NetWorthModel {
+	return &NetWorthModel{
+		Collection:                mongoDB.Database.Collection("daily-net-worth-stats"),
+		BankAccountCollection:     mongoDB.Database.Collection("bank-accounts"),
+		CardCollection:            mongoDB.Database.Collection("cards"),
+		TransactionCollection:     mongoDB.Database.Collection("transactions"),
+		UserCollection:            mongoDB.Database.Collection("users"),
+		ExchangeCollection:        mongoDB.Database.Collection("exchanges"),
+		DailyAssetStatsCollection: mongoDB.Database.Collection("daily-asset-stats"),
+	}
+}
+
+/**
+* Bank account balance is the opening balance plus income transactions minus
+* the others, card outstanding balance is the opening balance plus spendings
+* minus income, e.g. refunds and payments. Transactions are converted to the
+* account's currency and balances to user's currency with the latest rates.
+*
+* Net worth is total assets plus bank balances minus card balances.
+**/
+type transactionMethodKey struct {
+	MethodID string `bson:"method_id"`
+	Type     int64  `bson:"type"`
+	Currency string `bson:"currency"`
+}
+
+type transactionMethodTotal struct {
+	Key   transactionMethodKey `bson:"_id"`
+	Total float64              `bson:"total"`
+}
+
+type netWorthCalculator struct {
+	nwModel       *NetWorthModel
+	exchangeRates map[string]float64
+}
+
+// GetNetWorthByUserID expects total assets in user's currency.
+func (nwModel *NetWorthModel) GetNetWorthByUserID(uid, currency string, totalAssets float64) (responses.NetWorth, error) {
+	calculator := &netWorthCalculator{
+		nwModel:       nwModel,
+		exchangeRates: make(map[string]float64),
+	}
+
+	return calculator.calculateNetWorth(uid, currency, totalAssets)
+}
+
+// CalculateDailyNetWorth snapshots the net worth of users with bank accounts,
+// cards or assets. It uses today's daily asset stats, so it should run after
+// CalculateDailyAssetStats.
+func (nwModel *NetWorthModel) CalculateDailyNetWorth() {
+	today := getDayStart(time.Now().UTC())
+
+	var dailyAssetStats []responses.DailyAssetStatsCalculation
+	cursor, err := nwModel.DailyAssetStatsCollection.Find(context.TODO(), bson.M{
+		"created_at": bson.M{
+			"$gte": today,
+			"$lt":  today.AddDate(0, 0, 1),
+		},
+	})
+	if err != nil {
+		logrus.Error("failed to find daily asset stats for net worth: ", err)
+		return
+	}
+
+	if err = cursor.All(context.TODO(), &dailyAssetStats); err != nil {
+		logrus.Error("failed to decode daily asset stats for net worth: ", err)
+		return
+	}
+
+	var (
+		userIDs     []string
+		totalAssets = make(map[string]float64)
+	)
+
+	for _, dailyAssetStat := range dailyAssetStats {
+		uid := dailyAssetStat.UserID.Hex()
+		totalAssets[uid] = dailyAssetStat.TotalAssets
+		userIDs = append(userIDs, uid)
+	}
+
+	for _, collection := range []*mongo.Collection{nwModel.BankAccountCollection, nwModel.CardCollection} {
+		accountUserIDs, err := collection.Distinct(context.TODO(), "user_id", bson.M{})
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"collection": collection.Name(),
+			}).Error("failed to find users for net worth: ", err)
+
+			return
+		}
+
+		for _, accountUserID := range accountUserIDs {
+			if uid, ok := accountUserID.(string); ok {
+				if _, ok := totalAssets[uid]; !ok {
+					totalAssets[uid] = 0
+					userIDs = append(userIDs, uid)
+				}
+			}
+		}
+	}
+
+	calculator := &netWorthCalculator{
+		nwModel:       nwModel,
+		exchangeRates: make(map[string]float64),
+	}
+
+	var writeModels []mongo.WriteModel
+	for _, uid := range userIDs {
+		objectUID, err := primitive.ObjectIDFromHex(uid)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"uid": uid,
+			}).Error("failed to parse user id for net worth: ", err)
+
+			continue
+		}
+
+		var user User
+		if err := nwModel.UserCollection.FindOne(context.TODO(), bson.M{"_id": objectUID}).Decode(&user); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"uid": uid,
+			}).Error("failed to find user for net worth: ", err)
+
+			continue
+		}
+
+		netWorth, err := calculator.calculateNetWorth(uid, user.Currency, totalAssets[uid])
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"uid": uid,
+			}).Error("failed to calculate daily net worth: ", err)
+
+			continue
+		}
+
+		writeModels = append(writeModels, getDailyStatsWriteModel(bson.M{
+			"user_id": objectUID,
+		}, today, responses.DailyNetWorthCalculation{
+			UserID:    objectUID,
+			CreatedAt: today,
+			NetWorth:  netWorth,
+		}))
+	}
+
+	if err := bulkWriteDailyStats(nwModel.Collection, writeModels); err != nil {
+		logrus.Error("failed to upsert daily net worth: ", err)
+	}
+}
+
+func (nwModel *NetWorthModel) DeleteAllNetWorthStatsByUserID(uid string) error {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	if _, err := nwModel.Collection.DeleteMany(context.TODO(), bson.M{
+		"user_id": objectUID,
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to delete all net worth stats by user id: ", err)
+
+		return fmt.Errorf("Failed to delete all net worth stats by user id.")
+	}
+
+	return nil
+}
+
+func (calculator *netWorthCalculator) calculateNetWorth(uid, currency string, totalAssets float64) (responses.NetWorth, error) {
+	nwModel := calculator.nwModel
+
+	var bankAccounts []BankAccount
+	if err := nwModel.findNetWorthDocuments(nwModel.BankAccountCollection, uid, &bankAccounts); err != nil {
+		return responses.NetWorth{}, err
+	}
+
+	var cards []Card
+	if err := nwModel.findNetWorthDocuments(nwModel.CardCollection, uid, &cards); err != nil {
+		return responses.NetWorth{}, err
+	}
+
+	methodTotals, err := nwModel.getTransactionMethodTotals(uid)
+	if err != nil {
+		return responses.NetWorth{}, err
+	}
+
+	netWorth := responses.NetWorth{
+		Currency:     currency,
+		TotalAssets:  totalAssets,
+		BankAccounts: []responses.NetWorthAccount{},
+		Cards:        []responses.NetWorthAccount{},
+	}
+
+	for _, bankAccount := range bankAccounts {
+		account, err := calculator.getNetWorthAccount(
+			bankAccount.ID.Hex(), bankAccount.Name, bankAccount.Currency, currency,
+			bankAccount.OpeningBalance, BankAcc, methodTotals,
+		)
+		if err != nil {
+			return responses.NetWorth{}, err
+		}
+
+		netWorth.TotalBankBalance += account.ConvertedBalance
+		netWorth.BankAccounts = append(netWorth.BankAccounts, account)
+	}
+
+	for _, card := range cards {
+		account, err := calculator.getNetWorthAccount(
+			card.ID.Hex(), card.Name, card.Currency, currency,
+			card.OpeningBalance, CreditCard, methodTotals,
+		)
+		if err != nil {
+			return responses.NetWorth{}, err
+		}
+
+		netWorth.TotalCardBalance += account.ConvertedBalance
+		netWorth.Cards = append(netWorth.Cards, account)
+	}
+
+	netWorth.NetWorth = netWorth.TotalAssets + netWorth.TotalBankBalance - netWorth.TotalCardBalance
+
+	return netWorth, nil
+}
+
+func (calculator *netWorthCalculator) getNetWorthAccount(
+	id, name, accountCurrency, currency string, openingBalance float64,
+	methodType int64, methodTotals []transactionMethodTotal,
+) (responses.NetWorthAccount, error) {
+	account := responses.NetWorthAccount{
+		ID:       id,
+		Name:     name,
+		Currency: accountCurrency,
+		Balance:  openingBalance,
+	}
+
+	for _, methodTotal := range methodTotals {
+		if methodTotal.Key.MethodID != id || methodTotal.Key.Type != methodType {
+			continue
+		}
+
+		exchangeRate, err := calculator.getExchangeRate(methodTotal.Key.Currency, accountCurrency)
+		if err != nil {
+			return responses.NetWorthAccount{}, err
+		}
+
+		// Totals are signed for bank accounts, spendings increase card balance.
+		if methodType == CreditCard {
+			account.Balance -= methodTotal.Total * exchangeRate
+		} else {
+			account.Balance += methodTotal.Total * exchangeRate
+		}
+	}
+
+	exchangeRate, err := calculator.getExchangeRate(accountCurrency, currency)
+	if err != nil {
+		return responses.NetWorthAccount{}, err
+	}
+
+	account.ConvertedBalance = account.Balance * exchangeRate
+
+	return account, nil
+}
+
+func (calculator *netWorthCalculator) getExchangeRate(fromCurrency, toCurrency string) (float64, error) {
+	key := fromCurrency + toCurrency
+
+	if exchangeRate, ok := calculator.exchangeRates[key]; ok {
+		return exchangeRate, nil
+	}
+
+	exchangeRate, err := getExchangeRate(calculator.nwModel.ExchangeCollection, fromCurrency, toCurrency)
+	if err != nil {
+		return 0, err
+	}
+
+	calculator.exchangeRates[key] = exchangeRate
+
+	return exchangeRate, nil
+}
+
+// getTransactionMethodTotals sums the user's transactions by method and
+// currency, income is positive and the other categories are negative.
+func (nwModel *NetWorthModel) getTransactionMethodTotals(uid string) ([]transactionMethodTotal, error) {
+	match := bson.M{"$match": bson.M{
+		"user_id": uid,
+		"method":  bson.M{"$ne": nil},
+	}}
+	group := bson.M{"$group": bson.M{
+		"_id": bson.M{
+			"method_id": "$method.method_id",
+			"type":      "$method.type",
+			"currency":  "$currency",
+		},
+		"total": bson.M{
+			"$sum": bson.M{
+				"$cond": bson.A{
+					bson.M{"$eq": bson.A{"$category", Income}},
+					"$price",
+					bson.M{"$multiply": bson.A{"$price", -1}},
+				},
+			},
+		},
+	}}
+
+	cursor, err := nwModel.TransactionCollection.Aggregate(context.TODO(), bson.A{match, group})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to aggregate transaction method totals: ", err)
+
+		return nil, fmt.Errorf("Failed to aggregate transaction totals.")
+	}
+
+	var methodTotals []transactionMethodTotal
+	if err = cursor.All(context.TODO(), &methodTotals); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode transaction method totals: ", err)
+
+		return nil, fmt.Errorf("Failed to decode transaction totals.")
+	}
+
+	return methodTotals, nil
+}
+
+func (nwModel *NetWorthModel) findNetWorthDocuments(collection *mongo.Collection, uid string, results interface{}) error {
+	cursor, err := collection.Find(context.TODO(), bson.M{
+		"user_id": uid,
+	}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"collection": collection.Name(),
+		}).Error("failed to find documents for net worth: ", err)
+
+		return fmt.Errorf("Failed to find accounts for net worth.")
+	}
+
+	if err = cursor.All(context.TODO(), results); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"collection": collection.Name(),
+		}).Error("failed to decode documents for net worth: ", err)
+
+		return fmt.Errorf("Failed to decode accounts for net worth.")
+	}
+
+	return nil
+}
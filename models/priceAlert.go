@@ -0,0 +1,417 @@
+package models
+
+import (
+	"asset_backend/db"
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PriceAlertModel struct {
+	Collection *mongo.Collection
+}
+
+func NewPriceAlertModel(mongoDB *db.MongoDB) *PriceAlertModel {
+	return &PriceAlertModel{
+		Collection: mongoDB.Database.Collection("price-alerts"),
+	}
+}
+
+/**
+* Above/below alerts compare the current price with value. Change alerts compare
+* it with the reference price, which is renewed every 24 hours, and trigger when
+* the change in percentage reaches value in either direction.
+*
+* One-shot alerts are deactivated after they trigger. Recurring alerts stay
+* active and trigger again once the condition stops holding and holds again.
+**/
+type PriceAlert struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	UserID          string             `bson:"user_id" json:"user_id"`
+	InvestingID     InvestingID        `bson:"investing_id" json:"investing_id"`
+	AlertType       string             `bson:"alert_type" json:"alert_type"`
+	Value           float64            `bson:"value" json:"value"`
+	IsRecurring     bool               `bson:"is_recurring" json:"is_recurring"`
+	SendEmail       bool               `bson:"send_email" json:"send_email"`
+	IsActive        bool               `bson:"is_active" json:"is_active"`
+	IsTriggered     bool               `bson:"is_triggered" json:"-"`
+	ReferencePrice  *float64           `bson:"reference_price" json:"-"`
+	ReferenceDate   *time.Time         `bson:"reference_date" json:"-"`
+	LastTriggeredAt *time.Time         `bson:"last_triggered_at" json:"last_triggered_at"`
+	CreatedAt       time.Time          `bson:"created_at" json:"-"`
+}
+
+type PriceAlertNotification struct {
+	AlertID      string
+	InvestingID  InvestingID
+	Name         string
+	AlertType    string
+	Value        float64
+	Price        float64
+	Change       float64
+	Currency     string
+	FCMToken     string
+	EmailAddress string
+}
+
+const (
+	PriceAlertAbove  = "above"
+	PriceAlertBelow  = "below"
+	PriceAlertChange = "change"
+
+	priceAlertChangePeriod = 24 * time.Hour
+)
+
+func createPriceAlert(uid string, data requests.PriceAlertCreate) *PriceAlert {
+	return &PriceAlert{
+		UserID: uid,
+		InvestingID: InvestingID{
+			Symbol: data.Symbol,
+			Type:   data.Type,
+			Market: data.Market,
+		},
+		AlertType:   data.AlertType,
+		Value:       data.Value,
+		IsRecurring: data.IsRecurring,
+		SendEmail:   data.SendEmail,
+		IsActive:    true,
+		IsTriggered: false,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+func (priceAlertModel *PriceAlertModel) CreatePriceAlert(uid string, data requests.PriceAlertCreate) (PriceAlert, error) {
+	priceAlert := createPriceAlert(uid, data)
+
+	insertedID, err := priceAlertModel.Collection.InsertOne(context.TODO(), priceAlert)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":  uid,
+			"data": data,
+		}).Error("failed to create new price alert: ", err)
+
+		return PriceAlert{}, fmt.Errorf("Failed to create new price alert.")
+	}
+
+	priceAlert.ID = insertedID.InsertedID.(primitive.ObjectID)
+
+	return *priceAlert, nil
+}
+
+func (priceAlertModel *PriceAlertModel) GetPriceAlertCount(uid string) int64 {
+	count, err := priceAlertModel.Collection.CountDocuments(context.TODO(), bson.M{"user_id": uid})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to count user price alerts: ", err)
+
+		return countErrorFallback
+	}
+
+	return count
+}
+
+func (priceAlertModel *PriceAlertModel) GetPriceAlertByID(priceAlertID string) (PriceAlert, error) {
+	objectPriceAlertID, _ := primitive.ObjectIDFromHex(priceAlertID)
+
+	result := priceAlertModel.Collection.FindOne(context.TODO(), bson.M{"_id": objectPriceAlertID})
+
+	var priceAlert PriceAlert
+	if err := result.Decode(&priceAlert); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"price_alert_id": priceAlertID,
+		}).Error("failed to find price alert by price alert id: ", err)
+
+		return PriceAlert{}, fmt.Errorf("Failed to find price alert by price alert id.")
+	}
+
+	return priceAlert, nil
+}
+
+func (priceAlertModel *PriceAlertModel) GetPriceAlertsByUserID(uid string) ([]responses.PriceAlert, error) {
+	match := bson.M{"$match": bson.M{
+		"user_id": uid,
+	}}
+	sort := bson.M{"$sort": bson.M{
+		"created_at": -1,
+	}}
+
+	cursor, err := priceAlertModel.Collection.Aggregate(context.TODO(), bson.A{
+		match, getPriceAlertInvestingLookup(), getPriceAlertInvestingUnwind(true), addPriceAlertInvestingFields(), sort,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to aggregate price alerts: ", err)
+
+		return nil, fmt.Errorf("Failed to aggregate price alerts.")
+	}
+
+	var priceAlerts []responses.PriceAlert
+	if err = cursor.All(context.TODO(), &priceAlerts); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode price alerts: ", err)
+
+		return nil, fmt.Errorf("Failed to decode price alerts.")
+	}
+
+	return priceAlerts, nil
+}
+
+func (priceAlertModel *PriceAlertModel) UpdatePriceAlert(data requests.PriceAlertUpdate, priceAlert PriceAlert) (PriceAlert, error) {
+	if data.Value != nil {
+		priceAlert.Value = *data.Value
+	}
+
+	if data.IsRecurring != nil {
+		priceAlert.IsRecurring = *data.IsRecurring
+	}
+
+	if data.SendEmail != nil {
+		priceAlert.SendEmail = *data.SendEmail
+	}
+
+	if data.IsActive != nil {
+		priceAlert.IsActive = *data.IsActive
+	}
+
+	// The alert is evaluated from scratch with the new settings.
+	priceAlert.IsTriggered = false
+	priceAlert.ReferencePrice = nil
+	priceAlert.ReferenceDate = nil
+
+	if _, err := priceAlertModel.Collection.UpdateOne(context.TODO(), bson.M{
+		"_id": priceAlert.ID,
+	}, bson.M{"$set": priceAlert}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"price_alert_id": data.ID,
+			"data":           data,
+		}).Error("failed to update price alert: ", err)
+
+		return PriceAlert{}, fmt.Errorf("Failed to update price alert.")
+	}
+
+	return priceAlert, nil
+}
+
+// EvaluatePriceAlerts checks every active alert against the current price of
+// its investing, updates the alert states and returns the alerts to deliver.
+func (priceAlertModel *PriceAlertModel) EvaluatePriceAlerts() []PriceAlertNotification {
+	match := bson.M{"$match": bson.M{
+		"is_active": true,
+	}}
+	uidToObject := bson.M{"$addFields": bson.M{
+		"user_object_id": bson.M{
+			"$toObjectId": "$user_id",
+		},
+	}}
+	userLookup := bson.M{"$lookup": bson.M{
+		"from":         "users",
+		"localField":   "user_object_id",
+		"foreignField": "_id",
+		"as":           "user",
+	}}
+	unwindUser := bson.M{"$unwind": bson.M{
+		"path":                       "$user",
+		"includeArrayIndex":          "index",
+		"preserveNullAndEmptyArrays": false,
+	}}
+
+	cursor, err := priceAlertModel.Collection.Aggregate(context.TODO(), bson.A{
+		match, getPriceAlertInvestingLookup(), getPriceAlertInvestingUnwind(false), addPriceAlertInvestingFields(),
+		uidToObject, userLookup, unwindUser,
+	})
+	if err != nil {
+		logrus.Error("failed to aggregate active price alerts: ", err)
+		return nil
+	}
+
+	var priceAlerts []struct {
+		PriceAlert `bson:",inline"`
+		Name       string  `bson:"name"`
+		Price      float64 `bson:"price"`
+		Currency   string  `bson:"currency"`
+		User       User    `bson:"user"`
+	}
+
+	if err = cursor.All(context.TODO(), &priceAlerts); err != nil {
+		logrus.Error("failed to decode active price alerts: ", err)
+		return nil
+	}
+
+	now := time.Now().UTC()
+
+	var notifications []PriceAlertNotification
+
+	for _, priceAlert := range priceAlerts {
+		var (
+			isConditionMet bool
+			change         float64
+			update         = bson.M{}
+		)
+
+		switch priceAlert.AlertType {
+		case PriceAlertAbove:
+			isConditionMet = priceAlert.Price >= priceAlert.Value
+		case PriceAlertBelow:
+			isConditionMet = priceAlert.Price <= priceAlert.Value
+		case PriceAlertChange:
+			if priceAlert.ReferencePrice == nil || *priceAlert.ReferencePrice == 0 ||
+				priceAlert.ReferenceDate == nil || now.Sub(*priceAlert.ReferenceDate) >= priceAlertChangePeriod {
+				update["reference_price"] = priceAlert.Price
+				update["reference_date"] = now
+				update["is_triggered"] = false
+
+				break
+			}
+
+			change = (priceAlert.Price - *priceAlert.ReferencePrice) / *priceAlert.ReferencePrice * 100
+			isConditionMet = math.Abs(change) >= priceAlert.Value
+
+			// The next change is measured from the price it triggered at.
+			if isConditionMet {
+				update["reference_price"] = priceAlert.Price
+				update["reference_date"] = now
+			}
+		}
+
+		isNotified := isConditionMet && !priceAlert.IsTriggered
+		if isNotified {
+			// Change alerts are re-armed by moving the reference price instead.
+			update["last_triggered_at"] = now
+			update["is_triggered"] = priceAlert.IsRecurring && priceAlert.AlertType != PriceAlertChange
+			update["is_active"] = priceAlert.IsRecurring
+		} else if !isConditionMet && priceAlert.IsTriggered {
+			update["is_triggered"] = false
+		}
+
+		if len(update) > 0 {
+			if _, err := priceAlertModel.Collection.UpdateOne(context.TODO(), bson.M{
+				"_id": priceAlert.ID,
+			}, bson.M{"$set": update}); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"price_alert_id": priceAlert.ID,
+				}).Error("failed to update price alert state: ", err)
+
+				continue
+			}
+		}
+
+		if !isNotified {
+			continue
+		}
+
+		notification := PriceAlertNotification{
+			AlertID:     priceAlert.ID.Hex(),
+			InvestingID: priceAlert.InvestingID,
+			Name:        priceAlert.Name,
+			AlertType:   priceAlert.AlertType,
+			Value:       priceAlert.Value,
+			Price:       priceAlert.Price,
+			Change:      change,
+			Currency:    priceAlert.Currency,
+		}
+
+		if priceAlert.User.AppNotification {
+			notification.FCMToken = priceAlert.User.FCMToken
+		}
+
+		if priceAlert.SendEmail && priceAlert.User.MailNotification {
+			notification.EmailAddress = priceAlert.User.EmailAddress
+		}
+
+		if notification.FCMToken != "" || notification.EmailAddress != "" {
+			notifications = append(notifications, notification)
+		}
+	}
+
+	return notifications
+}
+
+func (priceAlertModel *PriceAlertModel) DeletePriceAlertByID(uid, priceAlertID string) (bool, error) {
+	objectPriceAlertID, _ := primitive.ObjectIDFromHex(priceAlertID)
+
+	count, err := priceAlertModel.Collection.DeleteOne(context.TODO(), bson.M{
+		"_id":     objectPriceAlertID,
+		"user_id": uid,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":            uid,
+			"price_alert_id": priceAlertID,
+		}).Error("failed to delete price alert by price alert id: ", err)
+
+		return false, fmt.Errorf("Failed to delete price alert by price alert id.")
+	}
+
+	return count.DeletedCount > 0, nil
+}
+
+func (priceAlertModel *PriceAlertModel) DeleteAllPriceAlertsByUserID(uid string) error {
+	if _, err := priceAlertModel.Collection.DeleteMany(context.TODO(), bson.M{
+		"user_id": uid,
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to delete all price alerts by user id: ", err)
+
+		return fmt.Errorf("Failed to delete all price alerts by user id.")
+	}
+
+	return nil
+}
+
+func getPriceAlertInvestingLookup() bson.M {
+	return bson.M{"$lookup": bson.M{
+		"from": "investings",
+		"let": bson.M{
+			"symbol": "$investing_id.symbol",
+			"type":   "$investing_id.type",
+			"market": "$investing_id.market",
+		},
+		"pipeline": bson.A{
+			bson.M{
+				"$match": bson.M{
+					"$expr": bson.M{
+						"$and": bson.A{
+							bson.M{"$eq": bson.A{"$_id.symbol", "$$symbol"}},
+							bson.M{"$eq": bson.A{"$_id.type", "$$type"}},
+							bson.M{"$eq": bson.A{"$_id.market", "$$market"}},
+						},
+					},
+				},
+			},
+		},
+		"as": "investing",
+	}}
+}
+
+func getPriceAlertInvestingUnwind(preserveNull bool) bson.M {
+	return bson.M{"$unwind": bson.M{
+		"path":                       "$investing",
+		"includeArrayIndex":          "index",
+		"preserveNullAndEmptyArrays": preserveNull,
+	}}
+}
+
+func addPriceAlertInvestingFields() bson.M {
+	return bson.M{"$addFields": bson.M{
+		"name":  "$investing.name",
+		"price": "$investing.price",
+		"currency": bson.M{
+			"$ifNull": bson.A{
+				"$investing._id.stock_currency",
+				"USD",
+			},
+		},
+	}}
+}
@@ -0,0 +1,404 @@
+package models
+
+import (
+	"asset_backend/db"
+	"asset_backend/responses"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PurchaseModel struct {
+	Collection     *mongo.Collection
+	UserCollection *mongo.Collection
+}
+
+func NewPurchaseModel(mongoDB *db.MongoDB) *PurchaseModel {
+	return &PurchaseModel{
+		Collection:     mongoDB.Database.Collection("purchases"),
+		UserCollection: mongoDB.Database.Collection("users"),
+	}
+}
+
+/**
+* Purchases are created from store verified receipts, membership is only
+* derived from them. Transaction ID is the original transaction of App Store
+* subscriptions and the purchase token of Play Store, so renewals update the
+* same purchase. Receipt is kept to verify the purchase again when it expires.
+**/
+type MembershipPurchase struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	UserID        string             `bson:"user_id" json:"user_id"`
+	Platform      string             `bson:"platform" json:"platform"`
+	ProductID     string             `bson:"product_id" json:"product_id"`
+	TransactionID string             `bson:"transaction_id" json:"transaction_id"`
+	Receipt       string             `bson:"receipt" json:"-"`
+	IsLifetime    bool               `bson:"is_lifetime" json:"is_lifetime"`
+	IsExpired     bool               `bson:"is_expired" json:"is_expired"`
+	PurchasedAt   time.Time          `bson:"purchased_at" json:"purchased_at"`
+	ExpiresAt     *time.Time         `bson:"expires_at" json:"expires_at"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ReceiptVerification is the store's response for a receipt, verifiers decide
+// if the product is a lifetime product.
+type ReceiptVerification struct {
+	ProductID     string
+	TransactionID string
+	IsLifetime    bool
+	PurchasedAt   time.Time
+	ExpiresAt     *time.Time
+}
+
+const (
+	PurchasePlatformAppStore  = "app_store"
+	PurchasePlatformPlayStore = "play_store"
+)
+
+const legacyMembershipGracePeriod = 30 * 24 * time.Hour
+
+// ErrPurchaseOwner is returned when the transaction is already saved for
+// another user.
+var ErrPurchaseOwner = errors.New("Purchase belongs to another user.")
+
+// CreatePurchaseIndexes creates the unique index of store transactions, it
+// keeps a transaction from being saved for more than one user.
+func (purchaseModel *PurchaseModel) CreatePurchaseIndexes() error {
+	if _, err := purchaseModel.Collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "platform", Value: 1}, {Key: "transaction_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		logrus.Error("failed to create purchase indexes: ", err)
+
+		return fmt.Errorf("Failed to create purchase indexes.")
+	}
+
+	return nil
+}
+
+func (purchaseModel *PurchaseModel) GetPurchaseByTransactionID(platform, transactionID string) (MembershipPurchase, error) {
+	result := purchaseModel.Collection.FindOne(context.TODO(), bson.M{
+		"platform":       platform,
+		"transaction_id": transactionID,
+	})
+
+	var purchase MembershipPurchase
+	if err := result.Decode(&purchase); err != nil {
+		if err != mongo.ErrNoDocuments {
+			logrus.WithFields(logrus.Fields{
+				"platform":       platform,
+				"transaction_id": transactionID,
+			}).Error("failed to find purchase by transaction id: ", err)
+		}
+
+		return MembershipPurchase{}, fmt.Errorf("Failed to find purchase by transaction id.")
+	}
+
+	return purchase, nil
+}
+
+func (purchaseModel *PurchaseModel) GetPurchasesByUserID(uid string) ([]MembershipPurchase, error) {
+	cursor, err := purchaseModel.Collection.Find(context.TODO(), bson.M{
+		"user_id": uid,
+	}, options.Find().SetSort(bson.M{"purchased_at": -1}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find purchases by user id: ", err)
+
+		return nil, fmt.Errorf("Failed to find purchases.")
+	}
+
+	var purchases []MembershipPurchase
+	if err = cursor.All(context.TODO(), &purchases); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode purchases: ", err)
+
+		return nil, fmt.Errorf("Failed to decode purchases.")
+	}
+
+	return purchases, nil
+}
+
+// GetExpiredPurchases returns the subscriptions that passed their expiry but
+// weren't verified again yet.
+func (purchaseModel *PurchaseModel) GetExpiredPurchases() ([]MembershipPurchase, error) {
+	cursor, err := purchaseModel.Collection.Find(context.TODO(), bson.M{
+		"is_lifetime": false,
+		"is_expired":  false,
+		"expires_at":  bson.M{"$lte": time.Now().UTC()},
+	})
+	if err != nil {
+		logrus.Error("failed to find expired purchases: ", err)
+
+		return nil, fmt.Errorf("Failed to find expired purchases.")
+	}
+
+	var purchases []MembershipPurchase
+	if err = cursor.All(context.TODO(), &purchases); err != nil {
+		logrus.Error("failed to decode expired purchases: ", err)
+
+		return nil, fmt.Errorf("Failed to decode expired purchases.")
+	}
+
+	return purchases, nil
+}
+
+// UpsertPurchase saves the verified purchase of the user. User is part of the
+// filter, so a transaction of another user conflicts on the unique index
+// instead of changing owner.
+func (purchaseModel *PurchaseModel) UpsertPurchase(uid, platform, receipt string, verification ReceiptVerification) (MembershipPurchase, error) {
+	now := time.Now().UTC()
+	purchase := newMembershipPurchase(uid, platform, receipt, verification, now)
+
+	if err := purchaseModel.Collection.FindOneAndUpdate(context.TODO(), bson.M{
+		"user_id":        uid,
+		"platform":       platform,
+		"transaction_id": verification.TransactionID,
+	}, bson.M{
+		"$set": bson.M{
+			"product_id":   purchase.ProductID,
+			"receipt":      purchase.Receipt,
+			"is_lifetime":  purchase.IsLifetime,
+			"is_expired":   purchase.IsExpired,
+			"purchased_at": purchase.PurchasedAt,
+			"expires_at":   purchase.ExpiresAt,
+			"updated_at":   purchase.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": now,
+		},
+	}, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&purchase); err != nil {
+		if isPurchaseOwnerConflict(err) {
+			return MembershipPurchase{}, ErrPurchaseOwner
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"uid":            uid,
+			"platform":       platform,
+			"transaction_id": verification.TransactionID,
+		}).Error("failed to upsert purchase: ", err)
+
+		return MembershipPurchase{}, fmt.Errorf("Failed to save purchase.")
+	}
+
+	return purchase, nil
+}
+
+func newMembershipPurchase(uid, platform, receipt string, verification ReceiptVerification, now time.Time) MembershipPurchase {
+	purchase := MembershipPurchase{
+		UserID:        uid,
+		Platform:      platform,
+		ProductID:     verification.ProductID,
+		TransactionID: verification.TransactionID,
+		Receipt:       receipt,
+		IsLifetime:    verification.IsLifetime,
+		PurchasedAt:   verification.PurchasedAt,
+		UpdatedAt:     now,
+	}
+
+	if !purchase.IsLifetime {
+		purchase.ExpiresAt = verification.ExpiresAt
+		purchase.IsExpired = verification.ExpiresAt == nil || !verification.ExpiresAt.After(now)
+	}
+
+	return purchase
+}
+
+func isPurchaseOwnerConflict(err error) bool {
+	return mongo.IsDuplicateKeyError(err)
+}
+
+// SetPurchaseExpired marks a subscription that wasn't renewed.
+func (purchaseModel *PurchaseModel) SetPurchaseExpired(purchase MembershipPurchase) error {
+	if _, err := purchaseModel.Collection.UpdateOne(context.TODO(), bson.M{
+		"_id": purchase.ID,
+	}, bson.M{"$set": bson.M{
+		"is_expired": true,
+		"updated_at": time.Now().UTC(),
+	}}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"purchase_id": purchase.ID,
+		}).Error("failed to set purchase expired: ", err)
+
+		return fmt.Errorf("Failed to set purchase expired.")
+	}
+
+	return nil
+}
+
+// UpdateUserMembershipByPurchases sets user's membership from the active
+// purchases, users without them aren't premium. Legacy memberships, set before
+// purchases were verified, are kept until their grace period ends.
+func (purchaseModel *PurchaseModel) UpdateUserMembershipByPurchases(uid string) (responses.IsUserPremium, error) {
+	purchases, err := purchaseModel.GetPurchasesByUserID(uid)
+	if err != nil {
+		return responses.IsUserPremium{}, err
+	}
+
+	legacyMembership, err := purchaseModel.getLegacyMembership(uid)
+	if err != nil {
+		return responses.IsUserPremium{}, err
+	}
+
+	now := time.Now().UTC()
+	membership := getMembershipByPurchases(purchases, now)
+
+	isLegacyExpired := legacyMembership.ExpiresAt != nil && !legacyMembership.ExpiresAt.After(now)
+	if legacyMembership.ExpiresAt != nil && !isLegacyExpired {
+		membership.IsPremium = membership.IsPremium || legacyMembership.IsPremium
+		membership.IsLifetimePremium = membership.IsLifetimePremium || legacyMembership.IsLifetimePremium
+	}
+
+	update := bson.M{"$set": bson.M{
+		"is_premium":          membership.IsPremium,
+		"is_lifetime_premium": membership.IsLifetimePremium,
+		"updated_at":          now,
+	}}
+	if isLegacyExpired {
+		update["$unset"] = bson.M{"legacy_premium_expires_at": ""}
+	}
+
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	if _, err := purchaseModel.UserCollection.UpdateOne(context.TODO(), bson.M{"_id": objectUID}, update); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":                 uid,
+			"is_premium":          membership.IsPremium,
+			"is_lifetime_premium": membership.IsLifetimePremium,
+		}).Error("failed to set membership for user: ", err)
+
+		return responses.IsUserPremium{}, fmt.Errorf("Failed to set membership for user.")
+	}
+
+	return membership, nil
+}
+
+// legacyMembership is the membership of user before purchases were verified.
+type legacyMembership struct {
+	IsPremium         bool       `bson:"is_premium"`
+	IsLifetimePremium bool       `bson:"is_lifetime_premium"`
+	ExpiresAt         *time.Time `bson:"legacy_premium_expires_at"`
+}
+
+func (purchaseModel *PurchaseModel) getLegacyMembership(uid string) (legacyMembership, error) {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	var membership legacyMembership
+	if err := purchaseModel.UserCollection.FindOne(context.TODO(), bson.M{"_id": objectUID}, options.FindOne().SetProjection(bson.M{
+		"is_premium":                1,
+		"is_lifetime_premium":       1,
+		"legacy_premium_expires_at": 1,
+	})).Decode(&membership); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find membership of user: ", err)
+
+		return legacyMembership{}, fmt.Errorf("Failed to find membership of user.")
+	}
+
+	return membership, nil
+}
+
+// SetLegacyMembershipExpiry gives premium users without purchases a grace
+// period to restore their purchase, their membership was set by the client.
+func (purchaseModel *PurchaseModel) SetLegacyMembershipExpiry() error {
+	userIDs, err := purchaseModel.Collection.Distinct(context.TODO(), "user_id", bson.M{})
+	if err != nil {
+		logrus.Error("failed to find users with purchases: ", err)
+
+		return fmt.Errorf("Failed to find users with purchases.")
+	}
+
+	objectUIDs := bson.A{}
+	for _, userID := range userIDs {
+		if uid, ok := userID.(string); ok {
+			if objectUID, err := primitive.ObjectIDFromHex(uid); err == nil {
+				objectUIDs = append(objectUIDs, objectUID)
+			}
+		}
+	}
+
+	if _, err := purchaseModel.UserCollection.UpdateMany(context.TODO(), bson.M{
+		"_id": bson.M{"$nin": objectUIDs},
+		"$or": bson.A{
+			bson.M{"is_premium": true},
+			bson.M{"is_lifetime_premium": true},
+		},
+		"legacy_premium_expires_at": bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{
+		"legacy_premium_expires_at": time.Now().UTC().Add(legacyMembershipGracePeriod),
+	}}); err != nil {
+		logrus.Error("failed to set legacy membership expiry: ", err)
+
+		return fmt.Errorf("Failed to set legacy membership expiry.")
+	}
+
+	return nil
+}
+
+// ExpireLegacyMemberships sets the membership of users whose grace period
+// ended from their purchases.
+func (purchaseModel *PurchaseModel) ExpireLegacyMemberships() {
+	cursor, err := purchaseModel.UserCollection.Find(context.TODO(), bson.M{
+		"$or": bson.A{
+			bson.M{"is_premium": true},
+			bson.M{"is_lifetime_premium": true},
+		},
+		"legacy_premium_expires_at": bson.M{"$lte": time.Now().UTC()},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logrus.Error("failed to find expired legacy memberships: ", err)
+
+		return
+	}
+
+	var users []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err = cursor.All(context.TODO(), &users); err != nil {
+		logrus.Error("failed to decode expired legacy memberships: ", err)
+
+		return
+	}
+
+	for _, user := range users {
+		purchaseModel.UpdateUserMembershipByPurchases(user.ID.Hex())
+	}
+}
+
+func getMembershipByPurchases(purchases []MembershipPurchase, now time.Time) responses.IsUserPremium {
+	var membership responses.IsUserPremium
+	for _, purchase := range purchases {
+		if purchase.IsLifetime {
+			membership.IsLifetimePremium = true
+		} else if !purchase.IsExpired && purchase.ExpiresAt != nil && purchase.ExpiresAt.After(now) {
+			membership.IsPremium = true
+		}
+	}
+
+	return membership
+}
+
+func (purchaseModel *PurchaseModel) DeleteAllPurchasesByUserID(uid string) error {
+	if _, err := purchaseModel.Collection.DeleteMany(context.TODO(), bson.M{
+		"user_id": uid,
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to delete all purchases by user id: ", err)
+
+		return fmt.Errorf("Failed to delete all purchases by user id.")
+	}
+
+	return nil
+}
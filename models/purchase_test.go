@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestNewMembershipPurchaseValid(t *testing.T) {
+	now := time.Now().UTC()
+	expiresAt := now.AddDate(0, 1, 0)
+
+	purchase := newMembershipPurchase("user", PurchasePlatformAppStore, "receipt", ReceiptVerification{
+		ProductID:     "premium_monthly",
+		TransactionID: "1000",
+		ExpiresAt:     &expiresAt,
+	}, now)
+
+	if purchase.IsLifetime || purchase.IsExpired {
+		t.Fatalf("expected an active subscription, got %+v", purchase)
+	}
+
+	membership := getMembershipByPurchases([]MembershipPurchase{purchase}, now)
+	if !membership.IsPremium || membership.IsLifetimePremium {
+		t.Fatalf("expected premium membership, got %+v", membership)
+	}
+}
+
+func TestNewMembershipPurchaseExpired(t *testing.T) {
+	now := time.Now().UTC()
+	expiresAt := now.AddDate(0, -1, 0)
+
+	purchase := newMembershipPurchase("user", PurchasePlatformAppStore, "receipt", ReceiptVerification{
+		ProductID:     "premium_monthly",
+		TransactionID: "1000",
+		ExpiresAt:     &expiresAt,
+	}, now)
+
+	if !purchase.IsExpired {
+		t.Fatalf("expected an expired subscription, got %+v", purchase)
+	}
+
+	membership := getMembershipByPurchases([]MembershipPurchase{purchase}, now)
+	if membership.IsPremium || membership.IsLifetimePremium {
+		t.Fatalf("expected no membership, got %+v", membership)
+	}
+}
+
+func TestNewMembershipPurchaseLifetime(t *testing.T) {
+	now := time.Now().UTC()
+	expiresAt := now.AddDate(0, -1, 0)
+
+	purchase := newMembershipPurchase("user", PurchasePlatformPlayStore, "receipt", ReceiptVerification{
+		ProductID:     "premium_lifetime",
+		TransactionID: "token",
+		IsLifetime:    true,
+		ExpiresAt:     &expiresAt,
+	}, now)
+
+	if !purchase.IsLifetime || purchase.IsExpired || purchase.ExpiresAt != nil {
+		t.Fatalf("expected a lifetime purchase, got %+v", purchase)
+	}
+
+	membership := getMembershipByPurchases([]MembershipPurchase{purchase}, now)
+	if !membership.IsLifetimePremium {
+		t.Fatalf("expected lifetime membership, got %+v", membership)
+	}
+}
+
+func TestPurchaseOwnerConflict(t *testing.T) {
+	duplicateKeyErrors := []error{
+		mongo.CommandError{Code: 11000, Message: "E11000 duplicate key error"},
+		mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}},
+	}
+
+	for _, err := range duplicateKeyErrors {
+		if !isPurchaseOwnerConflict(err) {
+			t.Fatalf("expected an owner conflict for %v", err)
+		}
+	}
+
+	if isPurchaseOwnerConflict(mongo.ErrNoDocuments) {
+		t.Fatal("expected no owner conflict")
+	}
+}
@@ -0,0 +1,396 @@
+package models
+
+import (
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/teambition/rrule-go"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RecurringTransaction struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	UserID            string             `bson:"user_id" json:"user_id"`
+	Title             string             `bson:"title" json:"title"`
+	Description       *string            `bson:"description" json:"description"`
+	Category          int64              `bson:"category" json:"category"`
+	CategoryID        *string            `bson:"category_id" json:"category_id"`
+	Price             float64            `bson:"price" json:"price"`
+	Currency          string             `bson:"currency" json:"currency"`
+	TransactionMethod *TransactionMethod `bson:"method" json:"method"`
+	Frequency         string             `bson:"frequency" json:"frequency"`
+	Interval          int                `bson:"interval" json:"interval"`
+	StartDate         time.Time          `bson:"start_date" json:"start_date"`
+	EndDate           *time.Time         `bson:"end_date" json:"end_date"`
+	IsPaused          bool               `bson:"is_paused" json:"is_paused"`
+	LastOccurrence    *time.Time         `bson:"last_occurrence" json:"last_occurrence"`
+	CreatedAt         time.Time          `bson:"created_at" json:"-"`
+}
+
+const recurringTransactionPreviewCount = 5
+
+var recurringTransactionFrequencies = map[string]rrule.Frequency{
+	"daily":   rrule.DAILY,
+	"weekly":  rrule.WEEKLY,
+	"monthly": rrule.MONTHLY,
+	"yearly":  rrule.YEARLY,
+}
+
+func createRecurringTransaction(uid string, data requests.RecurringTransactionCreate) *RecurringTransaction {
+	var transactionMethod *TransactionMethod
+	if data.TransactionMethod != nil {
+		transactionMethod = createTransactionMethod(*data.TransactionMethod)
+	}
+
+	return &RecurringTransaction{
+		UserID:            uid,
+		Title:             data.Title,
+		Description:       data.Description,
+		Category:          *data.Category,
+		CategoryID:        data.CategoryID,
+		Price:             data.Price,
+		Currency:          data.Currency,
+		TransactionMethod: transactionMethod,
+		Frequency:         data.Frequency,
+		Interval:          data.Interval,
+		StartDate:         data.StartDate.UTC(),
+		EndDate:           data.EndDate,
+		IsPaused:          false,
+		CreatedAt:         time.Now().UTC(),
+	}
+}
+
+func (transactionModel *TransactionModel) CreateRecurringTransaction(uid string, data requests.RecurringTransactionCreate) (RecurringTransaction, error) {
+	recurringTransaction := createRecurringTransaction(uid, data)
+
+	insertedID, err := transactionModel.RecurringCollection.InsertOne(context.TODO(), recurringTransaction)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":  uid,
+			"data": data,
+		}).Error("failed to create new recurring transaction: ", err)
+
+		return RecurringTransaction{}, fmt.Errorf("Failed to create new recurring transaction.")
+	}
+
+	recurringTransaction.ID = insertedID.InsertedID.(primitive.ObjectID)
+
+	return *recurringTransaction, nil
+}
+
+func (transactionModel *TransactionModel) GetRecurringTransactionsByUserID(uid string) ([]RecurringTransaction, error) {
+	cursor, err := transactionModel.RecurringCollection.Find(context.TODO(), bson.M{
+		"user_id": uid,
+	}, options.Find().SetSort(bson.M{"start_date": 1}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find recurring transactions: ", err)
+
+		return nil, fmt.Errorf("Failed to find recurring transactions.")
+	}
+
+	var recurringTransactions []RecurringTransaction
+	if err = cursor.All(context.TODO(), &recurringTransactions); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode recurring transactions: ", err)
+
+		return nil, fmt.Errorf("Failed to decode recurring transactions.")
+	}
+
+	return recurringTransactions, nil
+}
+
+func (transactionModel *TransactionModel) GetRecurringTransactionByID(recurringID string) (RecurringTransaction, error) {
+	objectRecurringID, _ := primitive.ObjectIDFromHex(recurringID)
+
+	result := transactionModel.RecurringCollection.FindOne(context.TODO(), bson.M{"_id": objectRecurringID})
+
+	var recurringTransaction RecurringTransaction
+	if err := result.Decode(&recurringTransaction); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"recurring_id": recurringID,
+		}).Error("failed to find recurring transaction: ", err)
+
+		return RecurringTransaction{}, fmt.Errorf("Failed to find recurring transaction by id.")
+	}
+
+	return recurringTransaction, nil
+}
+
+func (transactionModel *TransactionModel) UpdateRecurringTransaction(
+	data requests.RecurringTransactionUpdate, recurringTransaction RecurringTransaction,
+) (RecurringTransaction, error) {
+	if data.Title != nil {
+		recurringTransaction.Title = *data.Title
+	}
+
+	recurringTransaction.Description = data.Description
+
+	if data.Category != nil {
+		recurringTransaction.Category = *data.Category
+		recurringTransaction.CategoryID = data.CategoryID
+	}
+
+	if data.Price != nil {
+		recurringTransaction.Price = *data.Price
+	}
+
+	if data.Currency != nil {
+		recurringTransaction.Currency = *data.Currency
+	}
+
+	if data.TransactionMethod != nil {
+		recurringTransaction.TransactionMethod = createTransactionMethod(*data.TransactionMethod)
+	}
+
+	if data.ShouldDeleteMethod != nil && *data.ShouldDeleteMethod {
+		recurringTransaction.TransactionMethod = nil
+	}
+
+	if data.Frequency != nil {
+		recurringTransaction.Frequency = *data.Frequency
+	}
+
+	if data.Interval != nil {
+		recurringTransaction.Interval = *data.Interval
+	}
+
+	if data.StartDate != nil {
+		recurringTransaction.StartDate = data.StartDate.UTC()
+	}
+
+	if data.EndDate != nil {
+		recurringTransaction.EndDate = data.EndDate
+	}
+
+	if data.ShouldDeleteEndDate != nil && *data.ShouldDeleteEndDate {
+		recurringTransaction.EndDate = nil
+	}
+
+	if _, err := transactionModel.RecurringCollection.UpdateOne(context.TODO(), bson.M{
+		"_id": recurringTransaction.ID,
+	}, bson.M{"$set": recurringTransaction}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"recurring_id": data.ID,
+			"data":         data,
+		}).Error("failed to update recurring transaction: ", err)
+
+		return RecurringTransaction{}, fmt.Errorf("Failed to update recurring transaction.")
+	}
+
+	return recurringTransaction, nil
+}
+
+// PauseRecurringTransaction pauses or resumes the template. Resuming moves the
+// last occurrence to now, occurrences missed while paused are not materialized.
+func (transactionModel *TransactionModel) PauseRecurringTransaction(
+	recurringTransaction RecurringTransaction, isPaused bool,
+) (RecurringTransaction, error) {
+	if recurringTransaction.IsPaused && !isPaused {
+		now := time.Now().UTC()
+		recurringTransaction.LastOccurrence = &now
+	}
+
+	recurringTransaction.IsPaused = isPaused
+
+	if _, err := transactionModel.RecurringCollection.UpdateOne(context.TODO(), bson.M{
+		"_id": recurringTransaction.ID,
+	}, bson.M{"$set": bson.M{
+		"is_paused":       recurringTransaction.IsPaused,
+		"last_occurrence": recurringTransaction.LastOccurrence,
+	}}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"recurring_id": recurringTransaction.ID,
+			"is_paused":    isPaused,
+		}).Error("failed to pause recurring transaction: ", err)
+
+		return RecurringTransaction{}, fmt.Errorf("Failed to pause recurring transaction.")
+	}
+
+	return recurringTransaction, nil
+}
+
+func (transactionModel *TransactionModel) GetRecurringTransactionPreview(
+	recurringTransaction RecurringTransaction, count int,
+) responses.RecurringTransactionPreview {
+	if count == 0 {
+		count = recurringTransactionPreviewCount
+	}
+
+	preview := responses.RecurringTransactionPreview{
+		RecurringID: recurringTransaction.ID.Hex(),
+		Occurrences: []time.Time{},
+	}
+
+	rule, err := getRecurringTransactionRule(recurringTransaction)
+	if err != nil {
+		return preview
+	}
+
+	occurrence := time.Now().UTC()
+	if recurringTransaction.LastOccurrence != nil && recurringTransaction.LastOccurrence.After(occurrence) {
+		occurrence = *recurringTransaction.LastOccurrence
+	}
+
+	for i := 0; i < count; i++ {
+		occurrence = rule.After(occurrence, false)
+		if occurrence.IsZero() {
+			break
+		}
+
+		preview.Occurrences = append(preview.Occurrences, occurrence)
+	}
+
+	return preview
+}
+
+// MaterializeRecurringTransactions creates the transactions of every active
+// template that became due since its last occurrence. Transactions are upserted
+// by recurring_id and transaction_date, so running it again creates no duplicates.
+func (transactionModel *TransactionModel) MaterializeRecurringTransactions() {
+	now := time.Now().UTC()
+
+	cursor, err := transactionModel.RecurringCollection.Find(context.TODO(), bson.M{
+		"is_paused":  false,
+		"start_date": bson.M{"$lte": now},
+	})
+	if err != nil {
+		logrus.Error("failed to find recurring transactions to materialize: ", err)
+		return
+	}
+
+	var recurringTransactions []RecurringTransaction
+	if err = cursor.All(context.TODO(), &recurringTransactions); err != nil {
+		logrus.Error("failed to decode recurring transactions to materialize: ", err)
+		return
+	}
+
+	for _, recurringTransaction := range recurringTransactions {
+		transactionModel.materializeRecurringTransaction(recurringTransaction, now)
+	}
+}
+
+func (transactionModel *TransactionModel) materializeRecurringTransaction(recurringTransaction RecurringTransaction, now time.Time) {
+	rule, err := getRecurringTransactionRule(recurringTransaction)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"recurring_id": recurringTransaction.ID,
+		}).Error("failed to create recurring transaction rule: ", err)
+
+		return
+	}
+
+	after := recurringTransaction.StartDate.Add(-time.Second)
+	if recurringTransaction.LastOccurrence != nil && recurringTransaction.LastOccurrence.After(after) {
+		after = *recurringTransaction.LastOccurrence
+	}
+
+	occurrences := rule.Between(after, now, false)
+	if len(occurrences) == 0 {
+		return
+	}
+
+	recurringID := recurringTransaction.ID.Hex()
+
+	for _, occurrence := range occurrences {
+		transaction := createTransaction(
+			recurringTransaction.UserID,
+			recurringTransaction.Title,
+			recurringTransaction.Currency,
+			recurringTransaction.Category,
+			recurringTransaction.Price,
+			occurrence,
+			recurringTransaction.TransactionMethod,
+			recurringTransaction.Description,
+		)
+		transaction.CategoryID = recurringTransaction.CategoryID
+		transaction.RecurringID = &recurringID
+
+		if _, err := transactionModel.Collection.UpdateOne(context.TODO(), bson.M{
+			"recurring_id":     recurringID,
+			"transaction_date": occurrence,
+		}, bson.M{
+			"$setOnInsert": transaction,
+		}, options.Update().SetUpsert(true)); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"recurring_id": recurringID,
+				"occurrence":   occurrence,
+			}).Error("failed to materialize recurring transaction: ", err)
+
+			return
+		}
+	}
+
+	lastOccurrence := occurrences[len(occurrences)-1]
+
+	if _, err := transactionModel.RecurringCollection.UpdateOne(context.TODO(), bson.M{
+		"_id": recurringTransaction.ID,
+	}, bson.M{"$set": bson.M{
+		"last_occurrence": lastOccurrence,
+	}}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"recurring_id":    recurringID,
+			"last_occurrence": lastOccurrence,
+		}).Error("failed to update last occurrence of recurring transaction: ", err)
+	}
+}
+
+func (transactionModel *TransactionModel) DeleteRecurringTransactionByID(uid, recurringID string) (bool, error) {
+	objectRecurringID, _ := primitive.ObjectIDFromHex(recurringID)
+
+	count, err := transactionModel.RecurringCollection.DeleteOne(context.TODO(), bson.M{
+		"_id":     objectRecurringID,
+		"user_id": uid,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":          uid,
+			"recurring_id": recurringID,
+		}).Error("failed to delete recurring transaction by id: ", err)
+
+		return false, fmt.Errorf("Failed to delete recurring transaction by id.")
+	}
+
+	return count.DeletedCount > 0, nil
+}
+
+func (transactionModel *TransactionModel) DeleteAllRecurringTransactionsByUserID(uid string) error {
+	if _, err := transactionModel.RecurringCollection.DeleteMany(context.TODO(), bson.M{
+		"user_id": uid,
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to delete all recurring transactions by user id: ", err)
+
+		return fmt.Errorf("Failed to delete all recurring transactions by user id.")
+	}
+
+	return nil
+}
+
+func getRecurringTransactionRule(recurringTransaction RecurringTransaction) (*rrule.RRule, error) {
+	freq, ok := recurringTransactionFrequencies[recurringTransaction.Frequency]
+	if !ok {
+		return nil, fmt.Errorf("Invalid frequency %s.", recurringTransaction.Frequency)
+	}
+
+	option := rrule.ROption{
+		Freq:     freq,
+		Interval: recurringTransaction.Interval,
+		Dtstart:  recurringTransaction.StartDate,
+	}
+
+	if recurringTransaction.EndDate != nil {
+		option.Until = *recurringTransaction.EndDate
+	}
+
+	return rrule.NewRRule(option)
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"asset_backend/db"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SessionModel struct {
+	Collection *mongo.Collection
+}
+
+func NewSessionModel(mongoDB *db.MongoDB) *SessionModel {
+	return &SessionModel{
+		Collection: mongoDB.Database.Collection("sessions"),
+	}
+}
+
+/**
+* Sessions are created per device on login and hold the hash of the refresh
+* token. Refresh tokens are rotated on every use, the previous hash is kept so
+* a reused token can be detected and the session revoked.
+**/
+type Session struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
+	UserID            string             `bson:"user_id" json:"user_id"`
+	DeviceID          string             `bson:"device_id" json:"device_id"`
+	DeviceName        string             `bson:"device_name" json:"device_name"`
+	UserAgent         string             `bson:"user_agent" json:"user_agent"`
+	IPAddress         string             `bson:"ip_address" json:"ip_address"`
+	RefreshTokenHash  string             `bson:"refresh_token_hash" json:"-"`
+	PreviousTokenHash string             `bson:"previous_token_hash" json:"-"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+	LastUsedAt        time.Time          `bson:"last_used_at" json:"last_used_at"`
+	ExpiresAt         time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// CreateSession replaces the user's session of the same device, so logging in
+// again from a device doesn't pile up sessions.
+func (sessionModel *SessionModel) CreateSession(session Session) (Session, error) {
+	now := time.Now().UTC()
+
+	if err := sessionModel.Collection.FindOneAndUpdate(context.TODO(), bson.M{
+		"user_id":   session.UserID,
+		"device_id": session.DeviceID,
+	}, bson.M{
+		"$set": bson.M{
+			"device_name":         session.DeviceName,
+			"user_agent":          session.UserAgent,
+			"ip_address":          session.IPAddress,
+			"refresh_token_hash":  session.RefreshTokenHash,
+			"previous_token_hash": "",
+			"created_at":          now,
+			"last_used_at":        now,
+			"expires_at":          session.ExpiresAt,
+		},
+	}, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&session); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":       session.UserID,
+			"device_id": session.DeviceID,
+		}).Error("failed to create session: ", err)
+
+		return Session{}, fmt.Errorf("Failed to create session.")
+	}
+
+	return session, nil
+}
+
+// GetSessionByTokenHash finds the session of the current or the previous
+// refresh token.
+func (sessionModel *SessionModel) GetSessionByTokenHash(tokenHash string) (Session, error) {
+	result := sessionModel.Collection.FindOne(context.TODO(), bson.M{
+		"$or": bson.A{
+			bson.M{"refresh_token_hash": tokenHash},
+			bson.M{"previous_token_hash": tokenHash},
+		},
+	})
+
+	var session Session
+	if err := result.Decode(&session); err != nil {
+		if err != mongo.ErrNoDocuments {
+			logrus.Error("failed to find session by token: ", err)
+		}
+
+		return Session{}, fmt.Errorf("Failed to find session.")
+	}
+
+	return session, nil
+}
+
+func (sessionModel *SessionModel) GetSessionsByUserID(uid string) ([]Session, error) {
+	cursor, err := sessionModel.Collection.Find(context.TODO(), bson.M{
+		"user_id":    uid,
+		"expires_at": bson.M{"$gt": time.Now().UTC()},
+	}, options.Find().SetSort(bson.M{"last_used_at": -1}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find sessions by user id: ", err)
+
+		return nil, fmt.Errorf("Failed to find sessions.")
+	}
+
+	var sessions []Session
+	if err = cursor.All(context.TODO(), &sessions); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode sessions: ", err)
+
+		return nil, fmt.Errorf("Failed to decode sessions.")
+	}
+
+	return sessions, nil
+}
+
+// RotateSessionToken replaces the refresh token only if it wasn't rotated
+// by another request in the meantime.
+func (sessionModel *SessionModel) RotateSessionToken(session Session, tokenHash string, expiresAt time.Time) (bool, error) {
+	result, err := sessionModel.Collection.UpdateOne(context.TODO(), bson.M{
+		"_id":                session.ID,
+		"refresh_token_hash": session.RefreshTokenHash,
+	}, bson.M{"$set": bson.M{
+		"refresh_token_hash":  tokenHash,
+		"previous_token_hash": session.RefreshTokenHash,
+		"last_used_at":        time.Now().UTC(),
+		"expires_at":          expiresAt,
+	}})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"session_id": session.ID,
+		}).Error("failed to rotate session token: ", err)
+
+		return false, fmt.Errorf("Failed to refresh session.")
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+func (sessionModel *SessionModel) DeleteSessionByID(uid, sessionID string) (bool, error) {
+	objectSessionID, _ := primitive.ObjectIDFromHex(sessionID)
+
+	result, err := sessionModel.Collection.DeleteOne(context.TODO(), bson.M{
+		"_id":     objectSessionID,
+		"user_id": uid,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        uid,
+			"session_id": sessionID,
+		}).Error("failed to delete session: ", err)
+
+		return false, fmt.Errorf("Failed to delete session.")
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// DeleteSessionsByUserID deletes user's sessions except the given one and
+// returns the IDs of the deleted sessions.
+func (sessionModel *SessionModel) DeleteSessionsByUserID(uid, exceptSessionID string) ([]string, error) {
+	filter := bson.M{"user_id": uid}
+	if objectSessionID, err := primitive.ObjectIDFromHex(exceptSessionID); err == nil {
+		filter["_id"] = bson.M{"$ne": objectSessionID}
+	}
+
+	cursor, err := sessionModel.Collection.Find(context.TODO(), filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find sessions to delete: ", err)
+
+		return nil, fmt.Errorf("Failed to delete sessions.")
+	}
+
+	var sessions []Session
+	if err = cursor.All(context.TODO(), &sessions); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to decode sessions to delete: ", err)
+
+		return nil, fmt.Errorf("Failed to delete sessions.")
+	}
+
+	sessionIDs := make([]string, len(sessions))
+	objectSessionIDs := make(bson.A, len(sessions))
+	for i, session := range sessions {
+		sessionIDs[i] = session.ID.Hex()
+		objectSessionIDs[i] = session.ID
+	}
+
+	if len(sessions) == 0 {
+		return sessionIDs, nil
+	}
+
+	if _, err := sessionModel.Collection.DeleteMany(context.TODO(), bson.M{
+		"_id": bson.M{"$in": objectSessionIDs},
+	}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to delete sessions by user id: ", err)
+
+		return nil, fmt.Errorf("Failed to delete sessions.")
+	}
+
+	return sessionIDs, nil
+}
+
+func (sessionModel *SessionModel) DeleteExpiredSessions() {
+	if _, err := sessionModel.Collection.DeleteMany(context.TODO(), bson.M{
+		"expires_at": bson.M{"$lte": time.Now().UTC()},
+	}); err != nil {
+		logrus.Error("failed to delete expired sessions: ", err)
+	}
+}
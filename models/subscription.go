@@ -68,8 +68,6 @@ type BillCycle struct {
 	Year  int `bson:"year" json:"year"`
 }
 
-const subscriptionPremiumLimit = 5
-
 func createSubscriptionObject(
 	uid, name, currency, color, image string,
 	cardID, description *string, price float64,
@@ -198,7 +196,7 @@ func (subscriptionModel *SubscriptionModel) GetUserSubscriptionCount(uid string)
 			"uid": uid,
 		}).Error("failed to count user subscriptions: ", err)
 
-		return subscriptionPremiumLimit
+		return countErrorFallback
 	}
 
 	return count
@@ -457,32 +455,7 @@ func (subscriptionModel *SubscriptionModel) GetCardStatisticsByUserIDAndCardID(u
 		"includeArrayIndex":          "index",
 		"preserveNullAndEmptyArrays": false,
 	}}
-	exchangeLookup := bson.M{"$lookup": bson.M{
-		"from": "exchanges",
-		"let": bson.M{
-			"card_currency": "$card.currency",
-			"sub_currency":  "$currency",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$cond": bson.A{
-							bson.M{"$ne": bson.A{"$$card_currency", "$$sub_currency"}},
-							bson.M{
-								"$and": bson.A{
-									bson.M{"$eq": bson.A{"$to_exchange", "$$card_currency"}},
-									bson.M{"$eq": bson.A{"$from_exchange", "$$sub_currency"}},
-								},
-							},
-							nil,
-						},
-					},
-				},
-			},
-		},
-		"as": "card_exchange_rate",
-	}}
+	exchangeLookup := getExchangeRateLookup("$currency", "$card.currency", "$bill_date", "card_exchange_rate")
 	unwindExchange := bson.M{"$unwind": bson.M{
 		"path":                       "$card_exchange_rate",
 		"includeArrayIndex":          "index",
@@ -16,12 +16,14 @@ import (
 )
 
 type TransactionModel struct {
-	Collection *mongo.Collection
+	Collection          *mongo.Collection
+	RecurringCollection *mongo.Collection
 }
 
 func NewTransactionModel(mongoDB *db.MongoDB) *TransactionModel {
 	return &TransactionModel{
-		Collection: mongoDB.Database.Collection("transactions"),
+		Collection:          mongoDB.Database.Collection("transactions"),
+		RecurringCollection: mongoDB.Database.Collection("recurring-transactions"),
 	}
 }
 
@@ -43,18 +45,18 @@ const (
 	CreditCard
 )
 
-const transactionPremiumLimit = 10
-
 type Transaction struct {
 	ID                primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
 	UserID            string             `bson:"user_id" json:"user_id"`
 	Title             string             `bson:"title" json:"title"`
 	Description       *string            `bson:"description" json:"description"`
 	Category          int64              `bson:"category" json:"category"`
+	CategoryID        *string            `bson:"category_id" json:"category_id"`
 	Price             float64            `bson:"price" json:"price"`
 	Currency          string             `bson:"currency" json:"currency"`
 	TransactionMethod *TransactionMethod `bson:"method" json:"method"`
 	TransactionDate   time.Time          `bson:"transaction_date" json:"transaction_date"`
+	RecurringID       *string            `bson:"recurring_id,omitempty" json:"recurring_id"`
 	CreatedAt         time.Time          `bson:"created_at" json:"-"`
 }
 
@@ -106,6 +108,7 @@ func (transactionModel *TransactionModel) CreateTransaction(uid string, data req
 		transactionMethod,
 		data.Description,
 	)
+	transaction.CategoryID = data.CategoryID
 
 	var (
 		insertedID *mongo.InsertOneResult
@@ -192,27 +195,7 @@ func (transactionModel *TransactionModel) GetTotalTransactionByInterval(uid stri
 		"includeArrayIndex":          "index",
 		"preserveNullAndEmptyArrays": true,
 	}}
-	userCurrencyExchangeLookup := bson.M{"$lookup": bson.M{
-		"from": "exchanges",
-		"let": bson.M{
-			"user_currency":        "$user.currency",
-			"transaction_currency": "$currency",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$and": bson.A{
-							bson.M{"$ne": bson.A{"$$transaction_currency", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$to_exchange", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$from_exchange", "TRY"}},
-						},
-					},
-				},
-			},
-		},
-		"as": "user_exchange_rate",
-	}}
+	userCurrencyExchangeLookup := getExchangeRateLookup("$currency", "$user.currency", "$transaction_date", "user_exchange_rate")
 	unwindUserCurrency := bson.M{"$unwind": bson.M{
 		"path":                       "$user_exchange_rate",
 		"includeArrayIndex":          "index",
@@ -228,18 +211,10 @@ func (transactionModel *TransactionModel) GetTotalTransactionByInterval(uid stri
 			},
 		},
 	}}
-	group := bson.M{"$group": bson.M{
-		"_id": "$user_id",
-		"currency": bson.M{
-			"$first": "$user.currency",
-		},
-		"total_transaction": bson.M{
-			"$sum": "$value",
-		},
-	}}
+	currencyGroup, group := getCurrencyBreakdownGroups("$user_id", bson.M{})
 
 	cursor, err := transactionModel.Collection.Aggregate(context.TODO(), bson.A{
-		match, uidToObject, userLookup, unwindUser, userCurrencyExchangeLookup, unwindUserCurrency, addExhangeValue, group,
+		match, uidToObject, userLookup, unwindUser, userCurrencyExchangeLookup, unwindUserCurrency, addExhangeValue, currencyGroup, group,
 	})
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -286,27 +261,7 @@ func (transactionModel *TransactionModel) GetMethodStatistics(uid string, data r
 		"includeArrayIndex":          "index",
 		"preserveNullAndEmptyArrays": true,
 	}}
-	userCurrencyExchangeLookup := bson.M{"$lookup": bson.M{
-		"from": "exchanges",
-		"let": bson.M{
-			"user_currency":        "$user.currency",
-			"transaction_currency": "$currency",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$and": bson.A{
-							bson.M{"$ne": bson.A{"$$transaction_currency", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$to_exchange", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$from_exchange", "TRY"}},
-						},
-					},
-				},
-			},
-		},
-		"as": "user_exchange_rate",
-	}}
+	userCurrencyExchangeLookup := getExchangeRateLookup("$currency", "$user.currency", "$transaction_date", "user_exchange_rate")
 	unwindUserCurrency := bson.M{"$unwind": bson.M{
 		"path":                       "$user_exchange_rate",
 		"includeArrayIndex":          "index",
@@ -322,18 +277,10 @@ func (transactionModel *TransactionModel) GetMethodStatistics(uid string, data r
 			},
 		},
 	}}
-	group := bson.M{"$group": bson.M{
-		"_id": "$user_id",
-		"currency": bson.M{
-			"$first": "$user.currency",
-		},
-		"total_transaction": bson.M{
-			"$sum": "$value",
-		},
-	}}
+	currencyGroup, group := getCurrencyBreakdownGroups("$user_id", bson.M{})
 
 	cursor, err := transactionModel.Collection.Aggregate(context.TODO(), bson.A{
-		match, uidToObject, userLookup, unwindUser, userCurrencyExchangeLookup, unwindUserCurrency, addExhangeValue, group,
+		match, uidToObject, userLookup, unwindUser, userCurrencyExchangeLookup, unwindUserCurrency, addExhangeValue, currencyGroup, group,
 	})
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -361,7 +308,12 @@ func (transactionModel *TransactionModel) GetMethodStatistics(uid string, data r
 	return responses.TransactionTotal{}, nil
 }
 
-func (transactionModel *TransactionModel) GetTransactionStats(uid string, data requests.TransactionStatsInterval) ([]responses.TransactionDailyStats, error) {
+// GetTransactionStats returns daily expenses. incomeCategoryIDs are user's
+// income category and its sub categories, transactions without category_id
+// are resolved by their built-in category.
+func (transactionModel *TransactionModel) GetTransactionStats(
+	uid string, data requests.TransactionStatsInterval, incomeCategoryIDs []string,
+) ([]responses.TransactionDailyStats, error) {
 	var intervalDate time.Time
 
 	switch data.Interval {
@@ -381,9 +333,6 @@ func (transactionModel *TransactionModel) GetTransactionStats(uid string, data r
 					time.Now().Year(),
 				},
 			},
-			"category": bson.M{
-				"$ne": Income,
-			},
 		}}
 	} else {
 		match = bson.M{"$match": bson.M{
@@ -391,12 +340,14 @@ func (transactionModel *TransactionModel) GetTransactionStats(uid string, data r
 			"transaction_date": bson.M{
 				"$gte": intervalDate,
 			},
-			"category": bson.M{
-				"$ne": Income,
-			},
 		}}
 	}
 
+	match["$match"].(bson.M)["$nor"] = bson.A{
+		bson.M{"category_id": bson.M{"$in": incomeCategoryIDs}},
+		bson.M{"category": Income, "category_id": nil},
+	}
+
 	addFields := bson.M{"$addFields": bson.M{
 		"user_id": bson.M{
 			"$toObjectId": "$user_id",
@@ -421,27 +372,7 @@ func (transactionModel *TransactionModel) GetTransactionStats(uid string, data r
 		"includeArrayIndex":          "index",
 		"preserveNullAndEmptyArrays": true,
 	}}
-	userCurrencyExchangeLookup := bson.M{"$lookup": bson.M{
-		"from": "exchanges",
-		"let": bson.M{
-			"user_currency":        "$user.currency",
-			"transaction_currency": "$currency",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$and": bson.A{
-							bson.M{"$ne": bson.A{"$$transaction_currency", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$to_exchange", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$from_exchange", "TRY"}},
-						},
-					},
-				},
-			},
-		},
-		"as": "user_exchange_rate",
-	}}
+	userCurrencyExchangeLookup := getExchangeRateLookup("$currency", "$user.currency", "$transaction_date", "user_exchange_rate")
 	unwindUserCurrency := bson.M{"$unwind": bson.M{
 		"path":                       "$user_exchange_rate",
 		"includeArrayIndex":          "index",
@@ -479,24 +410,15 @@ func (transactionModel *TransactionModel) GetTransactionStats(uid string, data r
 		}}
 	}
 
-	group := bson.M{"$group": bson.M{
-		"_id": "$transaction_date",
-		"currency": bson.M{
-			"$first": "$user.currency",
-		},
-		"total_transaction": bson.M{
-			"$sum": "$value",
-		},
-		"date": bson.M{
-			"$first": "$transaction_date",
-		},
-	}}
+	currencyGroup, group := getCurrencyBreakdownGroups("$transaction_date", bson.M{
+		"date": "$transaction_date",
+	})
 	sort := bson.M{"$sort": bson.M{
 		"_id": 1,
 	}}
 
 	cursor, err := transactionModel.Collection.Aggregate(context.TODO(), bson.A{
-		match, addFields, userLookup, unwindUser, userCurrencyExchangeLookup, unwindUserCurrency, addExhangeValue, group, sort,
+		match, addFields, userLookup, unwindUser, userCurrencyExchangeLookup, unwindUserCurrency, addExhangeValue, currencyGroup, group, sort,
 	})
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -547,7 +469,7 @@ func (transactionModel *TransactionModel) GetUserTransactionCountByTime(uid stri
 			"date": date,
 		}).Error("failed to count transactions by date: ", err)
 
-		return transactionPremiumLimit
+		return countErrorFallback
 	}
 
 	return count
@@ -650,27 +572,7 @@ func (transactionModel *TransactionModel) GetTransactionCategoryDistribution(uid
 		"includeArrayIndex":          "index",
 		"preserveNullAndEmptyArrays": true,
 	}}
-	userCurrencyExchangeLookup := bson.M{"$lookup": bson.M{
-		"from": "exchanges",
-		"let": bson.M{
-			"user_currency":        "$user.currency",
-			"transaction_currency": "$currency",
-		},
-		"pipeline": bson.A{
-			bson.M{
-				"$match": bson.M{
-					"$expr": bson.M{
-						"$and": bson.A{
-							bson.M{"$ne": bson.A{"$$transaction_currency", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$to_exchange", "$$user_currency"}},
-							bson.M{"$eq": bson.A{"$from_exchange", "TRY"}},
-						},
-					},
-				},
-			},
-		},
-		"as": "user_exchange_rate",
-	}}
+	userCurrencyExchangeLookup := getExchangeRateLookup("$currency", "$user.currency", "$transaction_date", "user_exchange_rate")
 	unwindUserCurrency := bson.M{"$unwind": bson.M{
 		"path":                       "$user_exchange_rate",
 		"includeArrayIndex":          "index",
@@ -703,18 +605,66 @@ func (transactionModel *TransactionModel) GetTransactionCategoryDistribution(uid
 			},
 			bson.M{
 				"$group": bson.M{
-					"_id": "$category",
+					"_id": bson.M{
+						"$ifNull": bson.A{"$user_category._id", "$category"},
+					},
+					"category": bson.M{
+						"$first": "$category",
+					},
+					"category_id": bson.M{
+						"$first": bson.M{"$toString": "$user_category._id"},
+					},
+					"name": bson.M{
+						"$first": "$user_category.name",
+					},
+					"color": bson.M{
+						"$first": "$user_category.color",
+					},
+					"parent_id": bson.M{
+						"$first": "$user_category.parent_id",
+					},
 					"total_transaction": bson.M{
 						"$sum": "$value",
 					},
 				},
 			},
+			bson.M{
+				"$sort": bson.M{
+					"category": 1,
+				},
+			},
 		},
 		"currency": bson.A{bson.M{
 			"$project": bson.M{
 				"currency": "$user.currency",
 			},
 		}},
+		"currency_breakdown": bson.A{
+			bson.M{
+				"$group": bson.M{
+					"_id": "$currency",
+					"total": bson.M{
+						"$sum": "$price",
+					},
+					"converted_total": bson.M{
+						"$sum": "$value",
+					},
+					"missing_exchange_rate": bson.M{
+						"$max": missingExchangeRateExpression,
+					},
+				},
+			},
+			bson.M{
+				"$set": bson.M{
+					"currency": "$_id",
+				},
+			},
+			bson.M{
+				"$sort": bson.M{
+					"converted_total": -1,
+				},
+			},
+		},
 	}}
 	setResponse := bson.M{"$set": bson.M{
 		"total_transaction": bson.M{
@@ -725,9 +675,37 @@ func (transactionModel *TransactionModel) GetTransactionCategoryDistribution(uid
 		},
 	}}
 
+	// Transactions without category_id resolve to the user's built-in category.
+	userCategoryLookup := bson.M{"$lookup": bson.M{
+		"from": "categories",
+		"let": bson.M{
+			"category":    "$category",
+			"category_id": "$category_id",
+		},
+		"pipeline": bson.A{
+			bson.M{
+				"$match": bson.M{
+					"user_id": uid,
+					"$expr": bson.M{
+						"$cond": bson.A{
+							bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$$category_id", nil}}, nil}},
+							bson.M{"$eq": bson.A{"$built_in", "$$category"}},
+							bson.M{"$eq": bson.A{bson.M{"$toString": "$_id"}, "$$category_id"}},
+						},
+					},
+				},
+			},
+		},
+		"as": "user_category",
+	}}
+	unwindUserCategory := bson.M{"$unwind": bson.M{
+		"path":                       "$user_category",
+		"preserveNullAndEmptyArrays": true,
+	}}
+
 	cursor, err := transactionModel.Collection.Aggregate(context.TODO(), bson.A{
 		match, set, userLookup, unwindUser, userCurrencyExchangeLookup,
-		unwindUserCurrency, addExhangeValue, facet, setResponse,
+		unwindUserCurrency, addExhangeValue, userCategoryLookup, unwindUserCategory, facet, setResponse,
 	})
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -755,7 +733,7 @@ func (transactionModel *TransactionModel) GetTransactionCategoryDistribution(uid
 }
 
 func (transactionModel *TransactionModel) GetTransactionsByUserIDAndFilterSort(
-	uid string, data requests.TransactionSortFilter,
+	uid string, data requests.TransactionSortFilter, categoryIDs []string, legacyCategory *int64,
 ) ([]Transaction, pagination.PaginationData, error) {
 	match := bson.M{}
 	match["user_id"] = uid
@@ -828,6 +806,23 @@ func (transactionModel *TransactionModel) GetTransactionsByUserIDAndFilterSort(
 		}
 	}
 
+	// Transactions created before user categories only have the built-in category.
+	if categoryIDs != nil {
+		categoryMatch := bson.M{"category_id": bson.M{"$in": categoryIDs}}
+		if legacyCategory != nil {
+			categoryMatch = bson.M{"$or": bson.A{
+				categoryMatch,
+				bson.M{"category": *legacyCategory, "category_id": nil},
+			}}
+		}
+
+		if and, ok := match["$and"].(bson.A); ok {
+			match["$and"] = append(and, categoryMatch)
+		} else {
+			match["$and"] = bson.A{categoryMatch}
+		}
+	}
+
 	var (
 		sortType  int
 		sortOrder string
@@ -868,6 +863,7 @@ func (transactionModel *TransactionModel) UpdateTransaction(data requests.Transa
 
 	if data.Category != nil {
 		transaction.Category = *data.Category
+		transaction.CategoryID = data.CategoryID
 	}
 
 	if data.Price != nil {
@@ -924,6 +920,13 @@ func (transactionModel *TransactionModel) UpdateTransactionMethodIDToNull(uid st
 		}}); err != nil {
 		return
 	}
+
+	if _, err := transactionModel.RecurringCollection.UpdateMany(context.TODO(), match,
+		bson.M{"$set": bson.M{
+			"method": nil,
+		}}); err != nil {
+		return
+	}
 }
 
 func (transactionModel *TransactionModel) DeleteTransactionByTransactionID(uid, transactionID string) (bool, error) {
@@ -959,6 +962,62 @@ func (transactionModel *TransactionModel) DeleteAllTransactionsByUserID(uid stri
 	return nil
 }
 
+// Transactions in another currency without an exchange rate are summed with their original price.
+var missingExchangeRateExpression = bson.M{
+	"$and": bson.A{
+		bson.M{"$ne": bson.A{"$currency", "$user.currency"}},
+		bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$user_exchange_rate", nil}}, nil}},
+	},
+}
+
+// getCurrencyBreakdownGroups groups converted transactions by key and original
+// currency, then by key, so the converted total keeps the totals of every
+// original currency next to it. fields are carried over with $first.
+func getCurrencyBreakdownGroups(key string, fields bson.M) (bson.M, bson.M) {
+	currencyGroupFields := bson.M{
+		"_id": bson.M{
+			"key":      key,
+			"currency": "$currency",
+		},
+		"user_currency": bson.M{
+			"$first": "$user.currency",
+		},
+		"total": bson.M{
+			"$sum": "$price",
+		},
+		"converted_total": bson.M{
+			"$sum": "$value",
+		},
+		"missing_exchange_rate": bson.M{
+			"$max": missingExchangeRateExpression,
+		},
+	}
+	groupFields := bson.M{
+		"_id": "$_id.key",
+		"currency": bson.M{
+			"$first": "$user_currency",
+		},
+		"total_transaction": bson.M{
+			"$sum": "$converted_total",
+		},
+		"currency_breakdown": bson.M{
+			"$push": bson.M{
+				"currency":              "$_id.currency",
+				"total":                 "$total",
+				"converted_total":       "$converted_total",
+				"missing_exchange_rate": "$missing_exchange_rate",
+			},
+		},
+	}
+
+	for field, value := range fields {
+		currencyGroupFields[field] = bson.M{"$first": value}
+		groupFields[field] = bson.M{"$first": "$" + field}
+	}
+
+	return bson.M{"$group": currencyGroupFields}, bson.M{"$group": groupFields}
+}
+
 func (transactionModel *TransactionModel) GetTotalFromCategoryStats(stats responses.TransactionCategoryStats, isIncome bool) float64 {
 	var total float64 = 0
 
@@ -7,6 +7,7 @@ import (
 	"asset_backend/utils"
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -40,6 +41,10 @@ func NewUserModel(mongoDB *db.MongoDB) *UserModel {
 *	- Max 10 per day.
 * *Favourite Investings
 * 	- Max 5 favourites.
+* *Budgets
+* 	- Max 3 budgets.
+* *Price Alerts
+* 	- Max 3 price alerts.
 **/
 type User struct {
 	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"_id"`
@@ -57,22 +62,38 @@ type User struct {
 	FCMToken           string             `bson:"fcm_token" json:"fcm_token"`
 	AppNotification    bool               `bson:"app_notification" json:"app_notification"`
 	MailNotification   bool               `bson:"mail_notification" json:"mail_notification"`
+	CostBasisMethod    string             `bson:"cost_basis_method" json:"cost_basis_method"`
+	TwoFactorEnabled   bool               `bson:"two_factor_enabled" json:"two_factor_enabled"`
+	TwoFactorSecret    string             `bson:"two_factor_secret" json:"-"`
+	RecoveryCodes      []string           `bson:"recovery_codes" json:"-"`
+	IsVerified         bool               `bson:"is_verified" json:"is_verified"`
+	VerificationToken  string             `bson:"verification_token" json:"-"`
+	VerificationExpiry *time.Time         `bson:"verification_expiry" json:"-"`
 }
 
-func createUserObject(emailAddress, currency, password string) *User {
+// VerificationTokenTimeout is how long email verification links are valid.
+const VerificationTokenTimeout = 24 * time.Hour
+
+func createUserObject(emailAddress, currency, password, verificationToken string) *User {
+	verificationExpiry := time.Now().UTC().Add(VerificationTokenTimeout)
+
 	return &User{
-		EmailAddress:      emailAddress,
-		Currency:          currency,
-		Password:          utils.HashPassword(password),
-		CreatedAt:         time.Now().UTC(),
-		UpdatedAt:         time.Now().UTC(),
-		IsPremium:         false,
-		IsLifetimePremium: false,
-		IsOAuthUser:       false,
-		AppNotification:   true,
-		MailNotification:  true,
-		OAuthType:         -1,
-		FCMToken:          "",
+		EmailAddress:       emailAddress,
+		Currency:           currency,
+		Password:           utils.HashPassword(password),
+		CreatedAt:          time.Now().UTC(),
+		UpdatedAt:          time.Now().UTC(),
+		IsPremium:          false,
+		IsLifetimePremium:  false,
+		IsOAuthUser:        false,
+		AppNotification:    true,
+		MailNotification:   true,
+		OAuthType:          -1,
+		FCMToken:           "",
+		CostBasisMethod:    CostBasisFIFO,
+		IsVerified:         false,
+		VerificationToken:  verificationToken,
+		VerificationExpiry: &verificationExpiry,
 	}
 }
 
@@ -88,21 +109,26 @@ func createOAuthUserObject(emailAddress string, refreshToken *string, oAuthType
 		MailNotification: false,
 		OAuthType:        oAuthType,
 		RefreshToken:     refreshToken,
+		CostBasisMethod:  CostBasisFIFO,
+		IsVerified:       true,
 	}
 }
 
-func (userModel *UserModel) CreateUser(data requests.Register) error {
-	user := createUserObject(data.EmailAddress, data.Currency, data.Password)
+func (userModel *UserModel) CreateUser(data requests.Register, verificationToken string) (*User, error) {
+	user := createUserObject(data.EmailAddress, data.Currency, data.Password, verificationToken)
 
-	if _, err := userModel.Collection.InsertOne(context.TODO(), user); err != nil {
+	result, err := userModel.Collection.InsertOne(context.TODO(), user)
+	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"email": data.EmailAddress,
 		}).Error("failed to create new user: ", err)
 
-		return fmt.Errorf("Failed to create new user.")
+		return nil, fmt.Errorf("Failed to create new user.")
 	}
 
-	return nil
+	user.ID = result.InsertedID.(primitive.ObjectID)
+
+	return user, nil
 }
 
 func (userModel *UserModel) CreateOAuthUser(email string, refreshToken *string, oAuthType int) (*User, error) {
@@ -208,26 +234,91 @@ func (userModel *UserModel) UpdateUser(user User) error {
 	return nil
 }
 
-func (userModel *UserModel) UpdateUserMembership(uid string, data requests.ChangeMembership) error {
+// SetTwoFactorSecret saves the encrypted secret of a pending enrollment, two
+// factor is enabled once a code of it is verified.
+func (userModel *UserModel) SetTwoFactorSecret(uid, secret string) error {
 	objectUID, _ := primitive.ObjectIDFromHex(uid)
 
 	if _, err := userModel.Collection.UpdateOne(context.TODO(), bson.M{"_id": objectUID}, bson.M{"$set": bson.M{
-		"is_premium":          data.IsPremium,
-		"is_lifetime_premium": data.IsLifetimePremium,
-		"updated_at":          time.Now().UTC(),
+		"two_factor_enabled": false,
+		"two_factor_secret":  secret,
+		"recovery_codes":     bson.A{},
+		"updated_at":         time.Now().UTC(),
+	}}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to set two factor secret: ", err)
+
+		return fmt.Errorf("Failed to set two factor secret.")
+	}
+
+	return nil
+}
+
+// EnableTwoFactor enables two factor with the hashes of the recovery codes,
+// it's also used to replace the recovery codes.
+func (userModel *UserModel) EnableTwoFactor(uid string, recoveryCodes []string) error {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	if _, err := userModel.Collection.UpdateOne(context.TODO(), bson.M{"_id": objectUID}, bson.M{"$set": bson.M{
+		"two_factor_enabled": true,
+		"recovery_codes":     recoveryCodes,
+		"updated_at":         time.Now().UTC(),
 	}}); err != nil {
 		logrus.WithFields(logrus.Fields{
-			"uid":                 uid,
-			"is_premium":          data.IsPremium,
-			"is_lifetime_premium": data.IsLifetimePremium,
-		}).Error("failed to set membership for user: ", err)
+			"uid": uid,
+		}).Error("failed to enable two factor: ", err)
 
-		return fmt.Errorf("Failed to set membership for user.")
+		return fmt.Errorf("Failed to enable two factor authentication.")
 	}
 
 	return nil
 }
 
+func (userModel *UserModel) DisableTwoFactor(uid string) error {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	if _, err := userModel.Collection.UpdateOne(context.TODO(), bson.M{"_id": objectUID}, bson.M{"$set": bson.M{
+		"two_factor_enabled": false,
+		"two_factor_secret":  "",
+		"recovery_codes":     bson.A{},
+		"updated_at":         time.Now().UTC(),
+	}}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to disable two factor: ", err)
+
+		return fmt.Errorf("Failed to disable two factor authentication.")
+	}
+
+	return nil
+}
+
+// UseRecoveryCode removes the recovery code hash, so every code works once.
+func (userModel *UserModel) UseRecoveryCode(uid, recoveryCode string) bool {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	result, err := userModel.Collection.UpdateOne(context.TODO(), bson.M{
+		"_id":            objectUID,
+		"recovery_codes": recoveryCode,
+	}, bson.M{"$pull": bson.M{
+		"recovery_codes": recoveryCode,
+	}})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to use recovery code: ", err)
+
+		return false
+	}
+
+	return result.ModifiedCount > 0
+}
+
+// countErrorFallback is returned by quota counts when counting fails, so the
+// quota is treated as reached.
+const countErrorFallback = math.MaxInt32
+
 func (userModel *UserModel) IsUserPremium(uid string) bool {
 	objectUID, _ := primitive.ObjectIDFromHex(uid)
 
@@ -302,6 +393,85 @@ func (userModel *UserModel) FindUserByResetTokenAndEmail(token, email string) (U
 	return user, nil
 }
 
+func (userModel *UserModel) SetVerificationToken(uid, token string) error {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	if _, err := userModel.Collection.UpdateOne(context.TODO(), bson.M{"_id": objectUID}, bson.M{"$set": bson.M{
+		"verification_token":  token,
+		"verification_expiry": time.Now().UTC().Add(VerificationTokenTimeout),
+		"updated_at":          time.Now().UTC(),
+	}}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to set verification token: ", err)
+
+		return fmt.Errorf("Failed to set verification token.")
+	}
+
+	return nil
+}
+
+// VerifyUserEmail verifies the user of the unexpired token, it returns false
+// when the token doesn't match.
+func (userModel *UserModel) VerifyUserEmail(token, email string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+
+	result, err := userModel.Collection.UpdateOne(context.TODO(), bson.M{
+		"verification_token":  token,
+		"email_address":       email,
+		"verification_expiry": bson.M{"$gt": time.Now().UTC()},
+	}, bson.M{"$set": bson.M{
+		"is_verified":         true,
+		"verification_token":  "",
+		"verification_expiry": nil,
+		"updated_at":          time.Now().UTC(),
+	}})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"email": email,
+		}).Error("failed to verify user email: ", err)
+
+		return false, fmt.Errorf("Failed to verify email.")
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// SetUserVerified verifies the user, emails of OAuth users are verified by
+// the provider.
+func (userModel *UserModel) SetUserVerified(uid primitive.ObjectID) error {
+	if _, err := userModel.Collection.UpdateOne(context.TODO(), bson.M{"_id": uid}, bson.M{"$set": bson.M{
+		"is_verified": true,
+		"updated_at":  time.Now().UTC(),
+	}}); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to set user verified: ", err)
+
+		return fmt.Errorf("Failed to set user verified.")
+	}
+
+	return nil
+}
+
+// VerifyExistingUsers verifies users created before email verification, they
+// don't have the field.
+func (userModel *UserModel) VerifyExistingUsers() error {
+	if _, err := userModel.Collection.UpdateMany(context.TODO(), bson.M{
+		"is_verified": bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{
+		"is_verified": true,
+	}}); err != nil {
+		logrus.Error("failed to verify existing users: ", err)
+
+		return fmt.Errorf("Failed to verify existing users.")
+	}
+
+	return nil
+}
+
 func (userModel *UserModel) FindUserByEmail(email string) (User, error) {
 	result := userModel.Collection.FindOne(context.TODO(), bson.M{
 		"email_address": email,
@@ -0,0 +1,946 @@
+package models
+
+import (
+	"asset_backend/db"
+	"asset_backend/requests"
+	"asset_backend/responses"
+	"asset_backend/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UserDataModel struct {
+	Database                       *mongo.Database
+	CustomAssetValuationCollection *mongo.Collection
+	InvestingModel                 *InvestingModel
+}
+
+func NewUserDataModel(mongoDB *db.MongoDB) *UserDataModel {
+	return &UserDataModel{
+		Database:                       mongoDB.Database,
+		CustomAssetValuationCollection: mongoDB.Database.Collection("custom-asset-valuations"),
+		InvestingModel:                 NewInvestingModel(mongoDB),
+	}
+}
+
+type UserDataCollection struct {
+	Name      string
+	Documents []bson.M
+}
+
+// Every collection owned by a user, in the same order as UserController.DeleteUser.
+var userDataCollectionNames = []string{
+	"assets",
+	"daily-asset-stats",
+	"daily-asset-holding-stats",
+	"daily-asset-type-stats",
+	"daily-net-worth-stats",
+	"cards",
+	"subscriptions",
+	"subscription-invites",
+	"logs",
+	"transactions",
+	"recurring-transactions",
+	"bank-accounts",
+	"favourite_investings",
+	"price-alerts",
+	"allocation-targets",
+	"custom-asset-valuations",
+	"purchases",
+	"budgets",
+	"categories",
+}
+
+// Fields that must never leave the server.
+var userExportHiddenFields = []string{"password", "reset_token", "refresh_token", "two_factor_secret", "recovery_codes", "verification_token"}
+
+func (userDataModel *UserDataModel) GetUserExportData(uid string, includePasswords bool) ([]UserDataCollection, error) {
+	objectUID, _ := primitive.ObjectIDFromHex(uid)
+
+	var user bson.M
+	if err := userDataModel.Database.Collection("users").FindOne(context.TODO(), bson.M{
+		"_id": objectUID,
+	}).Decode(&user); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid": uid,
+		}).Error("failed to find user for export: ", err)
+
+		return nil, fmt.Errorf("Failed to find user by id.")
+	}
+
+	for _, field := range userExportHiddenFields {
+		delete(user, field)
+	}
+
+	userDataCollections := []UserDataCollection{{Name: "user", Documents: []bson.M{user}}}
+
+	for _, name := range userDataCollectionNames {
+		cursor, err := userDataModel.Database.Collection(name).Find(
+			context.TODO(),
+			getUserDataFilter(name, uid, objectUID),
+			options.Find().SetSort(bson.M{"_id": 1}),
+		)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"uid":        uid,
+				"collection": name,
+			}).Error("failed to find user data for export: ", err)
+
+			return nil, fmt.Errorf("Failed to export %s.", name)
+		}
+
+		documents := []bson.M{}
+		if err = cursor.All(context.TODO(), &documents); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"uid":        uid,
+				"collection": name,
+			}).Error("failed to decode user data for export: ", err)
+
+			return nil, fmt.Errorf("Failed to export %s.", name)
+		}
+
+		if name == "subscriptions" {
+			for _, document := range documents {
+				exportSubscriptionAccount(document, includePasswords)
+			}
+		}
+
+		if name == "purchases" {
+			for _, document := range documents {
+				delete(document, "receipt")
+			}
+		}
+
+		userDataCollections = append(userDataCollections, UserDataCollection{
+			Name:      name,
+			Documents: documents,
+		})
+	}
+
+	return userDataCollections, nil
+}
+
+// Daily stats store user_id as ObjectID and invites belong to both sides.
+func getUserDataFilter(name, uid string, objectUID primitive.ObjectID) bson.M {
+	switch name {
+	case "daily-asset-stats", "daily-asset-holding-stats", "daily-asset-type-stats", "daily-net-worth-stats":
+		return bson.M{"user_id": bson.M{"$in": bson.A{uid, objectUID}}}
+	case "subscription-invites":
+		return bson.M{"$or": bson.A{
+			bson.M{"user_id": uid},
+			bson.M{"invited_user_id": uid},
+		}}
+	default:
+		return bson.M{"user_id": uid}
+	}
+}
+
+func exportSubscriptionAccount(subscription bson.M, includePassword bool) {
+	account, ok := subscription["account"].(bson.M)
+	if !ok {
+		return
+	}
+
+	password, ok := account["password"].(string)
+	if !ok {
+		return
+	}
+
+	if includePassword {
+		account["password"] = utils.Decrypt(password)
+	} else {
+		account["password"] = nil
+	}
+}
+
+// UserDataImportLimits are the quotas of user's plan, negative limits are unlimited.
+type UserDataImportLimits struct {
+	Assets            int64
+	Subscriptions     int64
+	Cards             int64
+	BankAccounts      int64
+	DailyTransactions int64
+	Watchlist         int64
+}
+
+type userDataImporter struct {
+	model            *UserDataModel
+	uid              string
+	limits           UserDataImportLimits
+	includePasswords bool
+	cardIDs          map[string]string
+	bankAccountIDs   map[string]string
+	categoryIDs      map[string]string
+}
+
+// ImportUserData recreates the exported items for the user. Old ObjectIDs are
+// remapped so subscription cards, transaction methods and categories keep pointing
+// to the imported or already existing items. Assets and transactions are
+// validated with their create requests.
+func (userDataModel *UserDataModel) ImportUserData(
+	uid string, limits UserDataImportLimits, includePasswords bool, collections map[string][]bson.M,
+) (responses.UserDataImport, error) {
+	importer := userDataImporter{
+		model:            userDataModel,
+		uid:              uid,
+		limits:           limits,
+		includePasswords: includePasswords,
+		cardIDs:          make(map[string]string),
+		bankAccountIDs:   make(map[string]string),
+		categoryIDs:      make(map[string]string),
+	}
+
+	steps := []struct {
+		name string
+		run  func([]bson.M) (responses.UserDataImportResult, error)
+	}{
+		{"cards", importer.importCards},
+		{"bank-accounts", importer.importBankAccounts},
+		{"categories", importer.importCategories},
+		{"subscriptions", importer.importSubscriptions},
+		{"transactions", importer.importTransactions},
+		{"custom-asset-valuations", importer.importCustomAssetValuations},
+		{"assets", importer.importAssets},
+		{"favourite_investings", importer.importFavouriteInvestings},
+	}
+
+	userDataImport := responses.UserDataImport{
+		Collections: []responses.UserDataImportResult{},
+	}
+
+	for _, step := range steps {
+		result, err := step.run(collections[step.name])
+		if err != nil {
+			return userDataImport, err
+		}
+
+		result.Collection = step.name
+		userDataImport.Collections = append(userDataImport.Collections, result)
+	}
+
+	return userDataImport, nil
+}
+
+func (importer *userDataImporter) importCards(documents []bson.M) (responses.UserDataImportResult, error) {
+	result := newUserDataImportResult()
+
+	var existingCards []Card
+	if err := importer.findExisting("cards", &existingCards); err != nil {
+		return result, err
+	}
+
+	existingKeys := make(map[string]string, len(existingCards))
+	for _, card := range existingCards {
+		existingKeys[card.Name+"/"+card.Last4Digit] = card.ID.Hex()
+	}
+
+	count := len(existingCards)
+
+	for _, document := range documents {
+		var card Card
+		if oldID, ok := decodeUserDataDocument(document, &card, &result); ok {
+			key := card.Name + "/" + card.Last4Digit
+
+			if existingID, ok := existingKeys[key]; ok {
+				importer.cardIDs[oldID] = existingID
+				addUserDataImportConflict(&result, oldID, errImportAlreadyExists)
+
+				continue
+			}
+
+			if isImportLimitReached(importer.limits.Cards, count) {
+				addUserDataImportConflict(&result, oldID, errImportPremiumLimit)
+				continue
+			}
+
+			card.ID = primitive.NilObjectID
+			card.UserID = importer.uid
+
+			newID, err := importer.insert("cards", card)
+			if err != nil {
+				addUserDataImportConflict(&result, oldID, err.Error())
+				continue
+			}
+
+			importer.cardIDs[oldID] = newID
+			existingKeys[key] = newID
+			count++
+			result.Imported++
+		}
+	}
+
+	return result, nil
+}
+
+func (importer *userDataImporter) importBankAccounts(documents []bson.M) (responses.UserDataImportResult, error) {
+	result := newUserDataImportResult()
+
+	var existingBankAccounts []BankAccount
+	if err := importer.findExisting("bank-accounts", &existingBankAccounts); err != nil {
+		return result, err
+	}
+
+	existingKeys := make(map[string]string, len(existingBankAccounts))
+	for _, bankAccount := range existingBankAccounts {
+		existingKeys[bankAccount.Name+"/"+bankAccount.Iban] = bankAccount.ID.Hex()
+	}
+
+	count := len(existingBankAccounts)
+
+	for _, document := range documents {
+		var bankAccount BankAccount
+		if oldID, ok := decodeUserDataDocument(document, &bankAccount, &result); ok {
+			key := bankAccount.Name + "/" + bankAccount.Iban
+
+			if existingID, ok := existingKeys[key]; ok {
+				importer.bankAccountIDs[oldID] = existingID
+				addUserDataImportConflict(&result, oldID, errImportAlreadyExists)
+
+				continue
+			}
+
+			if isImportLimitReached(importer.limits.BankAccounts, count) {
+				addUserDataImportConflict(&result, oldID, errImportPremiumLimit)
+				continue
+			}
+
+			bankAccount.ID = primitive.NilObjectID
+			bankAccount.UserID = importer.uid
+
+			newID, err := importer.insert("bank-accounts", bankAccount)
+			if err != nil {
+				addUserDataImportConflict(&result, oldID, err.Error())
+				continue
+			}
+
+			importer.bankAccountIDs[oldID] = newID
+			existingKeys[key] = newID
+			count++
+			result.Imported++
+		}
+	}
+
+	return result, nil
+}
+
+// importCategories maps exported built-in categories to user's own and imports
+// the custom ones, top level categories first so sub categories can point to
+// their new parent.
+func (importer *userDataImporter) importCategories(documents []bson.M) (responses.UserDataImportResult, error) {
+	result := newUserDataImportResult()
+
+	if err := createDefaultCategories(importer.model.Database.Collection("categories"), importer.uid); err != nil {
+		return result, err
+	}
+
+	var existingCategories []Category
+	if err := importer.findExisting("categories", &existingCategories); err != nil {
+		return result, err
+	}
+
+	existingKeys := make(map[string]string, len(existingCategories))
+	for _, category := range existingCategories {
+		existingKeys[getCategoryImportKey(category)] = category.ID.Hex()
+	}
+
+	type importedCategory struct {
+		oldID    string
+		category Category
+	}
+
+	var topLevelCategories, subCategories []importedCategory
+
+	for _, document := range documents {
+		var category Category
+		if oldID, ok := decodeUserDataDocument(document, &category, &result); ok {
+			if category.ParentID == nil {
+				topLevelCategories = append(topLevelCategories, importedCategory{oldID, category})
+			} else {
+				subCategories = append(subCategories, importedCategory{oldID, category})
+			}
+		}
+	}
+
+	for _, imported := range append(topLevelCategories, subCategories...) {
+		oldID, category := imported.oldID, imported.category
+
+		if category.ParentID != nil {
+			if parentID, ok := importer.categoryIDs[*category.ParentID]; ok {
+				category.ParentID = &parentID
+			} else {
+				category.ParentID = nil
+				addUserDataImportConflict(&result, oldID, errImportMissingParent)
+			}
+		}
+
+		key := getCategoryImportKey(category)
+
+		if existingID, ok := existingKeys[key]; ok {
+			importer.categoryIDs[oldID] = existingID
+			addUserDataImportConflict(&result, oldID, errImportAlreadyExists)
+
+			continue
+		}
+
+		category.ID = primitive.NilObjectID
+		category.UserID = importer.uid
+
+		newID, err := importer.insert("categories", category)
+		if err != nil {
+			addUserDataImportConflict(&result, oldID, err.Error())
+			continue
+		}
+
+		importer.categoryIDs[oldID] = newID
+		existingKeys[key] = newID
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+func (importer *userDataImporter) importSubscriptions(documents []bson.M) (responses.UserDataImportResult, error) {
+	result := newUserDataImportResult()
+
+	var existingSubscriptions []Subscription
+	if err := importer.findExisting("subscriptions", &existingSubscriptions); err != nil {
+		return result, err
+	}
+
+	existingKeys := make(map[string]bool, len(existingSubscriptions))
+	for _, subscription := range existingSubscriptions {
+		existingKeys[getSubscriptionImportKey(subscription)] = true
+	}
+
+	count := len(existingSubscriptions)
+
+	for _, document := range documents {
+		var subscription Subscription
+		if oldID, ok := decodeUserDataDocument(document, &subscription, &result); ok {
+			key := getSubscriptionImportKey(subscription)
+
+			if existingKeys[key] {
+				addUserDataImportConflict(&result, oldID, errImportAlreadyExists)
+				continue
+			}
+
+			if isImportLimitReached(importer.limits.Subscriptions, count) {
+				addUserDataImportConflict(&result, oldID, errImportPremiumLimit)
+				continue
+			}
+
+			if subscription.CardID != nil {
+				if cardID, ok := importer.cardIDs[*subscription.CardID]; ok {
+					subscription.CardID = &cardID
+				} else {
+					subscription.CardID = nil
+					addUserDataImportConflict(&result, oldID, errImportMissingCard)
+				}
+			}
+
+			if subscription.Account != nil && subscription.Account.Password != nil {
+				if importer.includePasswords {
+					encryptedPassword := utils.Encrypt(*subscription.Account.Password)
+					subscription.Account.Password = &encryptedPassword
+				} else {
+					subscription.Account.Password = nil
+				}
+			}
+
+			subscription.ID = primitive.NilObjectID
+			subscription.UserID = importer.uid
+			subscription.SharedUsers = make([]string, 0)
+			subscription.InvitedUsers = make([]string, 0)
+
+			if _, err := importer.insert("subscriptions", subscription); err != nil {
+				addUserDataImportConflict(&result, oldID, err.Error())
+				continue
+			}
+
+			existingKeys[key] = true
+			count++
+			result.Imported++
+		}
+	}
+
+	return result, nil
+}
+
+func (importer *userDataImporter) importTransactions(documents []bson.M) (responses.UserDataImportResult, error) {
+	result := newUserDataImportResult()
+
+	var existingTransactions []Transaction
+	if err := importer.findExisting("transactions", &existingTransactions); err != nil {
+		return result, err
+	}
+
+	var categories []Category
+	if err := importer.findExisting("categories", &categories); err != nil {
+		return result, err
+	}
+
+	categoryRoots := getCategoryRootBuiltIns(categories)
+	existingKeys := make(map[string]bool, len(existingTransactions))
+	dailyCounts := make(map[string]int)
+
+	for _, transaction := range existingTransactions {
+		existingKeys[getTransactionImportKey(transaction)] = true
+		dailyCounts[transaction.TransactionDate.UTC().Format("2006-01-02")]++
+	}
+
+	for _, document := range documents {
+		var transaction Transaction
+		if oldID, ok := decodeUserDataDocument(document, &transaction, &result); ok {
+			if !isValidTransactionImport(transaction) {
+				addUserDataImportConflict(&result, oldID, errImportInvalid)
+				continue
+			}
+
+			key := getTransactionImportKey(transaction)
+			day := transaction.TransactionDate.UTC().Format("2006-01-02")
+
+			if existingKeys[key] {
+				addUserDataImportConflict(&result, oldID, errImportAlreadyExists)
+				continue
+			}
+
+			if isImportLimitReached(importer.limits.DailyTransactions, dailyCounts[day]) {
+				addUserDataImportConflict(&result, oldID, errImportPremiumLimit)
+				continue
+			}
+
+			if transaction.TransactionMethod != nil {
+				methodIDs := importer.bankAccountIDs
+				if transaction.TransactionMethod.Type == CreditCard {
+					methodIDs = importer.cardIDs
+				}
+
+				if methodID, ok := methodIDs[transaction.TransactionMethod.MethodID]; ok {
+					transaction.TransactionMethod.MethodID = methodID
+				} else {
+					transaction.TransactionMethod = nil
+					addUserDataImportConflict(&result, oldID, errImportMissingMethod)
+				}
+			}
+
+			transaction.ID = primitive.NilObjectID
+			transaction.UserID = importer.uid
+			if transaction.CategoryID != nil {
+				if categoryID, ok := importer.categoryIDs[*transaction.CategoryID]; ok {
+					transaction.CategoryID = &categoryID
+					transaction.Category = categoryRoots[categoryID]
+				} else {
+					transaction.CategoryID = nil
+					addUserDataImportConflict(&result, oldID, errImportMissingCategory)
+				}
+			}
+
+			transaction.RecurringID = nil
+
+			if _, err := importer.insert("transactions", transaction); err != nil {
+				addUserDataImportConflict(&result, oldID, err.Error())
+				continue
+			}
+
+			existingKeys[key] = true
+			dailyCounts[day]++
+			result.Imported++
+		}
+	}
+
+	return result, nil
+}
+
+// importCustomAssetValuations restores user supplied prices before assets, so
+// imported custom assets don't fall back to their first log's price.
+func (importer *userDataImporter) importCustomAssetValuations(documents []bson.M) (responses.UserDataImportResult, error) {
+	result := newUserDataImportResult()
+	collectionName := importer.model.CustomAssetValuationCollection.Name()
+
+	var existingValuations []CustomAssetValuation
+	if err := importer.findExisting(collectionName, &existingValuations); err != nil {
+		return result, err
+	}
+
+	existingKeys := make(map[string]bool, len(existingValuations))
+	for _, valuation := range existingValuations {
+		existingKeys[GetAssetPairKey(valuation.ToAsset, valuation.FromAsset)] = true
+	}
+
+	for _, document := range documents {
+		var valuation CustomAssetValuation
+		if oldID, ok := decodeUserDataDocument(document, &valuation, &result); ok {
+			valuation.FromAsset = strings.ToUpper(valuation.FromAsset)
+			key := GetAssetPairKey(valuation.ToAsset, valuation.FromAsset)
+
+			if existingKeys[key] {
+				addUserDataImportConflict(&result, oldID, errImportAlreadyExists)
+				continue
+			}
+
+			valuation.ID = primitive.NilObjectID
+			valuation.UserID = importer.uid
+
+			if _, err := importer.insert(collectionName, valuation); err != nil {
+				addUserDataImportConflict(&result, oldID, err.Error())
+				continue
+			}
+
+			existingKeys[key] = true
+			result.Imported++
+		}
+	}
+
+	return result, nil
+}
+
+func (importer *userDataImporter) importAssets(documents []bson.M) (responses.UserDataImportResult, error) {
+	result := newUserDataImportResult()
+
+	var existingAssets []Asset
+	if err := importer.findExisting("assets", &existingAssets); err != nil {
+		return result, err
+	}
+
+	existingKeys := make(map[string]bool, len(existingAssets))
+	assetPairs := make(map[string]bool)
+
+	for _, asset := range existingAssets {
+		existingKeys[getAssetImportKey(asset)] = true
+		assetPairs[GetAssetPairKey(asset.ToAsset, asset.FromAsset)] = true
+	}
+
+	type importedAsset struct {
+		oldID string
+		asset Asset
+	}
+
+	var importedAssets []importedAsset
+	investingIDSet := make(map[InvestingID]bool)
+
+	for _, document := range documents {
+		var asset Asset
+		if oldID, ok := decodeUserDataDocument(document, &asset, &result); ok {
+			if !isValidAssetImport(asset) {
+				addUserDataImportConflict(&result, oldID, errImportInvalid)
+				continue
+			}
+
+			if asset.AssetType != AssetTypeCustom {
+				investingIDSet[InvestingID{Symbol: asset.ToAsset, Type: asset.AssetType, Market: asset.AssetMarket}] = true
+			}
+
+			importedAssets = append(importedAssets, importedAsset{oldID, asset})
+		}
+	}
+
+	investingIDs := make([]InvestingID, 0, len(investingIDSet))
+	for investingID := range investingIDSet {
+		investingIDs = append(investingIDs, investingID)
+	}
+
+	// Non custom assets have to be tracked investings, same as asset import.
+	existingInvestingIDs, err := importer.model.InvestingModel.GetExistingInvestingIDs(investingIDs)
+	if err != nil {
+		return result, err
+	}
+
+	for _, imported := range importedAssets {
+		oldID, asset := imported.oldID, imported.asset
+
+		if asset.AssetType != AssetTypeCustom &&
+			!existingInvestingIDs[InvestingID{Symbol: asset.ToAsset, Type: asset.AssetType, Market: asset.AssetMarket}] {
+			addUserDataImportConflict(&result, oldID, errImportUnknownAsset)
+			continue
+		}
+
+		asset.FromAsset = strings.ToUpper(asset.FromAsset)
+		key := getAssetImportKey(asset)
+		pairKey := GetAssetPairKey(asset.ToAsset, asset.FromAsset)
+
+		if existingKeys[key] {
+			addUserDataImportConflict(&result, oldID, errImportAlreadyExists)
+			continue
+		}
+
+		if !assetPairs[pairKey] && isImportLimitReached(importer.limits.Assets, len(assetPairs)) {
+			addUserDataImportConflict(&result, oldID, errImportPremiumLimit)
+			continue
+		}
+
+		if asset.AssetType == AssetTypeCustom {
+			if err := createCustomAssetValuation(
+				importer.model.CustomAssetValuationCollection, importer.uid, asset.ToAsset, asset.FromAsset, asset.Price,
+			); err != nil {
+				addUserDataImportConflict(&result, oldID, err.Error())
+				continue
+			}
+		}
+
+		asset.ID = primitive.NilObjectID
+		asset.UserID = importer.uid
+
+		if _, err := importer.insert("assets", asset); err != nil {
+			addUserDataImportConflict(&result, oldID, err.Error())
+			continue
+		}
+
+		existingKeys[key] = true
+		assetPairs[pairKey] = true
+		result.Imported++
+	}
+
+	return result, nil
+}
+
+func (importer *userDataImporter) importFavouriteInvestings(documents []bson.M) (responses.UserDataImportResult, error) {
+	result := newUserDataImportResult()
+
+	var existingFavouriteInvestings []FavouriteInvesting
+	if err := importer.findExisting("favourite_investings", &existingFavouriteInvestings); err != nil {
+		return result, err
+	}
+
+	existingKeys := make(map[FavouriteInvestingID]bool, len(existingFavouriteInvestings))
+	for _, favouriteInvesting := range existingFavouriteInvestings {
+		existingKeys[favouriteInvesting.InvestingID] = true
+	}
+
+	for _, document := range documents {
+		var favouriteInvesting FavouriteInvesting
+		if oldID, ok := decodeUserDataDocument(document, &favouriteInvesting, &result); ok {
+			if existingKeys[favouriteInvesting.InvestingID] {
+				addUserDataImportConflict(&result, oldID, errImportAlreadyExists)
+				continue
+			}
+
+			if isImportLimitReached(importer.limits.Watchlist, len(existingKeys)) {
+				addUserDataImportConflict(&result, oldID, errImportPremiumLimit)
+				continue
+			}
+
+			favouriteInvesting.ID = primitive.NilObjectID
+			favouriteInvesting.UserID = importer.uid
+			favouriteInvesting.Priority = len(existingKeys)
+
+			if _, err := importer.insert("favourite_investings", favouriteInvesting); err != nil {
+				addUserDataImportConflict(&result, oldID, err.Error())
+				continue
+			}
+
+			existingKeys[favouriteInvesting.InvestingID] = true
+			result.Imported++
+		}
+	}
+
+	return result, nil
+}
+
+func (importer *userDataImporter) findExisting(name string, results interface{}) error {
+	cursor, err := importer.model.Database.Collection(name).Find(context.TODO(), bson.M{"user_id": importer.uid})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        importer.uid,
+			"collection": name,
+		}).Error("failed to find existing user data for import: ", err)
+
+		return fmt.Errorf("Failed to import %s.", name)
+	}
+
+	if err = cursor.All(context.TODO(), results); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        importer.uid,
+			"collection": name,
+		}).Error("failed to decode existing user data for import: ", err)
+
+		return fmt.Errorf("Failed to import %s.", name)
+	}
+
+	return nil
+}
+
+func (importer *userDataImporter) insert(name string, document interface{}) (string, error) {
+	insertedID, err := importer.model.Database.Collection(name).InsertOne(context.TODO(), document)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"uid":        importer.uid,
+			"collection": name,
+		}).Error("failed to insert user data on import: ", err)
+
+		return "", fmt.Errorf("Failed to create item.")
+	}
+
+	return insertedID.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+const (
+	errImportAlreadyExists   = "Already exists."
+	errImportPremiumLimit    = "Free membership limit reached, you can get premium membership for unlimited access."
+	errImportMissingCard     = "Card couldn't be imported, subscription is imported without card."
+	errImportMissingMethod   = "Payment method couldn't be imported, transaction is imported without method."
+	errImportInvalid         = "Invalid item."
+	errImportUnknownAsset    = "Asset couldn't be found in investings."
+	errImportMissingParent   = "Parent category couldn't be imported, category is imported as a top level category."
+	errImportMissingCategory = "Category couldn't be imported, transaction is imported with its default category."
+)
+
+func addUserDataImportConflict(result *responses.UserDataImportResult, id, reason string) {
+	result.Conflicts = append(result.Conflicts, responses.UserDataImportConflict{
+		ID:     id,
+		Reason: reason,
+	})
+}
+
+func newUserDataImportResult() responses.UserDataImportResult {
+	return responses.UserDataImportResult{
+		Conflicts: []responses.UserDataImportConflict{},
+	}
+}
+
+// decodeUserDataDocument converts an archive document to its model and returns
+// the exported ObjectID so references can be remapped.
+func decodeUserDataDocument(document bson.M, model interface{}, result *responses.UserDataImportResult) (string, bool) {
+	var oldID string
+	if id, ok := document["_id"].(primitive.ObjectID); ok {
+		oldID = id.Hex()
+	}
+
+	encodedDocument, err := bson.Marshal(document)
+	if err == nil {
+		err = bson.Unmarshal(encodedDocument, model)
+	}
+
+	if err != nil {
+		addUserDataImportConflict(result, oldID, errImportInvalid)
+
+		return oldID, false
+	}
+
+	return oldID, true
+}
+
+// isValidAssetImport runs the validation of created assets on the restored log,
+// split logs carry their ratio as the amount like the request does.
+func isValidAssetImport(asset Asset) bool {
+	request := requests.AssetCreate{
+		ToAsset:     asset.ToAsset,
+		FromAsset:   asset.FromAsset,
+		Price:       asset.Price,
+		Amount:      asset.Amount,
+		AssetType:   asset.AssetType,
+		AssetMarket: asset.AssetMarket,
+		Type:        asset.Type,
+	}
+
+	if asset.Type == AssetLogSplit {
+		if asset.SplitRatio <= 0 {
+			return false
+		}
+
+		request.Amount = asset.SplitRatio
+	} else if asset.Amount < 0 || asset.Price < 0 {
+		return false
+	}
+
+	return binding.Validator.ValidateStruct(&request) == nil
+}
+
+// isValidTransactionImport runs the validation of created transactions on the
+// restored transaction.
+func isValidTransactionImport(transaction Transaction) bool {
+	request := requests.TransactionCreate{
+		Title:           transaction.Title,
+		Description:     transaction.Description,
+		Category:        &transaction.Category,
+		CategoryID:      transaction.CategoryID,
+		Price:           transaction.Price,
+		Currency:        transaction.Currency,
+		TransactionDate: transaction.TransactionDate,
+	}
+
+	if transaction.TransactionMethod != nil {
+		if transaction.TransactionMethod.Type != BankAcc && transaction.TransactionMethod.Type != CreditCard {
+			return false
+		}
+
+		request.TransactionMethod = &requests.TransactionMethod{
+			MethodID: transaction.TransactionMethod.MethodID,
+			Type:     &transaction.TransactionMethod.Type,
+		}
+	}
+
+	return binding.Validator.ValidateStruct(&request) == nil
+}
+
+// getCategoryRootBuiltIns returns the built-in category transactions of each
+// category are counted as, same as CategoryModel.GetRootBuiltIn.
+func getCategoryRootBuiltIns(categories []Category) map[string]int64 {
+	categoriesByID := make(map[string]Category, len(categories))
+	for _, category := range categories {
+		categoriesByID[category.ID.Hex()] = category
+	}
+
+	rootBuiltIns := make(map[string]int64, len(categories))
+	for id, category := range categoriesByID {
+		if category.ParentID != nil {
+			if parent, ok := categoriesByID[*category.ParentID]; ok {
+				category = parent
+			}
+		}
+
+		rootBuiltIns[id] = Others
+		if category.BuiltIn != nil {
+			rootBuiltIns[id] = *category.BuiltIn
+		}
+	}
+
+	return rootBuiltIns
+}
+
+// Built-in categories are matched by their built-in category, the others by
+// parent and name.
+func getCategoryImportKey(category Category) string {
+	if category.BuiltIn != nil {
+		return fmt.Sprintf("built-in/%d", *category.BuiltIn)
+	}
+
+	var parentID string
+	if category.ParentID != nil {
+		parentID = *category.ParentID
+	}
+
+	return parentID + "/" + category.Name
+}
+
+func getSubscriptionImportKey(subscription Subscription) string {
+	return subscription.Name + "/" + subscription.BillDate.UTC().Format("2006-01-02")
+}
+
+func getTransactionImportKey(transaction Transaction) string {
+	return fmt.Sprintf(
+		"%s/%v/%s/%s",
+		transaction.Title, transaction.Price, transaction.Currency,
+		transaction.TransactionDate.UTC().Format(time.RFC3339),
+	)
+}
+
+func getAssetImportKey(asset Asset) string {
+	return fmt.Sprintf(
+		"%s/%s/%s/%v/%v/%s",
+		asset.ToAsset, asset.FromAsset, asset.Type, asset.Amount, asset.Price,
+		asset.CreatedAt.UTC().Format(time.RFC3339),
+	)
+}
+
+func isImportLimitReached(limit int64, count int) bool {
+	return limit >= 0 && int64(count) >= limit
+}
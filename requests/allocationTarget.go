@@ -0,0 +1,13 @@
+package requests
+
+type AllocationTargetUpdate struct {
+	TargetType     string                 `json:"target_type" binding:"required,oneof=asset_type asset"`
+	Targets        []AllocationTargetItem `json:"targets" binding:"required,min=1,dive"`
+	DriftTolerance *float64               `json:"drift_tolerance" binding:"omitempty,min=0,max=100"`
+	MinTradeSize   *float64               `json:"min_trade_size" binding:"omitempty,min=0"`
+}
+
+type AllocationTargetItem struct {
+	Key        string  `json:"key" binding:"required"`
+	Percentage float64 `json:"percentage" binding:"required,gt=0,max=100"`
+}
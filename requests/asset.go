@@ -1,13 +1,17 @@
 package requests
 
+import "time"
+
+// Amount is the split ratio for splits, e.g. 2 for a 2-for-1 split. Dividend
+// and fee values are price times amount and don't change the amount.
 type AssetCreate struct {
 	ToAsset     string  `json:"to_asset" binding:"required"`
 	FromAsset   string  `json:"from_asset" binding:"required"`
-	Price       float64 `json:"price" binding:"required"`
+	Price       float64 `json:"price" binding:"required_unless=Type split"`
 	Amount      float64 `json:"amount" binding:"required"`
-	AssetType   string  `json:"asset_type" binding:"required,oneof=crypto stock exchange commodity"`
+	AssetType   string  `json:"asset_type" binding:"required,oneof=crypto stock exchange commodity custom"`
 	AssetMarket string  `json:"asset_market"`
-	Type        string  `json:"type" binding:"required,oneof=sell buy"`
+	Type        string  `json:"type" binding:"required,oneof=sell buy dividend staking airdrop split fee"`
 }
 
 type AssetSortFilter struct {
@@ -32,7 +36,7 @@ type AssetLog struct {
 
 type AssetUpdate struct {
 	ID     string   `json:"id" binding:"required"`
-	Type   *string  `json:"type"`
+	Type   *string  `json:"type" binding:"omitempty,oneof=sell buy dividend staking airdrop fee"`
 	Price  *float64 `json:"price"`
 	Amount *float64 `json:"amount"`
 }
@@ -42,3 +46,32 @@ type AssetLogsDelete struct {
 	FromAsset   string `json:"from_asset" binding:"required"`
 	AssetMarket string `json:"asset_market" binding:"required"`
 }
+
+type AssetTaxReport struct {
+	Year int `form:"year" json:"year" binding:"required,number,min=1970"`
+}
+
+type AssetImport struct {
+	Profile     string `form:"profile" binding:"required,oneof=generic binance coinbase"`
+	AssetMarket string `form:"asset_market"`
+	DryRun      bool   `form:"dry_run"`
+}
+
+type AssetImportRow struct {
+	AssetCreate
+	Row        int       `json:"row"`
+	CreatedAt  time.Time `json:"created_at"`
+	SplitRatio float64   `json:"split_ratio,omitempty"`
+}
+
+type AssetPerformance struct {
+	StartDate *time.Time `form:"start_date" time_format:"2006-01-02"`
+	EndDate   *time.Time `form:"end_date" time_format:"2006-01-02"`
+}
+
+type CustomAssetValuationUpdate struct {
+	ToAsset   string  `json:"to_asset" binding:"required"`
+	FromAsset string  `json:"from_asset" binding:"required"`
+	Price     float64 `json:"price" binding:"required,min=0"`
+	Name      *string `json:"name"`
+}
@@ -0,0 +1,11 @@
+package requests
+
+type BudgetCreate struct {
+	Category *int64  `json:"category" binding:"required"`
+	Limit    float64 `json:"limit" binding:"required,gt=0"`
+}
+
+type BudgetUpdate struct {
+	ID    string   `json:"id" binding:"required"`
+	Limit *float64 `json:"limit" binding:"omitempty,gt=0"`
+}
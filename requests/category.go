@@ -0,0 +1,24 @@
+package requests
+
+type CategoryCreate struct {
+	Name     string  `json:"name" binding:"required"`
+	Color    string  `json:"color" binding:"required"`
+	ParentID *string `json:"parent_id"`
+}
+
+type CategoryUpdate struct {
+	ID                 string  `json:"id" binding:"required"`
+	Name               *string `json:"name"`
+	Color              *string `json:"color"`
+	ParentID           *string `json:"parent_id"`
+	ShouldDeleteParent *bool   `json:"delete_parent"`
+}
+
+type CategoryArchive struct {
+	ID         string `json:"id" binding:"required"`
+	IsArchived *bool  `json:"is_archived" binding:"required"`
+}
+
+type CategoryList struct {
+	IncludeArchived bool `form:"include_archived"`
+}
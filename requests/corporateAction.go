@@ -0,0 +1,14 @@
+package requests
+
+import "time"
+
+// Symbol changes need the new symbol and splits the ratio, e.g. 4 for a 4:1 split.
+type CorporateActionCreate struct {
+	ActionType    string    `json:"action_type" binding:"required,oneof=split symbol_change"`
+	Symbol        string    `json:"symbol" binding:"required"`
+	NewSymbol     string    `json:"new_symbol" binding:"required_if=ActionType symbol_change"`
+	AssetType     string    `json:"asset_type" binding:"required,oneof=crypto stock exchange commodity"`
+	AssetMarket   string    `json:"asset_market" binding:"required"`
+	SplitRatio    float64   `json:"split_ratio" binding:"required_if=ActionType split,omitempty,gt=0"`
+	EffectiveDate time.Time `json:"effective_date" binding:"required" time_format:"2006-01-02"`
+}
@@ -1,5 +1,31 @@
 package requests
 
+import "time"
+
 type DailyAssetStatsInterval struct {
-	Interval string `form:"interval" binding:"required,oneof=weekly monthly yearly"`
+	Interval  string     `form:"interval" binding:"required,oneof=weekly monthly yearly custom all"`
+	StartDate *time.Time `form:"start_date" binding:"required_if=Interval custom" time_format:"2006-01-02"`
+	EndDate   *time.Time `form:"end_date" binding:"required_if=Interval custom" time_format:"2006-01-02"`
+	Bucket    string     `form:"bucket" binding:"omitempty,oneof=daily weekly monthly"`
+}
+
+type DailyAssetHoldingStats struct {
+	ToAsset   string     `form:"to_asset" binding:"required"`
+	FromAsset string     `form:"from_asset" binding:"required"`
+	Interval  string     `form:"interval" binding:"required,oneof=weekly monthly yearly custom all"`
+	StartDate *time.Time `form:"start_date" binding:"required_if=Interval custom" time_format:"2006-01-02"`
+	EndDate   *time.Time `form:"end_date" binding:"required_if=Interval custom" time_format:"2006-01-02"`
+}
+
+type DailyAssetTypeStats struct {
+	AssetType string     `form:"asset_type" binding:"required,oneof=crypto stock exchange commodity custom"`
+	Interval  string     `form:"interval" binding:"required,oneof=weekly monthly yearly custom all"`
+	StartDate *time.Time `form:"start_date" binding:"required_if=Interval custom" time_format:"2006-01-02"`
+	EndDate   *time.Time `form:"end_date" binding:"required_if=Interval custom" time_format:"2006-01-02"`
+}
+
+type DailyAssetStatsBackfill struct {
+	UserID    string     `json:"user_id"`
+	StartDate time.Time  `json:"start_date" binding:"required" time_format:"2006-01-02"`
+	EndDate   *time.Time `json:"end_date" time_format:"2006-01-02"`
 }
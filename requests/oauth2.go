@@ -1,10 +1,14 @@
 package requests
 
 type GoogleLogin struct {
-	Token string `json:"token" binding:"required"`
+	Token      string `json:"token" binding:"required"`
+	DeviceID   string `json:"device_id"`
+	DeviceName string `json:"device_name"`
 }
 
 type AppleSignin struct {
-	Code      string `json:"code" binding:"required"`
-	IsRefresh *bool  `json:"is_refresh" binding:"required"`
+	Code       string `json:"code" binding:"required"`
+	IsRefresh  *bool  `json:"is_refresh" binding:"required"`
+	DeviceID   string `json:"device_id"`
+	DeviceName string `json:"device_name"`
 }
@@ -0,0 +1,19 @@
+package requests
+
+type PriceAlertCreate struct {
+	Symbol      string  `json:"symbol" binding:"required"`
+	Type        string  `json:"type" binding:"required"`
+	Market      string  `json:"market" binding:"required"`
+	AlertType   string  `json:"alert_type" binding:"required,oneof=above below change"`
+	Value       float64 `json:"value" binding:"required,gt=0"`
+	IsRecurring bool    `json:"is_recurring"`
+	SendEmail   bool    `json:"send_email"`
+}
+
+type PriceAlertUpdate struct {
+	ID          string   `json:"id" binding:"required"`
+	Value       *float64 `json:"value" binding:"omitempty,gt=0"`
+	IsRecurring *bool    `json:"is_recurring"`
+	SendEmail   *bool    `json:"send_email"`
+	IsActive    *bool    `json:"is_active"`
+}
@@ -5,7 +5,8 @@ import "time"
 type TransactionCreate struct {
 	Title             string             `json:"title" binding:"required"`
 	Description       *string            `json:"description"`
-	Category          *int64             `json:"category" binding:"required"`
+	Category          *int64             `json:"category" binding:"required_without=CategoryID,omitempty,min=0,max=7"`
+	CategoryID        *string            `json:"category_id"`
 	Price             float64            `json:"price" binding:"required"`
 	Currency          string             `json:"currency" binding:"required"`
 	TransactionMethod *TransactionMethod `json:"method"`
@@ -21,7 +22,8 @@ type TransactionUpdate struct {
 	ID                 string             `json:"id" binding:"required"`
 	Title              *string            `json:"title"`
 	Description        *string            `json:"description"`
-	Category           *int64             `json:"category"`
+	Category           *int64             `json:"category" binding:"omitempty,min=0,max=7"`
+	CategoryID         *string            `json:"category_id"`
 	Price              *float64           `json:"price"`
 	Currency           *string            `json:"currency"`
 	TransactionMethod  *TransactionMethod `json:"method"`
@@ -30,14 +32,15 @@ type TransactionUpdate struct {
 }
 
 type TransactionSortFilter struct {
-	Category  *int       `form:"category"`
-	StartDate *time.Time `form:"start_date" time_format:"2006-01-02"`
-	EndDate   *time.Time `form:"end_date" time_format:"2006-01-02"`
-	BankAccID *string    `form:"bank_id"`
-	CardID    *string    `form:"card_id"`
-	Page      int64      `form:"page" binding:"required,number,min=1"`
-	Sort      string     `form:"sort" binding:"required,oneof=price date"`
-	SortType  int        `form:"type" json:"type" binding:"required,oneof=1 -1"`
+	Category   *int       `form:"category"`
+	CategoryID *string    `form:"category_id"`
+	StartDate  *time.Time `form:"start_date" time_format:"2006-01-02"`
+	EndDate    *time.Time `form:"end_date" time_format:"2006-01-02"`
+	BankAccID  *string    `form:"bank_id"`
+	CardID     *string    `form:"card_id"`
+	Page       int64      `form:"page" binding:"required,number,min=1"`
+	Sort       string     `form:"sort" binding:"required,oneof=price date"`
+	SortType   int        `form:"type" json:"type" binding:"required,oneof=1 -1"`
 }
 
 type TransactionTotalInterval struct {
@@ -48,3 +51,44 @@ type TransactionTotalInterval struct {
 type TransactionStatsInterval struct {
 	Interval string `form:"interval" binding:"required,oneof=weekly monthly yearly"`
 }
+
+type RecurringTransactionCreate struct {
+	Title             string             `json:"title" binding:"required"`
+	Description       *string            `json:"description"`
+	Category          *int64             `json:"category" binding:"required_without=CategoryID,omitempty,min=0,max=7"`
+	CategoryID        *string            `json:"category_id"`
+	Price             float64            `json:"price" binding:"required"`
+	Currency          string             `json:"currency" binding:"required"`
+	TransactionMethod *TransactionMethod `json:"method"`
+	Frequency         string             `json:"frequency" binding:"required,oneof=daily weekly monthly yearly"`
+	Interval          int                `json:"interval" binding:"required,number,min=1"`
+	StartDate         time.Time          `json:"start_date" binding:"required" time_format:"2006-01-02"`
+	EndDate           *time.Time         `json:"end_date" time_format:"2006-01-02"`
+}
+
+type RecurringTransactionUpdate struct {
+	ID                  string             `json:"id" binding:"required"`
+	Title               *string            `json:"title"`
+	Description         *string            `json:"description"`
+	Category            *int64             `json:"category" binding:"omitempty,min=0,max=7"`
+	CategoryID          *string            `json:"category_id"`
+	Price               *float64           `json:"price"`
+	Currency            *string            `json:"currency"`
+	TransactionMethod   *TransactionMethod `json:"method"`
+	ShouldDeleteMethod  *bool              `json:"delete_method"`
+	Frequency           *string            `json:"frequency" binding:"omitempty,oneof=daily weekly monthly yearly"`
+	Interval            *int               `json:"interval" binding:"omitempty,number,min=1"`
+	StartDate           *time.Time         `json:"start_date"`
+	EndDate             *time.Time         `json:"end_date"`
+	ShouldDeleteEndDate *bool              `json:"delete_end_date"`
+}
+
+type RecurringTransactionPause struct {
+	ID       string `json:"id" binding:"required"`
+	IsPaused *bool  `json:"is_paused" binding:"required"`
+}
+
+type RecurringTransactionPreview struct {
+	ID    string `form:"id" binding:"required"`
+	Count int    `form:"count" binding:"omitempty,number,min=1,max=50"`
+}
@@ -1,8 +1,16 @@
 package requests
 
+// Device ID identifies the session of the device, logging in again from the
+// same device replaces it.
 type Login struct {
 	EmailAddress string `json:"email_address" binding:"required,email"`
 	Password     string `json:"password" binding:"required"`
+	DeviceID     string `json:"device_id"`
+	DeviceName   string `json:"device_name"`
+}
+
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 type Register struct {
@@ -20,6 +28,10 @@ type ChangeCurrency struct {
 	Currency string `json:"currency" binding:"required"`
 }
 
+type ChangeCostBasisMethod struct {
+	CostBasisMethod string `json:"cost_basis_method" binding:"required,oneof=fifo lifo average"`
+}
+
 type ChangeFCMToken struct {
 	FCMToken string `json:"fcm_token" binding:"required"`
 }
@@ -29,11 +41,26 @@ type ChangeNotification struct {
 	MailNotification *bool `json:"mail_notification" binding:"required"`
 }
 
+// Receipt is the base64 receipt for App Store and the purchase token for Play Store.
 type ChangeMembership struct {
-	IsPremium         bool `json:"is_premium"`
-	IsLifetimePremium bool `json:"is_lifetime_premium"`
+	Platform  string `json:"platform" binding:"required,oneof=app_store play_store"`
+	ProductID string `json:"product_id" binding:"required"`
+	Receipt   string `json:"receipt" binding:"required"`
 }
 
 type ForgotPassword struct {
 	EmailAddress string `json:"email_address" binding:"required,email"`
 }
+
+type UserExport struct {
+	IncludePasswords bool `form:"include_passwords"`
+}
+
+type TwoFactorCode struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorLogin struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
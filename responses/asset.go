@@ -11,9 +11,14 @@ type Asset struct {
 	AssetMarket     string  `bson:"asset_market" json:"asset_market"`
 	TotalBought     float64 `bson:"total_bought" json:"total_bought"`
 	TotalSold       float64 `bson:"total_sold" json:"total_sold"`
+	TotalIncome     float64 `bson:"total_income" json:"total_income"`
+	TotalFees       float64 `bson:"total_fees" json:"total_fees"`
 	PL              float64 `bson:"p/l" json:"p/l"`
 	CurrentTotal    float64 `bson:"current_total_value" json:"current_total_value"`
 	PLPercentage    float64 `bson:"pl_percentage" json:"pl_percentage"`
+	CostBasis       float64 `bson:"cost_basis" json:"cost_basis"`
+	RealizedPL      float64 `bson:"realized_p/l" json:"realized_p/l"`
+	UnrealizedPL    float64 `bson:"unrealized_p/l" json:"unrealized_p/l"`
 }
 
 type AssetDetails struct {
@@ -23,9 +28,15 @@ type AssetDetails struct {
 	RemainingAmount float64 `bson:"remaining_amount" json:"remaining_amount"`
 	TotalBought     float64 `bson:"total_bought" json:"total_bought"`
 	TotalSold       float64 `bson:"total_sold" json:"total_sold"`
+	TotalIncome     float64 `bson:"total_income" json:"total_income"`
+	TotalFees       float64 `bson:"total_fees" json:"total_fees"`
 	CurrentTotal    float64 `bson:"current_total_value" json:"current_total_value"`
 	PL              float64 `bson:"p/l" json:"p/l"`
 	PLPercentage    float64 `bson:"pl_percentage" json:"pl_percentage"`
+	CostBasis       float64 `bson:"cost_basis" json:"cost_basis"`
+	RealizedPL      float64 `bson:"realized_p/l" json:"realized_p/l"`
+	UnrealizedPL    float64 `bson:"unrealized_p/l" json:"unrealized_p/l"`
+	CostBasisMethod string  `bson:"cost_basis_method" json:"cost_basis_method"`
 	AssetType       string  `bson:"asset_type" json:"asset_type"`
 	AssetMarket     string  `bson:"asset_market" json:"asset_market"`
 }
@@ -40,6 +51,11 @@ type AssetLog struct {
 	CurrencyValue float64   `bson:"value" json:"value"`
 }
 
+type AssetPair struct {
+	ToAsset   string `bson:"to_asset" json:"to_asset"`
+	FromAsset string `bson:"from_asset" json:"from_asset"`
+}
+
 type AssetDocumentCount struct {
 	DocumentCount []AssetCount `bson:"document_count" json:"document_count"`
 }
@@ -52,24 +68,112 @@ type AssetStats struct {
 	Currency            string  `bson:"currency" json:"currency"`
 	TotalBought         float64 `bson:"total_bought" json:"total_bought"`
 	TotalSold           float64 `bson:"total_sold" json:"total_sold"`
+	TotalIncome         float64 `bson:"total_income" json:"total_income"`
+	TotalFees           float64 `bson:"total_fees" json:"total_fees"`
 	StockAssets         float64 `bson:"stock_assets" json:"stock_assets"`
 	CryptoAssets        float64 `bson:"crypto_assets" json:"crypto_assets"`
 	ExchangeAssets      float64 `bson:"exchange_assets" json:"exchange_assets"`
 	CommodityAssets     float64 `bson:"commodity_assets" json:"commodity_assets"`
+	CustomAssets        float64 `bson:"custom_assets" json:"custom_assets"`
 	TotalAssets         float64 `bson:"total_assets" json:"total_assets"`
 	StockPL             float64 `bson:"stock_p/l" json:"stock_p/l"`
 	CryptoPL            float64 `bson:"crypto_p/l" json:"crypto_p/l"`
 	ExchangePL          float64 `bson:"exchange_p/l" json:"exchange_p/l"`
 	CommodityPL         float64 `bson:"commodity_p/l" json:"commodity_p/l"`
+	CustomPL            float64 `bson:"custom_p/l" json:"custom_p/l"`
 	TotalPL             float64 `bson:"total_p/l" json:"total_p/l"`
 	TotalPLPercentage   float64 `bson:"total_pl_percentage" json:"total_pl_percentage"`
 	StockPercentage     float64 `bson:"stock_percentage" json:"stock_percentage"`
 	CryptoPercentage    float64 `bson:"crypto_percentage" json:"crypto_percentage"`
 	ExchangePercentage  float64 `bson:"exchange_percentage" json:"exchange_percentage"`
 	CommodityPercentage float64 `bson:"commodity_percentage" json:"commodity_percentage"`
+	CustomPercentage    float64 `bson:"custom_percentage" json:"custom_percentage"`
 }
 
 type AssetAndStats struct {
 	Data  []Asset    `bson:"data" json:"data"`
 	Stats AssetStats `bson:"stats" json:"stats"`
 }
+
+type AssetTaxReport struct {
+	Year            int                `bson:"year" json:"year"`
+	Currency        string             `bson:"currency" json:"currency"`
+	CostBasisMethod string             `bson:"cost_basis_method" json:"cost_basis_method"`
+	TotalProceeds   float64            `bson:"total_proceeds" json:"total_proceeds"`
+	TotalCostBasis  float64            `bson:"total_cost_basis" json:"total_cost_basis"`
+	TotalGain       float64            `bson:"total_gain" json:"total_gain"`
+	ShortTermGain   float64            `bson:"short_term_gain" json:"short_term_gain"`
+	LongTermGain    float64            `bson:"long_term_gain" json:"long_term_gain"`
+	Disposals       []AssetTaxDisposal `bson:"disposals" json:"disposals"`
+}
+
+type AssetTaxDisposal struct {
+	ToAsset               string    `bson:"to_asset" json:"to_asset"`
+	FromAsset             string    `bson:"from_asset" json:"from_asset"`
+	AssetType             string    `bson:"asset_type" json:"asset_type"`
+	AssetMarket           string    `bson:"asset_market" json:"asset_market"`
+	Amount                float64   `bson:"amount" json:"amount"`
+	AcquiredAt            time.Time `bson:"acquired_at" json:"acquired_at"`
+	DisposedAt            time.Time `bson:"disposed_at" json:"disposed_at"`
+	Proceeds              float64   `bson:"proceeds" json:"proceeds"`
+	CostBasis             float64   `bson:"cost_basis" json:"cost_basis"`
+	Gain                  float64   `bson:"gain" json:"gain"`
+	HoldingPeriod         string    `bson:"holding_period" json:"holding_period"`
+	ProceedsExchangeRate  float64   `bson:"proceeds_exchange_rate" json:"proceeds_exchange_rate"`
+	CostBasisExchangeRate float64   `bson:"cost_basis_exchange_rate" json:"cost_basis_exchange_rate"`
+}
+
+type AssetImport struct {
+	DryRun       bool               `bson:"dry_run" json:"dry_run"`
+	TotalRows    int                `bson:"total_rows" json:"total_rows"`
+	ValidRows    int                `bson:"valid_rows" json:"valid_rows"`
+	InsertedRows int                `bson:"inserted_rows" json:"inserted_rows"`
+	Errors       []AssetImportError `bson:"errors" json:"errors"`
+}
+
+type AssetImportError struct {
+	Row   int    `bson:"row" json:"row"`
+	Error string `bson:"error" json:"error"`
+}
+
+type AssetRebalance struct {
+	Currency       string               `bson:"currency" json:"currency"`
+	TargetType     string               `bson:"target_type" json:"target_type"`
+	TotalValue     float64              `bson:"total_value" json:"total_value"`
+	DriftTolerance float64              `bson:"drift_tolerance" json:"drift_tolerance"`
+	MinTradeSize   float64              `bson:"min_trade_size" json:"min_trade_size"`
+	IsBalanced     bool                 `bson:"is_balanced" json:"is_balanced"`
+	Items          []AssetRebalanceItem `bson:"items" json:"items"`
+}
+
+type AssetRebalanceItem struct {
+	Key               string  `bson:"key" json:"key"`
+	CurrentValue      float64 `bson:"current_value" json:"current_value"`
+	CurrentPercentage float64 `bson:"current_percentage" json:"current_percentage"`
+	TargetPercentage  float64 `bson:"target_percentage" json:"target_percentage"`
+	Drift             float64 `bson:"drift" json:"drift"`
+	TradeValue        float64 `bson:"trade_value" json:"trade_value"`
+	TradeAmount       float64 `bson:"trade_amount" json:"trade_amount"`
+	Action            string  `bson:"action" json:"action"`
+}
+
+type AssetPerformance struct {
+	Currency   string                 `bson:"currency" json:"currency"`
+	StartDate  time.Time              `bson:"start_date" json:"start_date"`
+	EndDate    time.Time              `bson:"end_date" json:"end_date"`
+	Portfolio  AssetPerformanceItem   `bson:"portfolio" json:"portfolio"`
+	AssetTypes []AssetPerformanceItem `bson:"asset_types" json:"asset_types"`
+	Holdings   []AssetPerformanceItem `bson:"holdings" json:"holdings"`
+}
+
+type AssetPerformanceItem struct {
+	ToAsset       string   `bson:"to_asset,omitempty" json:"to_asset,omitempty"`
+	FromAsset     string   `bson:"from_asset,omitempty" json:"from_asset,omitempty"`
+	AssetType     string   `bson:"asset_type,omitempty" json:"asset_type,omitempty"`
+	StartValue    float64  `bson:"start_value" json:"start_value"`
+	EndValue      float64  `bson:"end_value" json:"end_value"`
+	NetCashFlow   float64  `bson:"net_cash_flow" json:"net_cash_flow"`
+	TWR           float64  `bson:"twr" json:"twr"`
+	AnnualizedTWR float64  `bson:"annualized_twr" json:"annualized_twr"`
+	XIRR          *float64 `bson:"xirr" json:"xirr"`
+}
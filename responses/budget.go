@@ -0,0 +1,17 @@
+package responses
+
+type BudgetStatus struct {
+	Currency string                 `json:"currency"`
+	Month    string                 `json:"month"`
+	Budgets  []BudgetCategoryStatus `json:"budgets"`
+}
+
+type BudgetCategoryStatus struct {
+	ID         string  `json:"_id"`
+	Category   int64   `json:"category"`
+	Limit      float64 `json:"limit"`
+	Spent      float64 `json:"spent"`
+	Remaining  float64 `json:"remaining"`
+	Projected  float64 `json:"projected"`
+	Percentage float64 `json:"percentage"`
+}
@@ -0,0 +1,14 @@
+package responses
+
+type CorporateActionPreview struct {
+	UserCount int                          `json:"user_count"`
+	LogCount  int                          `json:"log_count"`
+	Users     []CorporateActionPreviewUser `json:"users"`
+}
+
+type CorporateActionPreviewUser struct {
+	UserID          string  `bson:"_id" json:"user_id"`
+	LogCount        int     `bson:"log_count" json:"log_count"`
+	RemainingAmount float64 `bson:"remaining_amount" json:"remaining_amount"`
+	AdjustedAmount  float64 `bson:"adjusted_amount" json:"adjusted_amount"`
+}
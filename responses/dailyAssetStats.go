@@ -14,9 +14,60 @@ type DailyAssetStatsCalculation struct {
 	TotalPL     float64            `bson:"total_p/l" json:"total_p/l"`
 }
 
+// Open, close, min and max of total assets are only set for bucketed stats,
+// total assets and p/l are the close values of the buckets.
 type DailyAssetStats struct {
 	Currency    string      `bson:"currency" json:"currency"`
 	TotalAssets []float64   `bson:"total_assets" json:"total_assets"`
 	TotalPL     []float64   `bson:"total_p/l" json:"total_p/l"`
 	Dates       []time.Time `bson:"dates" json:"dates"`
+	Open        []float64   `bson:"open" json:"open,omitempty"`
+	Close       []float64   `bson:"close" json:"close,omitempty"`
+	Min         []float64   `bson:"min" json:"min,omitempty"`
+	Max         []float64   `bson:"max" json:"max,omitempty"`
+}
+
+type DailyAssetHoldingStatsCalculation struct {
+	Currency    string             `bson:"currency" json:"currency"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	ToAsset     string             `bson:"to_asset" json:"to_asset"`
+	FromAsset   string             `bson:"from_asset" json:"from_asset"`
+	AssetType   string             `bson:"asset_type" json:"asset_type"`
+	Amount      float64            `bson:"amount" json:"amount"`
+	Price       float64            `bson:"price" json:"price"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	TotalAssets float64            `bson:"total_assets" json:"total_assets"`
+	TotalPL     float64            `bson:"total_p/l" json:"total_p/l"`
+}
+
+type DailyAssetTypeStatsCalculation struct {
+	Currency    string             `bson:"currency" json:"currency"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	AssetType   string             `bson:"asset_type" json:"asset_type"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	TotalAssets float64            `bson:"total_assets" json:"total_assets"`
+	TotalPL     float64            `bson:"total_p/l" json:"total_p/l"`
+}
+
+type DailyAssetHoldingStats struct {
+	Currency    string      `bson:"currency" json:"currency"`
+	Amounts     []float64   `bson:"amount" json:"amount"`
+	Prices      []float64   `bson:"price" json:"price"`
+	TotalAssets []float64   `bson:"total_assets" json:"total_assets"`
+	TotalPL     []float64   `bson:"total_p/l" json:"total_p/l"`
+	Dates       []time.Time `bson:"dates" json:"dates"`
+}
+
+type DailyAssetTypeStats struct {
+	Currency    string      `bson:"currency" json:"currency"`
+	TotalAssets []float64   `bson:"total_assets" json:"total_assets"`
+	TotalPL     []float64   `bson:"total_p/l" json:"total_p/l"`
+	Dates       []time.Time `bson:"dates" json:"dates"`
+}
+
+type DailyAssetStatsBackfill struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	UserCount int       `json:"user_count"`
+	DayCount  int       `json:"day_count"`
 }
@@ -0,0 +1,32 @@
+package responses
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Card balances are outstanding balances, they're subtracted from net worth.
+type NetWorth struct {
+	Currency         string            `bson:"currency" json:"currency"`
+	TotalAssets      float64           `bson:"total_assets" json:"total_assets"`
+	TotalBankBalance float64           `bson:"total_bank_balance" json:"total_bank_balance"`
+	TotalCardBalance float64           `bson:"total_card_balance" json:"total_card_balance"`
+	NetWorth         float64           `bson:"net_worth" json:"net_worth"`
+	BankAccounts     []NetWorthAccount `bson:"-" json:"bank_accounts"`
+	Cards            []NetWorthAccount `bson:"-" json:"cards"`
+}
+
+type NetWorthAccount struct {
+	ID               string  `json:"_id"`
+	Name             string  `json:"name"`
+	Currency         string  `json:"currency"`
+	Balance          float64 `json:"balance"`
+	ConvertedBalance float64 `json:"converted_balance"`
+}
+
+type DailyNetWorthCalculation struct {
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	NetWorth  `bson:",inline"`
+}
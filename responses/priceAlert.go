@@ -0,0 +1,22 @@
+package responses
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PriceAlert struct {
+	ID              primitive.ObjectID   `bson:"_id" json:"_id"`
+	UserID          string               `bson:"user_id" json:"user_id"`
+	InvestingID     FavouriteInvestingID `bson:"investing_id" json:"investing_id"`
+	AlertType       string               `bson:"alert_type" json:"alert_type"`
+	Value           float64              `bson:"value" json:"value"`
+	IsRecurring     bool                 `bson:"is_recurring" json:"is_recurring"`
+	SendEmail       bool                 `bson:"send_email" json:"send_email"`
+	IsActive        bool                 `bson:"is_active" json:"is_active"`
+	LastTriggeredAt *time.Time           `bson:"last_triggered_at" json:"last_triggered_at"`
+	Name            string               `bson:"name" json:"name"`
+	Price           float64              `bson:"price" json:"price"`
+	Currency        string               `bson:"currency" json:"currency"`
+}
@@ -3,8 +3,16 @@ package responses
 import "time"
 
 type TransactionTotal struct {
-	Currency         string  `bson:"currency" json:"currency"`
-	TotalTransaction float64 `bson:"total_transaction" json:"total_transaction"`
+	Currency          string                     `bson:"currency" json:"currency"`
+	TotalTransaction  float64                    `bson:"total_transaction" json:"total_transaction"`
+	CurrencyBreakdown []TransactionCurrencyTotal `bson:"currency_breakdown" json:"currency_breakdown"`
+}
+
+type TransactionCurrencyTotal struct {
+	Currency            string  `bson:"currency" json:"currency"`
+	Total               float64 `bson:"total" json:"total"`
+	ConvertedTotal      float64 `bson:"converted_total" json:"converted_total"`
+	MissingExchangeRate bool    `bson:"missing_exchange_rate" json:"missing_exchange_rate"`
 }
 
 type TransactionStats struct {
@@ -15,18 +23,29 @@ type TransactionStats struct {
 }
 
 type TransactionDailyStats struct {
-	Currency         string    `bson:"currency" json:"currency"`
-	TotalTransaction float64   `bson:"total_transaction" json:"total_transaction"`
-	Date             time.Time `bson:"date" json:"date"`
+	Currency          string                     `bson:"currency" json:"currency"`
+	TotalTransaction  float64                    `bson:"total_transaction" json:"total_transaction"`
+	CurrencyBreakdown []TransactionCurrencyTotal `bson:"currency_breakdown" json:"currency_breakdown"`
+	Date              time.Time                  `bson:"date" json:"date"`
 }
 
 type TransactionCategoryStats struct {
-	Currency         string                    `bson:"currency" json:"currency"`
-	TotalTransaction float64                   `bson:"total_transaction" json:"total_transaction"`
-	CategoryList     []TransactionCategoryStat `bson:"category_list" json:"category_list"`
+	Currency          string                     `bson:"currency" json:"currency"`
+	TotalTransaction  float64                    `bson:"total_transaction" json:"total_transaction"`
+	CurrencyBreakdown []TransactionCurrencyTotal `bson:"currency_breakdown" json:"currency_breakdown"`
+	CategoryList      []TransactionCategoryStat  `bson:"category_list" json:"category_list"`
 }
 
 type TransactionCategoryStat struct {
-	CategoryID               int64   `bson:"_id" json:"_id"`
+	CategoryID               int64   `bson:"category" json:"_id"`
+	UserCategoryID           *string `bson:"category_id" json:"category_id"`
+	Name                     *string `bson:"name" json:"name"`
+	Color                    *string `bson:"color" json:"color"`
+	ParentID                 *string `bson:"parent_id" json:"parent_id"`
 	TotalCategoryTransaction float64 `bson:"total_transaction" json:"total_transaction"`
 }
+
+type RecurringTransactionPreview struct {
+	RecurringID string      `json:"recurring_id"`
+	Occurrences []time.Time `json:"occurrences"`
+}
@@ -9,11 +9,55 @@ type UserInfo struct {
 	IsPremium         bool   `bson:"is_premium" json:"is_premium"`
 	IsLifetimePremium bool   `bson:"is_lifetime_premium" json:"is_lifetime_premium"`
 	IsOAuth           bool   `bson:"is_oauth" json:"is_oauth"`
+	IsTwoFactor       bool   `bson:"two_factor_enabled" json:"is_two_factor"`
+	IsVerified        bool   `bson:"is_verified" json:"is_verified"`
 	AppNotification   bool   `bson:"app_notification" json:"app_notification"`
 	EmailAddress      string `bson:"email_address" json:"email_address"`
 	Currency          string `bson:"currency" json:"currency"`
+	CostBasisMethod   string `bson:"cost_basis_method" json:"cost_basis_method"`
 	InvestingLimit    string `bson:"investing_limit" json:"investing_limit"`
 	SubscriptionLimit string `bson:"subscription_limit" json:"subscription_limit"`
 	WatchlistLimit    string `bson:"watchlist_limit" json:"watchlist_limit"`
 	FCMToken          string `bson:"fcm_token" json:"fcm_token"`
 }
+
+type UserDataImport struct {
+	Collections []UserDataImportResult `json:"collections"`
+}
+
+type UserDataImportResult struct {
+	Collection string                   `json:"collection"`
+	Imported   int                      `json:"imported"`
+	Conflicts  []UserDataImportConflict `json:"conflicts"`
+}
+
+type UserDataImportConflict struct {
+	ID     string `json:"_id"`
+	Reason string `json:"reason"`
+}
+
+type Entitlements struct {
+	Plan      string             `json:"plan"`
+	Resources []EntitlementUsage `json:"resources"`
+	Features  map[string]bool    `json:"features"`
+}
+
+// Limit is -1 for unlimited resources.
+type EntitlementUsage struct {
+	Resource    string `json:"resource"`
+	Usage       int64  `json:"usage"`
+	Limit       int64  `json:"limit"`
+	IsUnlimited bool   `json:"is_unlimited"`
+	IsReached   bool   `json:"is_reached"`
+}
+
+// TwoFactorSetup has the secret for manual entry and the otpauth URL to show
+// as QR code.
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
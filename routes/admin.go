@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"asset_backend/controllers"
+	"asset_backend/db"
+	"asset_backend/helpers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func adminRouter(router *gin.RouterGroup, mongoDB *db.MongoDB) {
+	adminController := controllers.NewAdminController(mongoDB)
+
+	admin := router.Group("/admin").Use(helpers.AdminMiddleware())
+	{
+		admin.POST("/daily-asset-stats/backfill", adminController.BackfillDailyAssetStats)
+		admin.POST("/corporate-action", adminController.CreateCorporateAction)
+		admin.POST("/corporate-action/preview", adminController.PreviewCorporateAction)
+		admin.GET("/corporate-action", adminController.GetCorporateActions)
+		admin.DELETE("/corporate-action", adminController.DeleteCorporateActionByID)
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"asset_backend/controllers"
 	"asset_backend/db"
+	"asset_backend/entitlements"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
@@ -17,14 +18,25 @@ func assetRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware, mongoD
 	{
 		asset.DELETE("/log", assetController.DeleteAssetLogByAssetID)
 		asset.DELETE("/logs", assetController.DeleteAssetLogsByUserID)
+		asset.DELETE("/allocation", assetController.DeleteAllocationTarget)
 		asset.DELETE("", assetController.DeleteAllAssetsByUserID)
+		asset.PUT("/allocation", assetController.UpdateAllocationTarget)
+		asset.PUT("/custom/valuation", assetController.UpdateCustomAssetValuation)
 		asset.PUT("", assetController.UpdateAssetLogByAssetID)
-		asset.POST("", assetController.CreateAsset)
+		asset.POST("", entitlements.QuotaMiddleware(mongoDB, entitlements.ResourceAsset), assetController.CreateAsset)
 		asset.POST("/log", assetController.CreateAssetLog)
+		asset.POST("/import", assetController.ImportAssets)
 		asset.GET("/details", assetController.GetAssetStatsByAssetAndUserID)
 		asset.GET("/daily-stats", dailyAssetStatsController.GetAssetStatsByUserID)
+		asset.GET("/daily-stats/holding", dailyAssetStatsController.GetHoldingStatsByUserID)
+		asset.GET("/daily-stats/type", dailyAssetStatsController.GetAssetTypeStatsByUserID)
 		asset.GET("/stats", assetController.GetAllAssetStatsByUserID)
 		asset.GET("/logs", assetController.GetAssetLogsByUserID)
+		asset.GET("/tax-report", assetController.GetTaxReportByUserID)
+		asset.GET("/allocation", assetController.GetAllocationTarget)
+		asset.GET("/custom/valuation", assetController.GetCustomAssetValuations)
+		asset.GET("/rebalance", assetController.GetAssetRebalance)
+		asset.GET("/performance", assetController.GetAssetPerformanceByUserID)
 		asset.GET("", assetController.GetAssetsAndStatsByUserID)
 	}
 
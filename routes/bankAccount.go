@@ -3,6 +3,7 @@ package routes
 import (
 	"asset_backend/controllers"
 	"asset_backend/db"
+	"asset_backend/entitlements"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
@@ -15,7 +16,7 @@ func bankAccountRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware,
 	{
 		bankAccount.DELETE("/all", bankAccountController.DeleteAllBankAccountsByUserID)
 		bankAccount.DELETE("", bankAccountController.DeleteBankAccountByBAID)
-		bankAccount.POST("", bankAccountController.CreateBankAccount)
+		bankAccount.POST("", entitlements.QuotaMiddleware(mongoDB, entitlements.ResourceBankAccount), bankAccountController.CreateBankAccount)
 		bankAccount.PUT("", bankAccountController.UpdateBankAccount)
 		bankAccount.GET("", bankAccountController.GetBankAccountsByUserID)
 		bankAccount.GET("/stats", bankAccountController.GetBankAccountStatistics)
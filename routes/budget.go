@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"asset_backend/controllers"
+	"asset_backend/db"
+	"asset_backend/entitlements"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func budgetRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware, mongoDB *db.MongoDB) {
+	budgetController := controllers.NewBudgetController(mongoDB)
+
+	budget := router.Group("/budget").Use(jwtToken.MiddlewareFunc())
+	{
+		budget.GET("", budgetController.GetBudgetStatus)
+		budget.POST("", entitlements.QuotaMiddleware(mongoDB, entitlements.ResourceBudget), budgetController.CreateBudget)
+		budget.PUT("", budgetController.UpdateBudget)
+		budget.DELETE("", budgetController.DeleteBudgetByBudgetID)
+	}
+}
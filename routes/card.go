@@ -3,6 +3,7 @@ package routes
 import (
 	"asset_backend/controllers"
 	"asset_backend/db"
+	"asset_backend/entitlements"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
@@ -16,7 +17,7 @@ func cardRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware, mongoDB
 		card.DELETE("/all", cardController.DeleteAllCardsByUserID)
 		card.DELETE("", cardController.DeleteCardByCardID)
 		card.PUT("", cardController.UpdateCard)
-		card.POST("", cardController.CreateCard)
+		card.POST("", entitlements.QuotaMiddleware(mongoDB, entitlements.ResourceCard), cardController.CreateCard)
 		card.GET("", cardController.GetCardsByUserID)
 		card.GET("/stats", cardController.GetCardStatisticsByUserIDAndCardID)
 	}
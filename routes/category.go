@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"asset_backend/controllers"
+	"asset_backend/db"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func categoryRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware, mongoDB *db.MongoDB) {
+	categoryController := controllers.NewCategoryController(mongoDB)
+
+	category := router.Group("/category").Use(jwtToken.MiddlewareFunc())
+	{
+		category.GET("", categoryController.GetCategoriesByUserID)
+		category.POST("", categoryController.CreateCategory)
+		category.PUT("", categoryController.UpdateCategory)
+		category.PUT("/archive", categoryController.ArchiveCategory)
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"asset_backend/controllers"
 	"asset_backend/db"
+	"asset_backend/entitlements"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
@@ -15,7 +16,7 @@ func favouriteInvestingRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddl
 	{
 		favInvesting.DELETE("/all", favInvestingController.DeleteAllFavouriteInvestingsByUserID)
 		favInvesting.DELETE("", favInvestingController.DeleteFavouriteInvestingByID)
-		favInvesting.POST("", favInvestingController.CreateFavouriteInvesting)
+		favInvesting.POST("", entitlements.QuotaMiddleware(mongoDB, entitlements.ResourceWatchlist), favInvestingController.CreateFavouriteInvesting)
 		favInvesting.PUT("", favInvestingController.UpdateFavouriteInvestingOrder)
 		favInvesting.GET("", favInvestingController.GetFavouriteInvestings)
 	}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"asset_backend/controllers"
+	"asset_backend/db"
+	"asset_backend/entitlements"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func priceAlertRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware, mongoDB *db.MongoDB) {
+	priceAlertController := controllers.NewPriceAlertController(mongoDB)
+
+	priceAlert := router.Group("/price-alert").Use(jwtToken.MiddlewareFunc())
+	{
+		priceAlert.GET("", priceAlertController.GetPriceAlerts)
+		priceAlert.POST("", entitlements.QuotaMiddleware(mongoDB, entitlements.ResourcePriceAlert), priceAlertController.CreatePriceAlert)
+		priceAlert.PUT("", priceAlertController.UpdatePriceAlert)
+		priceAlert.DELETE("", priceAlertController.DeletePriceAlertByID)
+	}
+}
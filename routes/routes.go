@@ -17,9 +17,13 @@ func SetupRoutes(router *gin.Engine, jwtToken *jwt.GinJWTMiddleware, mongoDB *db
 	cardRouter(apiRouter, jwtToken, mongoDB)
 	bankAccountRouter(apiRouter, jwtToken, mongoDB)
 	transactionRouter(apiRouter, jwtToken, mongoDB)
+	budgetRouter(apiRouter, jwtToken, mongoDB)
+	categoryRouter(apiRouter, jwtToken, mongoDB)
 	oauth2Router(apiRouter, jwtToken, mongoDB)
 	logRouter(apiRouter, jwtToken, mongoDB)
 	favouriteInvestingRouter(apiRouter, jwtToken, mongoDB)
+	priceAlertRouter(apiRouter, jwtToken, mongoDB)
+	adminRouter(apiRouter, mongoDB)
 
 	router.GET("/privacy", privacyPolicy)
 	router.GET("/terms", termsConditions)
@@ -3,6 +3,8 @@ package routes
 import (
 	"asset_backend/controllers"
 	"asset_backend/db"
+	"asset_backend/entitlements"
+	"asset_backend/helpers"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
@@ -16,10 +18,11 @@ func subscriptionRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware,
 		subscription.DELETE("/all", subscriptionController.DeleteAllSubscriptionsByUserID)
 		subscription.DELETE("", subscriptionController.DeleteSubscriptionBySubscriptionID)
 		subscription.PUT("", subscriptionController.UpdateSubscription)
-		subscription.POST("", subscriptionController.CreateSubscription)
+		subscription.POST("", entitlements.QuotaMiddleware(mongoDB, entitlements.ResourceSubscription), subscriptionController.CreateSubscription)
 		subscription.GET("/card", subscriptionController.GetSubscriptionsByCardID)
 		subscription.GET("", subscriptionController.GetSubscriptionsAndStatsByUserID)
 		subscription.GET("/details", subscriptionController.GetSubscriptionDetails)
+		subscription.GET("/password", helpers.TwoFactorMiddleware(mongoDB), subscriptionController.GetSubscriptionPassword)
 		subscription.GET("/stats", subscriptionController.GetSubscriptionStatisticsByUserID)
 
 		subscription.POST("/invite", subscriptionController.InviteSubscriptionToUser)
@@ -3,6 +3,7 @@ package routes
 import (
 	"asset_backend/controllers"
 	"asset_backend/db"
+	"asset_backend/entitlements"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
@@ -15,10 +16,16 @@ func transactionRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware,
 	{
 		transaction.DELETE("/all", transactionController.DeleteAllTransactionsByUserID)
 		transaction.DELETE("", transactionController.DeleteTransactionByTransactionID)
-		transaction.POST("", transactionController.CreateTransaction)
+		transaction.POST("", entitlements.QuotaMiddleware(mongoDB, entitlements.ResourceDailyTransaction), transactionController.CreateTransaction)
 		transaction.PUT("", transactionController.UpdateTransaction)
 		transaction.GET("", transactionController.GetTransactionsByUserIDAndFilterSort)
 		transaction.GET("/total", transactionController.GetTotalTransactionByInterval)
 		transaction.GET("/stats", transactionController.GetTransactionStats)
+		transaction.GET("/recurring", transactionController.GetRecurringTransactionsByUserID)
+		transaction.GET("/recurring/preview", transactionController.GetRecurringTransactionPreview)
+		transaction.POST("/recurring", transactionController.CreateRecurringTransaction)
+		transaction.PUT("/recurring", transactionController.UpdateRecurringTransaction)
+		transaction.PUT("/recurring/pause", transactionController.PauseRecurringTransaction)
+		transaction.DELETE("/recurring", transactionController.DeleteRecurringTransactionByID)
 	}
 }
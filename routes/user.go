@@ -3,6 +3,7 @@ package routes
 import (
 	"asset_backend/controllers"
 	"asset_backend/db"
+	"asset_backend/helpers"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
@@ -12,13 +13,17 @@ func userRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware, mongoDB
 	userController := controllers.NewUserController(mongoDB)
 
 	router.GET("/confirm-password-reset", userController.ConfirmPasswordReset)
+	router.GET("/confirm-email", userController.ConfirmEmail)
 
 	auth := router.Group("/auth")
 	{
 		auth.POST("/login", jwtToken.LoginHandler)
 		auth.POST("/register", userController.Register)
-		auth.POST("/logout", jwtToken.LogoutHandler)
+		auth.POST("/logout", userController.Logout(jwtToken))
+		auth.POST("/refresh", userController.RefreshToken(jwtToken))
+		auth.POST("/2fa", userController.VerifyTwoFactorLogin(jwtToken))
 		auth.GET("/confirm-password-reset", userController.ConfirmPasswordReset)
+		auth.GET("/confirm-email", userController.ConfirmEmail)
 	}
 
 	user := router.Group("/user")
@@ -28,12 +33,24 @@ func userRouter(router *gin.RouterGroup, jwtToken *jwt.GinJWTMiddleware, mongoDB
 		user.Use(jwtToken.MiddlewareFunc())
 		{
 			user.GET("/info", userController.GetUserInfo)
-			user.DELETE("", userController.DeleteUser)
-			user.PUT("/change-password", userController.ChangePassword)
+			user.POST("/resend-verification", userController.ResendVerificationEmail)
+			user.GET("/entitlements", userController.GetEntitlements)
+			user.GET("/export", userController.ExportUserData)
+			user.POST("/import", userController.ImportUserData)
+			user.DELETE("", helpers.TwoFactorMiddleware(mongoDB), userController.DeleteUser)
+			user.PUT("/change-password", helpers.TwoFactorMiddleware(mongoDB), userController.ChangePassword)
 			user.PUT("/change-currency", userController.ChangeCurrency)
+			user.PUT("/change-cost-basis", userController.ChangeCostBasisMethod)
 			user.PUT("/change-notification", userController.ChangeNotificationPreference)
 			user.PUT("/update-token", userController.UpdateFCMToken)
 			user.PUT("/membership", userController.ChangeUserMembership)
+			user.GET("/net-worth", userController.GetNetWorth)
+			user.GET("/sessions", userController.GetSessions)
+			user.DELETE("/sessions", userController.DeleteSession)
+			user.POST("/2fa/setup", userController.SetupTwoFactor)
+			user.POST("/2fa/enable", userController.EnableTwoFactor)
+			user.POST("/2fa/recovery-codes", helpers.TwoFactorMiddleware(mongoDB), userController.RegenerateRecoveryCodes)
+			user.DELETE("/2fa", helpers.TwoFactorMiddleware(mongoDB), userController.DisableTwoFactor)
 		}
 	}
 }